@@ -118,9 +118,7 @@ func executeExec(ctx context.Context, dev *model.Dev, args []string) error {
 		return err
 	}
 
-	if dev.Container == "" {
-		dev.Container = p.Spec.Containers[0].Name
-	}
+	container := pods.GetContainerName(p, dev)
 
 	if dev.RemoteModeEnabled() {
 		if dev.RemotePort == 0 {
@@ -142,5 +140,5 @@ func executeExec(ctx context.Context, dev *model.Dev, args []string) error {
 		return ssh.Exec(ctx, dev.Interface, dev.RemotePort, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
 	}
 
-	return exec.Exec(ctx, client, cfg, dev.Namespace, p.Name, dev.Container, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
+	return exec.Exec(ctx, client, cfg, dev.Namespace, p.Name, container, true, os.Stdin, os.Stdout, os.Stderr, wrapped)
 }
@@ -49,7 +49,7 @@ func TestRun(t *testing.T) {
 	}
 
 	if dev.Image.Name != "okteto/golang:1" {
-		t.Errorf("got %s, expected %s", dev.Image, "okteto/golang:1")
+		t.Errorf("got %s, expected %s", dev.Image.Name, "okteto/golang:1")
 	}
 
 	if err := Run("", "", p, "ruby", dir, true); err != nil {
@@ -62,7 +62,7 @@ func TestRun(t *testing.T) {
 	}
 
 	if dev.Image.Name != "okteto/ruby:2" {
-		t.Errorf("got %s, expected %s", dev.Image, "okteto/ruby:2")
+		t.Errorf("got %s, expected %s", dev.Image.Name, "okteto/ruby:2")
 	}
 }
 
@@ -0,0 +1,82 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
+	k8Client "github.com/okteto/okteto/pkg/k8s/client"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+//Original prints the original deployment spec stored by okteto up
+func Original() *cobra.Command {
+	var devPath string
+	var namespace string
+	var k8sContext string
+
+	cmd := &cobra.Command{
+		Use:   "original",
+		Short: "Prints the original spec of a deployment in development mode",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			dev, err := utils.LoadDev(devPath, namespace, k8sContext)
+			if err != nil {
+				return err
+			}
+
+			manifest, err := executeOriginal(ctx, dev)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(string(manifest))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&devPath, "file", "f", utils.DefaultDevManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace where the original command is executed")
+	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the original command is executed")
+	return cmd
+}
+
+func executeOriginal(ctx context.Context, dev *model.Dev) ([]byte, error) {
+	client, _, err := k8Client.GetLocalWithContext(dev.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := deployments.Get(ctx, dev, dev.Namespace, client)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := deployments.GetOriginalYAML(d)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.UserError{
+				E:    fmt.Errorf("deployment '%s' is not in development mode", d.Name),
+				Hint: "Run 'okteto up' to activate your development container",
+			}
+		}
+		return nil, err
+	}
+	return manifest, nil
+}
@@ -183,7 +183,7 @@ func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, okt
 	defer spinner.Stop()
 
 	if d.Annotations[model.OktetoAutoCreateAnnotation] == model.OktetoPushCmd {
-		if err := services.CreateDev(ctx, dev, c); err != nil {
+		if err := services.CreateDev(ctx, dev, false, c); err != nil {
 			return err
 		}
 	}
@@ -191,7 +191,7 @@ func runPush(ctx context.Context, dev *model.Dev, autoDeploy bool, imageTag, okt
 	if !exists {
 		d.Spec.Template.Spec.Containers[0].Image = imageTag
 		deployments.SetLastBuiltAnnotation(d)
-		return deployments.Deploy(ctx, d, true, c)
+		return deployments.Deploy(ctx, d, true, model.DeployStrategyReplace, c)
 	}
 
 	for _, tr := range trList {
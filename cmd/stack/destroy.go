@@ -29,6 +29,7 @@ func Destroy(ctx context.Context) *cobra.Command {
 	var name string
 	var namespace string
 	var rm bool
+	var force bool
 	cmd := &cobra.Command{
 		Use:   "destroy <name>",
 		Short: "Destroys a stack",
@@ -41,7 +42,7 @@ func Destroy(ctx context.Context) *cobra.Command {
 			if err := s.UpdateNamespace(namespace); err != nil {
 				return err
 			}
-			err = stack.Destroy(ctx, s, rm)
+			err = stack.Destroy(ctx, s, rm, force)
 			analytics.TrackDestroyStack(err == nil)
 			if err == nil {
 				log.Success("Successfully destroyed stack '%s'", s.Name)
@@ -53,5 +54,6 @@ func Destroy(ctx context.Context) *cobra.Command {
 	cmd.Flags().StringVarP(&name, "name", "", "", "overwrites the stack name")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "overwrites the stack namespace where the stack is destroyed")
 	cmd.Flags().BoolVarP(&rm, "volumes", "v", false, "remove persistent volumes")
+	cmd.Flags().BoolVarP(&force, "force", "", false, "remove persistent volumes without asking for confirmation")
 	return cmd
 }
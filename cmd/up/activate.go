@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
@@ -11,11 +12,15 @@ import (
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/k8s/namespaces"
+	"github.com/okteto/okteto/pkg/k8s/nodes"
 	"github.com/okteto/okteto/pkg/k8s/pods"
 	"github.com/okteto/okteto/pkg/k8s/secrets"
+	"github.com/okteto/okteto/pkg/k8s/serviceaccounts"
 	"github.com/okteto/okteto/pkg/k8s/services"
 	"github.com/okteto/okteto/pkg/k8s/volumes"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/metrics"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/registry"
 	appsv1 "k8s.io/api/apps/v1"
@@ -23,6 +28,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+//isFirstActivation returns true if the session hasn't activated the development container yet
+func (up *upContext) isFirstActivation() bool {
+	return !up.isRetry && !up.isReactivation
+}
+
 func (up *upContext) activate(autoDeploy, build bool) error {
 	log.Infof("activating development container retry=%t", up.isRetry)
 
@@ -42,6 +52,7 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 	up.CommandResult = make(chan error, 1)
 	up.cleaned = make(chan string, 1)
 	up.hardTerminate = make(chan error, 1)
+	up.activated = false
 
 	d, create, err := up.getCurrentDeployment(ctx, autoDeploy)
 	if err != nil {
@@ -53,6 +64,10 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 		return nil
 	}
 
+	if err := up.applySessionPolicy(ctx, d); err != nil {
+		return err
+	}
+
 	if deployments.IsDevModeOn(d) && deployments.HasBeenChanged(d) {
 		return errors.UserError{
 			E: fmt.Errorf("Deployment '%s' has been modified while your development container was active", d.Name),
@@ -69,20 +84,35 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 		build = true
 	}
 
-	if !up.isRetry && build {
+	if up.isFirstActivation() && build {
 		if err := up.buildDevImage(ctx, d, create); err != nil {
 			return fmt.Errorf("error building dev image: %s", err)
 		}
 	}
 
+	if up.isFirstActivation() {
+		if err := up.pinImageDigests(ctx); err != nil {
+			return err
+		}
+		if warning := up.Dev.SyncOwnershipWarning(); warning != "" {
+			log.Warning(warning)
+		}
+	}
+
 	go up.initializeSyncthing()
 
 	if err := up.setDevContainer(d); err != nil {
 		return err
 	}
 
+	if up.isFirstActivation() {
+		if err := up.checkImageArchitecture(ctx, d); err != nil {
+			return err
+		}
+	}
+
 	if err := up.devMode(ctx, d, create); err != nil {
-		if errors.IsTransient(err) {
+		if errors.IsTransient(err) || err == errors.ErrGPUUnavailable {
 			return err
 		}
 		return fmt.Errorf("couldn't activate your development container\n    %s", err.Error())
@@ -90,11 +120,18 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 
 	up.isRetry = true
 
+	if create && up.Dev.WaitForService > 0 {
+		timeout := time.Duration(up.Dev.WaitForService) * time.Second
+		if err := services.WaitForEndpoints(ctx, up.Dev.Namespace, up.Dev.Name, timeout, up.Client); err != nil {
+			return err
+		}
+	}
+
 	if err := up.forwards(ctx); err != nil {
 		if err == errors.ErrSSHConnectError {
 			err := up.checkOktetoStartError(ctx, "Failed to connect to your development container")
 			if err == errors.ErrLostSyncthing {
-				if err := pods.Destroy(ctx, up.Pod.Name, up.Dev.Namespace, up.Client); err != nil {
+				if err := pods.DestroyWithGracePeriod(ctx, up.Pod.Name, up.Dev.Namespace, up.Dev.RecoveryGracePeriodSeconds(), up.Client); err != nil {
 					return fmt.Errorf("error recreating development container: %s", err.Error())
 				}
 			}
@@ -111,9 +148,24 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 		return err
 	}
 
+	if err := up.runPostSync(ctx, up.execPostSync); err != nil {
+		return err
+	}
+
+	if up.Dev.LogFifo != "" {
+		go up.streamLogFifo(ctx)
+	}
+
+	if len(up.Dev.TailLogs) > 0 {
+		go up.tailLogs(ctx, up.tailLogsSince)
+		up.tailLogsSince = &metav1.Time{Time: time.Now()}
+	}
+
 	up.success = true
+	up.activated = true
 	if up.isRetry {
 		analytics.TrackReconnect(true, up.isSwap)
+		metrics.TrackReconnect()
 	}
 
 	go func() {
@@ -147,9 +199,15 @@ func (up *upContext) activate(autoDeploy, build bool) error {
 	}()
 	prevError := up.waitUntilExitOrInterrupt()
 
+	if prevError != nil {
+		if err := up.checkDevPodTerminated(ctx); err != nil {
+			return err
+		}
+	}
+
 	if up.shouldRetry(ctx, prevError) {
 		if !up.Dev.PersistentVolumeEnabled() {
-			if err := pods.Destroy(ctx, up.Pod.Name, up.Dev.Namespace, up.Client); err != nil {
+			if err := pods.DestroyWithGracePeriod(ctx, up.Pod.Name, up.Dev.Namespace, up.Dev.RecoveryGracePeriodSeconds(), up.Client); err != nil {
 				return err
 			}
 		}
@@ -173,10 +231,18 @@ func (up *upContext) shouldRetry(ctx context.Context, err error) bool {
 }
 
 func (up *upContext) devMode(ctx context.Context, d *appsv1.Deployment, create bool) error {
+	start := time.Now()
 	if err := up.createDevContainer(ctx, d, create); err != nil {
 		return err
 	}
-	return up.waitUntilDevelopmentContainerIsRunning(ctx)
+	metrics.TrackPhase(metrics.ActivatePhase, start)
+
+	start = time.Now()
+	if err := up.waitUntilDevelopmentContainerIsRunning(ctx); err != nil {
+		return err
+	}
+	metrics.TrackPhase(metrics.StartPhase, start)
+	return nil
 }
 
 func (up *upContext) createDevContainer(ctx context.Context, d *appsv1.Deployment, create bool) error {
@@ -188,21 +254,51 @@ func (up *upContext) createDevContainer(ctx context.Context, d *appsv1.Deploymen
 		return err
 	}
 
+	if err := namespaces.CheckNotTerminating(ctx, up.Dev.Namespace, up.Client); err != nil {
+		return err
+	}
+
 	if up.Dev.PersistentVolumeEnabled() {
-		if err := volumes.Create(ctx, up.Dev, up.Client); err != nil {
+		if err := volumes.Create(ctx, up.Dev, up.maxVolumeSize, up.Client); err != nil {
 			return err
 		}
 	}
 
+	if up.Dev.SharedCache != nil {
+		if err := volumes.CreateSharedCache(ctx, up.Dev, up.Client); err != nil {
+			return err
+		}
+	}
+
+	if err := serviceaccounts.CreateDev(ctx, up.Dev, up.Client); err != nil {
+		return err
+	}
+
 	trList, err := deployments.GetTranslations(ctx, up.Dev, d, up.Client)
 	if err != nil {
 		return err
 	}
 
+	var gitAnnotations map[string]string
+	if up.Dev.GitAnnotations {
+		gitAnnotations = deployments.GetGitAnnotations(up.getGitPath())
+	}
+
+	for _, tr := range trList {
+		tr.RecordSteps = up.debugTranslation
+		tr.GitAnnotations = gitAnnotations
+		tr.AllowHostPath = up.allowHostPath
+		tr.PodSecurity = up.podSecurity
+	}
+
 	if err := deployments.TranslateDevMode(trList, up.Client, up.isOktetoNamespace); err != nil {
 		return err
 	}
 
+	if up.debugTranslation {
+		printTranslationSteps(trList)
+	}
+
 	initSyncErr := <-up.hardTerminate
 	if initSyncErr != nil {
 		return initSyncErr
@@ -215,11 +311,11 @@ func (up *upContext) createDevContainer(ctx context.Context, d *appsv1.Deploymen
 
 	for name := range trList {
 		if name == d.Name {
-			if err := deployments.Deploy(ctx, trList[name].Deployment, create, up.Client); err != nil {
+			if err := deployments.Deploy(ctx, trList[name].Deployment, create, up.Dev.DeployStrategy, up.Client); err != nil {
 				return err
 			}
 		} else {
-			if err := deployments.Deploy(ctx, trList[name].Deployment, false, up.Client); err != nil {
+			if err := deployments.Deploy(ctx, trList[name].Deployment, false, up.Dev.DeployStrategy, up.Client); err != nil {
 				return err
 			}
 		}
@@ -235,7 +331,7 @@ func (up *upContext) createDevContainer(ctx context.Context, d *appsv1.Deploymen
 	}
 
 	if create {
-		if err := services.CreateDev(ctx, up.Dev, up.Client); err != nil {
+		if err := services.CreateDev(ctx, up.Dev, true, up.Client); err != nil {
 			return err
 		}
 	}
@@ -245,6 +341,22 @@ func (up *upContext) createDevContainer(ctx context.Context, d *appsv1.Deploymen
 		return err
 	}
 
+	tr := trList[d.Name]
+	if !create && pods.MustBeRecreated(pod, &tr.Deployment.Spec.Template.Spec, up.Dev.GetDevContainerName()) {
+		log.Infof("recreating development container '%s'", pod.Name)
+		pod, err = pods.Recreate(ctx, pod, up.Dev, up.Client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if up.Dev.CleanCutover {
+		if err := pods.WaitUntilReplaced(ctx, pod, d.Spec.Selector.MatchLabels, up.Client); err != nil {
+			return err
+		}
+	}
+
+	up.trackDevRestarts(pod)
 	up.Pod = pod
 	return nil
 }
@@ -262,6 +374,16 @@ func (up *upContext) waitUntilDevelopmentContainerIsRunning(ctx context.Context)
 	spinner.Start()
 	defer spinner.Stop()
 
+	if up.Dev.PersistentVolumeEnabled() {
+		err := volumes.WaitUntilReleased(ctx, up.Dev, up.Pod.Name, up.Client, func(holder string) {
+			spinner.Update(fmt.Sprintf("Waiting for pod '%s' to release the persistent volume...", holder))
+		})
+		if err != nil {
+			return err
+		}
+		spinner.Update(msg)
+	}
+
 	optsWatchPod := metav1.ListOptions{
 		Watch:         true,
 		FieldSelector: fmt.Sprintf("metadata.name=%s", up.Pod.Name),
@@ -300,6 +422,9 @@ func (up *upContext) waitUntilDevelopmentContainerIsRunning(ctx context.Context)
 				if strings.Contains(e.Message, "pod has unbound immediate PersistentVolumeClaims") {
 					continue
 				}
+				if up.Dev.GPUFallback && isGPUUnavailable(e) {
+					return errors.ErrGPUUnavailable
+				}
 				return fmt.Errorf(e.Message)
 			case "SuccessfulAttachVolume":
 				spinner.Stop()
@@ -325,17 +450,137 @@ func (up *upContext) waitUntilDevelopmentContainerIsRunning(ctx context.Context)
 				continue
 			}
 			log.Infof("dev pod %s is now %s", pod.Name, pod.Status.Phase)
-			if pod.Status.Phase == apiv1.PodRunning {
+			running, err := up.checkDevPodStatus(pod)
+			if err != nil {
+				return err
+			}
+			if running {
 				spinner.Stop()
 				log.Success("Images successfully pulled")
 				return nil
 			}
-			if pod.DeletionTimestamp != nil {
-				return errors.ErrDevPodDeleted
-			}
 		case <-ctx.Done():
 			log.Debug("call to waitUntilDevelopmentContainerIsRunning cancelled")
 			return ctx.Err()
 		}
 	}
 }
+
+//isGPUUnavailable returns if a pod event reports that there are no nodes with the GPUs requested by the pod
+func isGPUUnavailable(e *apiv1.Event) bool {
+	if e.Reason != "FailedScheduling" {
+		return false
+	}
+	for _, gpu := range []apiv1.ResourceName{model.ResourceAMDGPU, model.ResourceNVIDIAGPU} {
+		if strings.Contains(e.Message, fmt.Sprintf("Insufficient %s", gpu)) {
+			return true
+		}
+	}
+	return false
+}
+
+//getGitPath returns the local folder used to read the git state of the development container
+func (up *upContext) getGitPath() string {
+	if len(up.Dev.Sync.Folders) > 0 {
+		return up.Dev.Sync.Folders[0].LocalPath
+	}
+	return "."
+}
+
+func printTranslationSteps(trList map[string]*model.Translation) {
+	for name, tr := range trList {
+		log.Information("Translation steps applied to deployment '%s':", name)
+		for _, s := range tr.Steps {
+			log.Println(fmt.Sprintf("    - %s", s))
+		}
+	}
+}
+
+//trackDevRestarts returns the restarts of the dev container when this session first saw the pod,
+//so failures from previous sessions are not reported
+func (up *upContext) trackDevRestarts(pod *apiv1.Pod) int32 {
+	if up.devRestarts == nil {
+		up.devRestarts = map[string]int32{}
+	}
+	if restarts, ok := up.devRestarts[pod.Name]; ok {
+		return restarts
+	}
+	restarts := pods.GetRestarts(pod, up.Dev.GetDevContainerName())
+	up.devRestarts[pod.Name] = restarts
+	return restarts
+}
+
+//checkDevPodStatus returns if the dev pod is running, or an error if the dev pod won't run
+func (up *upContext) checkDevPodStatus(pod *apiv1.Pod) (bool, error) {
+	if err := pods.GetOOMKilledError(pod, up.Dev.GetDevContainerName(), up.trackDevRestarts(pod)); err != nil {
+		return false, err
+	}
+	if err := pods.GetCrashLoopError(pod, up.Dev.GetDevContainerName(), up.trackDevRestarts(pod), pods.GetCrashLoopThreshold()); err != nil {
+		return false, err
+	}
+	if pod.DeletionTimestamp != nil {
+		return false, errors.ErrDevPodDeleted
+	}
+	if pod.Status.Phase != apiv1.PodRunning {
+		return false, nil
+	}
+	if !pods.IsContainerRunning(pod, up.Dev.GetDevContainerName()) {
+		log.Infof("dev pod %s is running but container '%s' is not running yet", pod.Name, up.Dev.GetDevContainerName())
+		return false, nil
+	}
+	return true, nil
+}
+
+//checkImageArchitecture fails if the dev image can't run on the architecture of the nodes of the deployment
+func (up *upContext) checkImageArchitecture(ctx context.Context, d *appsv1.Deployment) error {
+	if up.Dev.Image.Name == "" {
+		return nil
+	}
+	arch, err := registry.GetImageArchitecture(ctx, up.Dev.Namespace, up.Dev.Image.Name)
+	if err != nil {
+		log.Infof("error getting the architecture of image '%s': %s", up.Dev.Image.Name, err.Error())
+		return nil
+	}
+	if arch == "" {
+		return nil
+	}
+	nodeArchs, err := nodes.GetArchitectures(ctx, d.Spec.Template.Spec.NodeSelector, up.Client)
+	if err != nil {
+		log.Infof("error getting the architecture of the nodes: %s", err.Error())
+		return nil
+	}
+	return validateImageArchitecture(up.Dev.Image.Name, arch, nodeArchs)
+}
+
+func validateImageArchitecture(image, arch string, nodeArchs []string) error {
+	if len(nodeArchs) == 0 {
+		return nil
+	}
+	for _, nodeArch := range nodeArchs {
+		if nodeArch == arch {
+			return nil
+		}
+	}
+	return errors.UserError{
+		E:    fmt.Errorf("image '%s' is built for '%s' but the nodes of your deployment run '%s'", image, arch, strings.Join(nodeArchs, "', '")),
+		Hint: fmt.Sprintf("Build your image for the 'linux/%s' platform or update the 'image' of your okteto manifest", nodeArchs[0]),
+	}
+}
+
+func (up *upContext) pinImageDigests(ctx context.Context) error {
+	devs := append([]*model.Dev{up.Dev}, up.Dev.Services...)
+	for _, dev := range devs {
+		if !dev.PinDigest || dev.EmptyImage || dev.Image == nil || dev.Image.Name == "" {
+			continue
+		}
+		digest, err := registry.GetImageDigest(ctx, up.Dev.Namespace, dev.Image.Name)
+		if err != nil {
+			return fmt.Errorf("error resolving the digest of image '%s': %s", dev.Image.Name, err)
+		}
+		if digest == "" {
+			log.Infof("image '%s' can't be pinned to a digest", dev.Image.Name)
+		}
+		dev.ImageDigest = digest
+	}
+	return nil
+}
@@ -12,14 +12,21 @@ import (
 	"github.com/okteto/okteto/pkg/k8s/exec"
 	"github.com/okteto/okteto/pkg/k8s/pods"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/ssh"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (up *upContext) cleanCommand(ctx context.Context) {
 	in := strings.NewReader("\n")
 	var out bytes.Buffer
 
-	cmd := "cat /var/okteto/bin/version.txt; cat /proc/sys/fs/inotify/max_user_watches; /var/okteto/bin/clean >/dev/null 2>&1"
+	binPath := up.Dev.BinPath
+	if binPath == "" {
+		binPath = model.OktetoBinMountPath
+	}
+	cmd := fmt.Sprintf("cat %s/version.txt; cat /proc/sys/fs/inotify/max_user_watches; %s/clean >/dev/null 2>&1", binPath, binPath)
 
 	err := exec.Exec(
 		ctx,
@@ -27,7 +34,7 @@ func (up *upContext) cleanCommand(ctx context.Context) {
 		up.RestConfig,
 		up.Dev.Namespace,
 		up.Pod.Name,
-		up.Dev.Container,
+		up.Dev.GetDevContainerName(),
 		false,
 		in,
 		&out,
@@ -58,7 +65,7 @@ func (up *upContext) runCommand(ctx context.Context) error {
 		up.RestConfig,
 		up.Dev.Namespace,
 		up.Pod.Name,
-		up.Dev.Container,
+		up.Dev.GetDevContainerName(),
 		true,
 		os.Stdin,
 		os.Stdout,
@@ -67,6 +74,84 @@ func (up *upContext) runCommand(ctx context.Context) error {
 	)
 }
 
+//runPostSync runs the post-sync command the first time files are synchronized
+func (up *upContext) runPostSync(ctx context.Context, run func(context.Context, []string) error) error {
+	if up.postSyncDone || len(up.Dev.PostSync.Values) == 0 {
+		return nil
+	}
+
+	log.Information("Running post-sync command: %s", strings.Join(up.Dev.PostSync.Values, " "))
+	if err := run(ctx, up.Dev.PostSync.Values); err != nil {
+		return errors.UserError{
+			E:    fmt.Errorf("post-sync command failed: %s", err),
+			Hint: "Fix the 'postSync' command in your okteto manifest and run 'okteto up' again",
+		}
+	}
+	up.postSyncDone = true
+	return nil
+}
+
+func (up *upContext) execPostSync(ctx context.Context, command []string) error {
+	in := strings.NewReader("")
+	if up.Dev.RemoteModeEnabled() {
+		return ssh.Exec(ctx, up.Dev.Interface, up.Dev.RemotePort, false, in, os.Stdout, os.Stderr, command)
+	}
+
+	return exec.Exec(
+		ctx,
+		up.Client,
+		up.RestConfig,
+		up.Dev.Namespace,
+		up.Pod.Name,
+		up.Dev.GetDevContainerName(),
+		false,
+		in,
+		os.Stdout,
+		os.Stderr,
+		command,
+	)
+}
+
+func (up *upContext) streamLogFifo(ctx context.Context) {
+	if err := pods.StreamLogs(ctx, up.Pod, model.OktetoLogTailContainer, os.Stdout, up.Client); err != nil {
+		log.Infof("failed to stream log fifo: %s", err)
+	}
+}
+
+//tailLogs streams the logs of the containers listed in 'tailLogs' while the dev command runs
+func (up *upContext) tailLogs(ctx context.Context, since *metav1.Time) {
+	containers := []string{}
+	for _, name := range up.Dev.TailLogs {
+		if !hasContainer(up.Pod, name) {
+			log.Warning("Container '%s' in 'tailLogs' not found in your development container", name)
+			continue
+		}
+		containers = append(containers, name)
+	}
+	if err := pods.TailLogs(ctx, up.Pod, containers, since, os.Stdout, up.Client); err != nil {
+		log.Infof("failed to tail container logs: %s", err)
+	}
+}
+
+func hasContainer(pod *apiv1.Pod, name string) bool {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+//checkDevPodTerminated returns an error if the dev pod was stopped by kubernetes
+func (up *upContext) checkDevPodTerminated(ctx context.Context) error {
+	pod, err := up.Client.CoreV1().Pods(up.Dev.Namespace).Get(ctx, up.Pod.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Infof("failed to get development container status: %s", err)
+		return nil
+	}
+	return pods.GetOOMKilledError(pod, up.Dev.GetDevContainerName(), up.trackDevRestarts(pod))
+}
+
 func (up *upContext) checkOktetoStartError(ctx context.Context, msg string) error {
 	userID := pods.GetDevPodUserID(ctx, up.Dev, up.Client)
 	if up.Dev.PersistentVolumeEnabled() {
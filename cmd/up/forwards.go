@@ -3,6 +3,7 @@ package up
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/k8s/forward"
@@ -21,6 +22,10 @@ func (up *upContext) forwards(ctx context.Context) error {
 		return up.sshForwards(ctx)
 	}
 
+	if len(up.Dev.Sockets) > 0 {
+		return fmt.Errorf("the field 'sockets' requires remote mode to be enabled")
+	}
+
 	log.Infof("starting port forwards")
 	up.Forwarder = forward.NewPortForwardManager(ctx, up.Dev.Interface, up.RestConfig, up.Client, up.Dev.Namespace)
 
@@ -56,7 +61,7 @@ func (up *upContext) sshForwards(ctx context.Context) error {
 		return err
 	}
 
-	up.Forwarder = ssh.NewForwardManager(ctx, fmt.Sprintf(":%d", up.Dev.RemotePort), up.Dev.Interface, "0.0.0.0", f, up.Dev.Namespace)
+	up.Forwarder = ssh.NewForwardManager(ctx, fmt.Sprintf(":%d", up.Dev.RemotePort), up.Dev.Interface, "0.0.0.0", f, up.Dev.Namespace, time.Duration(up.Dev.SSHKeepAliveInterval)*time.Second, up.Dev.ForwardConcurrency)
 
 	if err := up.Forwarder.Add(model.Forward{Local: up.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
 		return err
@@ -86,6 +91,12 @@ func (up *upContext) sshForwards(ctx context.Context) error {
 		}
 	}
 
+	for _, s := range up.Dev.Sockets {
+		if err := up.Forwarder.AddSocket(s); err != nil {
+			return err
+		}
+	}
+
 	if err := ssh.AddEntry(up.Dev.Name, up.Dev.Interface, up.Dev.RemotePort); err != nil {
 		log.Infof("failed to add entry to your SSH config file: %s", err)
 		return fmt.Errorf("failed to add entry to your SSH config file")
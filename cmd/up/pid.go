@@ -15,9 +15,12 @@ package up
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/log"
@@ -37,9 +40,39 @@ func createPIDFile(ns, dpName string) error {
 	return nil
 }
 
-// cleanPIDFile deletes PID file after Up finishes
+// getPIDFromFile returns the PID stored in the PID file, or 0 if there is none
+func getPIDFromFile(ns, dpName string) int {
+	filePath := filepath.Join(config.GetDeploymentHome(ns, dpName), "okteto.pid")
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		log.Infof("invalid PID file at %s: %s", filePath, err)
+		return 0
+	}
+	return pid
+}
+
+// getPIDFileTime returns the last time the PID file was written, or the zero time if there is none
+func getPIDFileTime(ns, dpName string) time.Time {
+	filePath := filepath.Join(config.GetDeploymentHome(ns, dpName), "okteto.pid")
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// cleanPIDFile deletes PID file after Up finishes.
+// The PID file is kept if it belongs to another session that took over the development container
 func cleanPIDFile(ns, dpName string) {
 	filePath := filepath.Join(config.GetDeploymentHome(ns, dpName), "okteto.pid")
+	if pid := getPIDFromFile(ns, dpName); pid != 0 && pid != os.Getpid() {
+		log.Infof("PID file at %s belongs to session %d", filePath, pid)
+		return
+	}
 	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 		log.Infof("unable to delete PID file at %s", filePath)
 	}
@@ -49,3 +49,18 @@ func TestCreatePIDFile(t *testing.T) {
 	}
 
 }
+
+func TestCleanPIDFileOfAnotherSession(t *testing.T) {
+	deploymentName := "deployment"
+	namespace := "namespace"
+	filePath := filepath.Join(config.GetDeploymentHome(namespace, deploymentName), "okteto.pid")
+	if err := ioutil.WriteFile(filePath, []byte(strconv.Itoa(os.Getpid()+1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filePath)
+
+	cleanPIDFile(namespace, deploymentName)
+	if _, err := os.Stat(filePath); err != nil {
+		t.Fatalf("deleted the pid file of another session: %s", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/pods"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/shirou/gopsutil/process"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	// sessionPolicyAttach reuses the development container of the active session
+	sessionPolicyAttach = "attach"
+
+	// sessionPolicyError fails if there is an active session
+	sessionPolicyError = "error"
+
+	// sessionPolicyRestart terminates the active session and recreates the development container
+	sessionPolicyRestart = "restart"
+
+	// sessionPolicyTakeover terminates the active session and reuses its development container
+	sessionPolicyTakeover = "takeover"
+)
+
+func validateSessionPolicy(policy string) error {
+	switch policy {
+	case sessionPolicyAttach, sessionPolicyError, sessionPolicyRestart, sessionPolicyTakeover:
+		return nil
+	}
+	return fmt.Errorf("supported values for '--session-policy' are: '%s', '%s', '%s' or '%s'", sessionPolicyAttach, sessionPolicyError, sessionPolicyRestart, sessionPolicyTakeover)
+}
+
+// getSessionActions returns if the active session must be terminated and if the development container must be recreated.
+// sameSession is true when the development container was activated by the current session
+func getSessionActions(policy string, d *appsv1.Deployment, sameSession bool, name string) (terminate, recreate bool, err error) {
+	if sameSession || d == nil || !deployments.IsDevModeOn(d) {
+		return false, false, nil
+	}
+
+	switch policy {
+	case sessionPolicyError:
+		return false, false, errors.UserError{
+			E:    fmt.Errorf("development container '%s' is already active", name),
+			Hint: "Run 'okteto down' first, or use '--session-policy' to attach to, restart or take over the active session",
+		}
+	case sessionPolicyRestart:
+		return true, true, nil
+	case sessionPolicyTakeover:
+		return true, false, nil
+	}
+	return false, false, nil
+}
+
+func (up *upContext) applySessionPolicy(ctx context.Context, d *appsv1.Deployment) error {
+	terminate, recreate, err := getSessionActions(up.sessionPolicy, d, !up.isFirstActivation(), up.Dev.Name)
+	if err != nil {
+		return err
+	}
+
+	if terminate {
+		if err := terminateSession(up.activeSessionPID, up.activeSessionTime, up.Dev.Name); err != nil {
+			return fmt.Errorf("couldn't terminate the active session: %s", err)
+		}
+	}
+
+	if recreate {
+		pod, err := pods.GetDevPod(ctx, up.Dev, up.Client, false)
+		if err != nil {
+			return err
+		}
+		if pod != nil {
+			log.Infof("recreating development container '%s'", pod.Name)
+			if err := pods.Destroy(ctx, pod.Name, pod.Namespace, up.Client); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// terminateSession interrupts the 'okteto up' process of another session so it shuts down cleanly.
+// The process is only signaled if it is the one that wrote the PID file at pidFileTime, to not signal a reused PID
+func terminateSession(pid int, pidFileTime time.Time, devName string) error {
+	if pid == 0 {
+		log.Info("no local session to terminate")
+		return nil
+	}
+
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		log.Infof("session process %d is not running: %s", pid, err)
+		return nil
+	}
+
+	name, err := p.Name()
+	if err != nil || !strings.Contains(name, "okteto") {
+		log.Infof("process %d is not an okteto session", pid)
+		return nil
+	}
+
+	if !isSessionProcess(p, pidFileTime, devName) {
+		log.Infof("process %d doesn't belong to the session of '%s'", pid, devName)
+		return nil
+	}
+
+	if err := p.SendSignal(syscall.SIGINT); err != nil {
+		log.Infof("failed to interrupt session process %d, terminating it: %s", pid, err)
+		if err := p.Terminate(); err != nil {
+			return err
+		}
+	}
+
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for i := 0; i < 100; i++ {
+		isRunning, err := p.IsRunning()
+		if err != nil || !isRunning {
+			log.Infof("session process %d terminated", pid)
+			return nil
+		}
+		<-tick.C
+	}
+
+	return p.Kill()
+}
+
+// isSessionProcess returns if the process command line refers to the development container,
+// or if the process was already running when the PID file was written
+func isSessionProcess(p *process.Process, pidFileTime time.Time, devName string) bool {
+	if cmdline, err := p.CmdlineSlice(); err == nil && len(cmdline) > 1 {
+		for _, arg := range cmdline[1:] {
+			if arg == devName {
+				return true
+			}
+		}
+	}
+
+	created, err := p.CreateTime()
+	if err != nil || pidFileTime.IsZero() {
+		return false
+	}
+	// the process create time has a precision of one second
+	return !time.Unix(0, created*int64(time.Millisecond)).After(pidFileTime.Add(time.Second))
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/errors"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getSessionActions(t *testing.T) {
+	active := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{okLabels.DevLabel: "true"},
+		},
+	}
+	inactive := &appsv1.Deployment{}
+
+	var tests = []struct {
+		name              string
+		policy            string
+		d                 *appsv1.Deployment
+		isRetry           bool
+		expectedTerminate bool
+		expectedRecreate  bool
+		expectErr         bool
+	}{
+		{name: "attach", policy: sessionPolicyAttach, d: active},
+		{name: "error", policy: sessionPolicyError, d: active, expectErr: true},
+		{name: "restart", policy: sessionPolicyRestart, d: active, expectedTerminate: true, expectedRecreate: true},
+		{name: "takeover", policy: sessionPolicyTakeover, d: active, expectedTerminate: true},
+		{name: "error-not-active", policy: sessionPolicyError, d: inactive},
+		{name: "restart-not-active", policy: sessionPolicyRestart, d: inactive},
+		{name: "error-no-deployment", policy: sessionPolicyError},
+		{name: "error-on-retry", policy: sessionPolicyError, d: active, isRetry: true},
+		{name: "restart-on-retry", policy: sessionPolicyRestart, d: active, isRetry: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminate, recreate, err := getSessionActions(tt.policy, tt.d, tt.isRetry, "dev")
+			if tt.expectErr {
+				if _, ok := err.(errors.UserError); !ok {
+					t.Fatalf("expected user error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if terminate != tt.expectedTerminate {
+				t.Errorf("expected terminate %t, got %t", tt.expectedTerminate, terminate)
+			}
+			if recreate != tt.expectedRecreate {
+				t.Errorf("expected recreate %t, got %t", tt.expectedRecreate, recreate)
+			}
+		})
+	}
+}
+
+func Test_applySessionPolicyReactivation(t *testing.T) {
+	active := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{okLabels.DevLabel: "true"},
+		},
+	}
+	up := &upContext{
+		Dev:            &model.Dev{Name: "dev"},
+		sessionPolicy:  sessionPolicyError,
+		isReactivation: true,
+	}
+	if err := up.applySessionPolicy(context.Background(), active); err != nil {
+		t.Fatalf("session policy was applied to the development container activated by the session: %s", err)
+	}
+}
+
+func Test_validateSessionPolicy(t *testing.T) {
+	for _, policy := range []string{sessionPolicyAttach, sessionPolicyError, sessionPolicyRestart, sessionPolicyTakeover} {
+		if err := validateSessionPolicy(policy); err != nil {
+			t.Errorf("policy '%s' failed: %s", policy, err)
+		}
+	}
+	if err := validateSessionPolicy("detach"); err == nil {
+		t.Errorf("invalid policy didn't fail")
+	}
+}
+
+func Test_terminateSession(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not available")
+	}
+	content, err := ioutil.ReadFile(sleep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "okteto")
+	if err := ioutil.WriteFile(bin, content, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bin, "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	if err := terminateSession(cmd.Process.Pid, time.Now().Add(-time.Hour), "dev"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-exited:
+		t.Fatal("process started after the PID file was written was terminated")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if err := terminateSession(cmd.Process.Pid, time.Now(), "dev"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session process was not terminated")
+	}
+
+	if err := terminateSession(0, time.Time{}, "dev"); err != nil {
+		t.Errorf("terminating a missing session failed: %s", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/pods"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/metrics"
 	"github.com/okteto/okteto/pkg/syncthing"
 )
 
@@ -49,6 +50,10 @@ func (up *upContext) sync(ctx context.Context) error {
 	}
 
 	log.Success("Files synchronized")
+	metrics.TrackPhase(metrics.SyncPhase, start)
+	if up.metricsAddress != "" {
+		up.trackSyncedBytes(ctx)
+	}
 
 	elapsed := time.Since(start)
 	maxDuration := time.Duration(1) * time.Minute
@@ -73,6 +78,17 @@ func (up *upContext) sync(ctx context.Context) error {
 	return up.Sy.Restart(ctx)
 }
 
+//trackSyncedBytes tracks the bytes transferred by the initial file synchronization.
+//The local syncthing is started on every activation, so its totals only include the bytes of the current one
+func (up *upContext) trackSyncedBytes(ctx context.Context) {
+	bytes, err := up.Sy.GetTransferredBytes(ctx, true)
+	if err != nil {
+		log.Infof("failed to get the synchronized bytes: %s", err)
+		return
+	}
+	metrics.TrackSyncedBytes(bytes)
+}
+
 func (up *upContext) startSyncthing(ctx context.Context) error {
 	spinner := utils.NewSpinner("Starting the file synchronization service...")
 	spinner.Start()
@@ -93,13 +109,17 @@ func (up *upContext) startSyncthing(ctx context.Context) error {
 		log.Infof("failed to ping syncthing: %s", err.Error())
 		err = up.checkOktetoStartError(ctx, "Failed to connect to the synchronization service")
 		if err == errors.ErrLostSyncthing {
-			if err := pods.Destroy(ctx, up.Pod.Name, up.Dev.Namespace, up.Client); err != nil {
+			if err := pods.DestroyWithGracePeriod(ctx, up.Pod.Name, up.Dev.Namespace, up.Dev.RecoveryGracePeriodSeconds(), up.Client); err != nil {
 				return fmt.Errorf("error recreating development container: %s", err.Error())
 			}
 		}
 		return err
 	}
 
+	if err := up.Sy.CheckVersions(ctx); err != nil {
+		return err
+	}
+
 	if up.resetSyncthing {
 		spinner.Update("Resetting synchronization service database...")
 		if err := up.Sy.ResetDatabase(ctx, up.Dev); err != nil {
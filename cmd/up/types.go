@@ -15,11 +15,13 @@ package up
 
 import (
 	"context"
+	"time"
 
 	"github.com/docker/docker/pkg/term"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/syncthing"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -30,8 +32,12 @@ type upContext struct {
 	ShutdownCompleted chan bool
 	Dev               *model.Dev
 	isOktetoNamespace bool
+	allowHostPath     bool
+	maxVolumeSize     string
+	podSecurity       string
 	isSwap            bool
 	isRetry           bool
+	isReactivation    bool
 	Client            *kubernetes.Clientset
 	RestConfig        *rest.Config
 	Pod               *apiv1.Pod
@@ -43,16 +49,27 @@ type upContext struct {
 	cleaned           chan string
 	hardTerminate     chan error
 	success           bool
+	postSyncDone      bool
+	activated         bool
+	maxRetries        int
+	metricsAddress    string
+	debugTranslation  bool
+	sessionPolicy     string
+	activeSessionPID  int
+	activeSessionTime time.Time
 	resetSyncthing    bool
 	inFd              uintptr
 	isTerm            bool
 	stateTerm         *term.State
+	tailLogsSince     *metav1.Time
+	devRestarts       map[string]int32
 }
 
 // Forwarder is an interface for the port-forwarding features
 type forwarder interface {
 	Add(model.Forward) error
 	AddReverse(model.Reverse) error
+	AddSocket(model.SocketForward) error
 	Start(string, string) error
 	Stop()
 	TransformLabelsToServiceName(model.Forward) (model.Forward, error)
@@ -33,6 +33,7 @@ import (
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/namespaces"
 	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/metrics"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/registry"
@@ -42,11 +43,15 @@ import (
 
 	"github.com/spf13/cobra"
 	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
 )
 
 // ReconnectingMessage is the message shown when we are trying to reconnect
 const ReconnectingMessage = "Trying to reconnect to your cluster. File synchronization will automatically resume when the connection improves."
 
+//maxActivationBackoff is the maximum time to wait between activations after a transient error
+const maxActivationBackoff = 30 * time.Second
+
 //Up starts a development container
 func Up() *cobra.Command {
 	var devPath string
@@ -57,6 +62,10 @@ func Up() *cobra.Command {
 	var build bool
 	var forcePull bool
 	var resetSyncthing bool
+	var sessionPolicy string
+	var maxRetries int
+	var metricsAddress string
+	var debugTranslation bool
 	cmd := &cobra.Command{
 		Use:   "up",
 		Short: "Activates your development container",
@@ -66,6 +75,14 @@ func Up() *cobra.Command {
 				return errors.ErrNotInDevContainer
 			}
 
+			if err := validateSessionPolicy(sessionPolicy); err != nil {
+				return err
+			}
+
+			if maxRetries < 0 {
+				return fmt.Errorf("'--max-retries' must be >= 0")
+			}
+
 			u := upgradeAvailable()
 			if len(u) > 0 {
 				warningFolder := filepath.Join(config.GetOktetoHome(), ".warnings")
@@ -122,9 +139,13 @@ More information is available here: https://okteto.com/docs/reference/cli#up`)
 			}
 
 			up := &upContext{
-				Dev:            dev,
-				Exit:           make(chan error, 1),
-				resetSyncthing: resetSyncthing,
+				Dev:              dev,
+				Exit:             make(chan error, 1),
+				resetSyncthing:   resetSyncthing,
+				sessionPolicy:    sessionPolicy,
+				maxRetries:       maxRetries,
+				metricsAddress:   metricsAddress,
+				debugTranslation: debugTranslation,
 			}
 			up.inFd, up.isTerm = term.GetFdInfo(os.Stdin)
 			if up.isTerm {
@@ -150,6 +171,10 @@ More information is available here: https://okteto.com/docs/reference/cli#up`)
 	cmd.Flags().BoolVarP(&build, "build", "", false, "build on-the-fly the dev image using the info provided by the 'build' okteto manifest field")
 	cmd.Flags().BoolVarP(&forcePull, "pull", "", false, "force dev image pull")
 	cmd.Flags().BoolVarP(&resetSyncthing, "reset", "", false, "reset the file synchronization database")
+	cmd.Flags().IntVarP(&maxRetries, "max-retries", "", 0, "maximum number of consecutive reconnection attempts (0 for unlimited)")
+	cmd.Flags().StringVarP(&metricsAddress, "metrics-address", "", "", "address where the session metrics are exposed for Prometheus (e.g. localhost:9090)")
+	cmd.Flags().BoolVarP(&debugTranslation, "debug-translation", "", false, "print the translation steps applied to the deployments of the development container")
+	cmd.Flags().StringVarP(&sessionPolicy, "session-policy", "", sessionPolicyAttach, "what to do if the development container is already active (attach, error, restart, takeover)")
 	return cmd
 }
 
@@ -227,6 +252,22 @@ func (up *upContext) start(autoDeploy, build bool) error {
 	}
 
 	up.isOktetoNamespace = namespaces.IsOktetoNamespace(ns)
+	up.allowHostPath = namespaces.IsHostPathAllowed(ns)
+	up.maxVolumeSize = namespaces.GetMaxVolumeSize(ns)
+	up.podSecurity = namespaces.GetPodSecurityLevel(ns)
+
+	if up.Dev.SecurityContext != nil && up.Dev.SecurityContext.LocalUser {
+		if uid := int64(os.Getuid()); uid < 0 {
+			log.Warning("'securityContext.localUser' is not supported on this operating system")
+		} else if warning := namespaces.GetUserPolicyWarning(ns, uid); warning != "" {
+			log.Warning(warning)
+		}
+	}
+
+	if pid := getPIDFromFile(up.Dev.Namespace, up.Dev.Name); pid != os.Getpid() {
+		up.activeSessionPID = pid
+		up.activeSessionTime = getPIDFileTime(up.Dev.Namespace, up.Dev.Name)
+	}
 
 	if err := createPIDFile(up.Dev.Namespace, up.Dev.Name); err != nil {
 		log.Infof("failed to create pid file for %s - %s: %s", up.Dev.Namespace, up.Dev.Name, err)
@@ -235,6 +276,14 @@ func (up *upContext) start(autoDeploy, build bool) error {
 
 	defer cleanPIDFile(up.Dev.Namespace, up.Dev.Name)
 
+	if up.metricsAddress != "" {
+		metricsCtx, stopMetrics := context.WithCancel(ctx)
+		defer stopMetrics()
+		if err := metrics.Serve(metricsCtx, up.metricsAddress); err != nil {
+			return fmt.Errorf("couldn't expose the metrics on '%s': %s", up.metricsAddress, err)
+		}
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 
@@ -259,9 +308,8 @@ func (up *upContext) start(autoDeploy, build bool) error {
 // activateLoop activates the development container in a retry loop
 func (up *upContext) activateLoop(autoDeploy, build bool) {
 	isTransientError := false
-	t := time.NewTicker(1 * time.Second)
+	retries := &activationRetries{max: up.maxRetries}
 	iter := 0
-	defer t.Stop()
 
 	defer config.DeleteStateFile(up.Dev)
 
@@ -275,13 +323,23 @@ func (up *upContext) activateLoop(autoDeploy, build bool) {
 			iter++
 			iter = iter % 10
 			if isTransientError {
-				<-t.C
+				time.Sleep(retries.backoff())
 			}
 		}
 		err := up.activate(autoDeploy, build)
+		if up.activated {
+			retries.reset()
+		}
 		if err != nil {
 			log.Infof("activate failed with: %s", err)
 
+			if err == errors.ErrLostSyncthing || errors.IsTransient(err) {
+				if retryErr := retries.failed(err); retryErr != nil {
+					up.Exit <- retryErr
+					return
+				}
+			}
+
 			if err == errors.ErrLostSyncthing {
 				isTransientError = false
 				iter = 0
@@ -293,6 +351,14 @@ func (up *upContext) activateLoop(autoDeploy, build bool) {
 				continue
 			}
 
+			if err == errors.ErrGPUUnavailable && !up.Dev.GPUDisabled {
+				log.Yellow("There are no GPUs available for your development container, falling back to CPU")
+				up.Dev.GPUDisabled = true
+				up.isReactivation = true
+				build = false
+				continue
+			}
+
 			up.Exit <- err
 			return
 		}
@@ -301,6 +367,63 @@ func (up *upContext) activateLoop(autoDeploy, build bool) {
 	}
 }
 
+//activationRetries tracks the consecutive failures of the activation loop
+type activationRetries struct {
+	max    int
+	errors []error
+}
+
+//failed records a failed activation and returns an error if there are no retries left.
+//Failures are not recorded when retries are unlimited
+func (r *activationRetries) failed(err error) error {
+	if r.max <= 0 {
+		return nil
+	}
+	r.errors = append(r.errors, err)
+	if len(r.errors) <= r.max {
+		return nil
+	}
+
+	messages := []string{}
+	for _, e := range r.errors {
+		if !contains(messages, e.Error()) {
+			messages = append(messages, e.Error())
+		}
+	}
+	return errors.UserError{
+		E:    fmt.Errorf("your development container failed to activate %d consecutive times: %s", len(r.errors), strings.Join(messages, "; ")),
+		Hint: "Check the status of your cluster and run 'okteto up' again",
+	}
+}
+
+//backoff returns the time to wait before the next activation, it backs off exponentially only when retries are limited
+func (r *activationRetries) backoff() time.Duration {
+	wait := time.Second
+	if r.max <= 0 {
+		return wait
+	}
+	for i := 1; i < len(r.errors) && wait < maxActivationBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxActivationBackoff {
+		return maxActivationBackoff
+	}
+	return wait
+}
+
+func (r *activationRetries) reset() {
+	r.errors = nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (up *upContext) getCurrentDeployment(ctx context.Context, autoDeploy bool) (*appsv1.Deployment, bool, error) {
 	d, err := deployments.Get(ctx, up.Dev, up.Dev.Namespace, up.Client)
 	if err == nil {
@@ -323,14 +446,19 @@ func (up *upContext) getCurrentDeployment(ctx context.Context, autoDeploy bool)
 		return nil, false, err
 	}
 
-	if !up.Dev.Autocreate {
-		err = errors.UserError{
+	return up.deploymentNotFound(autoDeploy)
+}
+
+// deploymentNotFound returns the sandbox deployment if autocreate is enabled, or an actionable error otherwise
+func (up *upContext) deploymentNotFound(autoDeploy bool) (*appsv1.Deployment, bool, error) {
+	if !up.Dev.Autocreate && !autoDeploy {
+		return nil, false, errors.UserError{
 			E: fmt.Errorf("Deployment '%s' not found in namespace '%s'", up.Dev.Name, up.Dev.Namespace),
-			Hint: `Verify that your application has been deployed and your Kubernetes context is pointing to the right namespace
+			Hint: `Apply your manifests to deploy your application (e.g. 'kubectl apply' or 'okteto pipeline deploy') and try again
     Or set the 'autocreate' field in your okteto manifest if you want to create a standalone development container
+    Verify that your Kubernetes context is pointing to the right namespace
     More information is available here: https://okteto.com/docs/reference/cli#up`,
 		}
-		return nil, false, err
 	}
 
 	return up.Dev.GevSandbox(), true, nil
@@ -380,9 +508,9 @@ func (up *upContext) buildDevImage(ctx context.Context, d *appsv1.Deployment, cr
 	}
 
 	if up.Dev.Image.Name == "" {
-		devContainer := deployments.GetDevContainer(&d.Spec.Template.Spec, up.Dev.Container)
-		if devContainer == nil {
-			return fmt.Errorf("container '%s' does not exist in deployment '%s'", up.Dev.Container, up.Dev.Name)
+		devContainer, err := up.getDevContainer(d)
+		if err != nil {
+			return err
 		}
 		up.Dev.Image.Name = devContainer.Image
 	}
@@ -397,24 +525,18 @@ func (up *upContext) buildDevImage(ctx context.Context, d *appsv1.Deployment, cr
 	log.Infof("building dev image tag %s", imageTag)
 
 	buildArgs := model.SerializeBuildArgs(up.Dev.Image.Args)
-	if err := buildCMD.Run(ctx, up.Dev.Namespace, buildKitHost, isOktetoCluster, up.Dev.Image.Context, up.Dev.Image.Dockerfile, imageTag, up.Dev.Image.Target, false, up.Dev.Image.CacheFrom, buildArgs, nil, "tty"); err != nil {
+	buildSecrets := model.SerializeBuildSecrets(up.Dev.Image.Secrets)
+	if err := buildCMD.Run(ctx, up.Dev.Namespace, buildKitHost, isOktetoCluster, up.Dev.Image.Context, up.Dev.Image.Dockerfile, imageTag, up.Dev.Image.Target, false, up.Dev.Image.CacheFrom, buildArgs, buildSecrets, "tty"); err != nil {
 		return fmt.Errorf("error building dev image '%s': %s", imageTag, err)
 	}
-	for _, s := range up.Dev.Services {
-		if s.Image.Name == up.Dev.Image.Name {
-			s.Image.Name = imageTag
-			s.SetLastBuiltAnnotation()
-		}
-	}
-	up.Dev.Image.Name = imageTag
-	up.Dev.SetLastBuiltAnnotation()
+	up.Dev.SetBuiltImage(imageTag)
 	return nil
 }
 
 func (up *upContext) setDevContainer(d *appsv1.Deployment) error {
-	devContainer := deployments.GetDevContainer(&d.Spec.Template.Spec, up.Dev.Container)
-	if devContainer == nil {
-		return fmt.Errorf("container '%s' does not exist in deployment '%s'", up.Dev.Container, up.Dev.Name)
+	devContainer, err := up.getDevContainer(d)
+	if err != nil {
+		return err
 	}
 
 	up.Dev.Container = devContainer.Name
@@ -426,6 +548,23 @@ func (up *upContext) setDevContainer(d *appsv1.Deployment) error {
 	return nil
 }
 
+//getDevContainer resolves the dev container against the original deployment when it has been renamed
+func (up *upContext) getDevContainer(d *appsv1.Deployment) (*apiv1.Container, error) {
+	if up.Dev.ContainerSuffix != "" && deployments.IsDevModeOn(d) {
+		dOrig, err := deployments.GetOriginal(d)
+		if err != nil {
+			return nil, err
+		}
+		d = dOrig
+	}
+
+	devContainer := deployments.GetDevContainer(&d.Spec.Template.Spec, up.Dev.Container)
+	if devContainer == nil {
+		return nil, fmt.Errorf("container '%s' does not exist in deployment '%s'", up.Dev.Container, up.Dev.Name)
+	}
+	return devContainer, nil
+}
+
 func (up *upContext) getInteractive() bool {
 	if len(up.Dev.Command.Values) == 0 {
 		return true
@@ -14,11 +14,17 @@
 package up
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_waitUntilExitOrInterrupt(t *testing.T) {
@@ -100,3 +106,419 @@ func Test_printDisplayContext(t *testing.T) {
 	}
 
 }
+
+func Test_deploymentNotFound(t *testing.T) {
+	var tests = []struct {
+		name       string
+		autocreate bool
+		autoDeploy bool
+		expectErr  bool
+	}{
+		{
+			name:      "autocreate-off",
+			expectErr: true,
+		},
+		{
+			name:       "autocreate-on",
+			autocreate: true,
+		},
+		{
+			name:       "auto-deploy",
+			autoDeploy: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &upContext{
+				Dev: &model.Dev{
+					Name:       "dev",
+					Namespace:  "namespace",
+					Autocreate: tt.autocreate,
+					Image:      &model.BuildInfo{},
+				},
+			}
+
+			d, create, err := up.deploymentNotFound(tt.autoDeploy)
+			if !tt.expectErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if d == nil || !create {
+					t.Fatalf("sandbox deployment not returned")
+				}
+				return
+			}
+
+			if d != nil || create {
+				t.Fatalf("deployment returned when autocreate is off")
+			}
+
+			uErr, ok := err.(errors.UserError)
+			if !ok {
+				t.Fatalf("didn't return a user error: %s", err)
+			}
+			if !errors.IsNotFound(uErr) {
+				t.Errorf("error is not a not found error: %s", uErr)
+			}
+			if !strings.Contains(uErr.Hint, "Apply your manifests") {
+				t.Errorf("hint doesn't explain how to deploy the application: %s", uErr.Hint)
+			}
+			if !strings.Contains(uErr.Hint, "autocreate") {
+				t.Errorf("hint doesn't mention the 'autocreate' field: %s", uErr.Hint)
+			}
+		})
+	}
+}
+
+func Test_runPostSync(t *testing.T) {
+	ctx := context.Background()
+	up := upContext{
+		Dev: &model.Dev{PostSync: model.Command{Values: []string{"make", "deps"}}},
+	}
+
+	calls := 0
+	run := func(ctx context.Context, command []string) error {
+		calls++
+		if !reflect.DeepEqual(command, []string{"make", "deps"}) {
+			t.Errorf("wrong post-sync command: %v", command)
+		}
+		return nil
+	}
+
+	if err := up.runPostSync(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+	if err := up.runPostSync(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("post-sync command executed %d times", calls)
+	}
+}
+
+func Test_runPostSyncError(t *testing.T) {
+	ctx := context.Background()
+	up := upContext{
+		Dev: &model.Dev{PostSync: model.Command{Values: []string{"make", "deps"}}},
+	}
+
+	run := func(ctx context.Context, command []string) error {
+		return fmt.Errorf("exit code 2")
+	}
+
+	err := up.runPostSync(ctx, run)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := err.(errors.UserError); !ok {
+		t.Errorf("didn't return a user error: %s", err)
+	}
+	if up.postSyncDone {
+		t.Errorf("post-sync command marked as done after failure")
+	}
+}
+
+func Test_runPostSyncEmpty(t *testing.T) {
+	up := upContext{Dev: &model.Dev{}}
+	run := func(ctx context.Context, command []string) error {
+		t.Errorf("post-sync command executed")
+		return nil
+	}
+	if err := up.runPostSync(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_activationRetries(t *testing.T) {
+	retries := &activationRetries{max: 3}
+	expectedBackoff := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	for i := 0; i < 3; i++ {
+		if err := retries.failed(errors.ErrLostSyncthing); err != nil {
+			t.Fatalf("retry %d failed: %s", i, err)
+		}
+		if backoff := retries.backoff(); backoff != expectedBackoff[i] {
+			t.Errorf("wrong backoff for retry %d: %s", i, backoff)
+		}
+	}
+
+	err := retries.failed(fmt.Errorf("i/o timeout"))
+	if err == nil {
+		t.Fatal("retries limit not reached")
+	}
+	if _, ok := err.(errors.UserError); !ok {
+		t.Fatalf("didn't return a user error: %s", err)
+	}
+	expected := "your development container failed to activate 4 consecutive times: synchronization service is disconnected; i/o timeout"
+	if err.Error() != expected {
+		t.Errorf("wrong error: %s", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		_ = retries.failed(errors.ErrLostSyncthing)
+	}
+	if backoff := retries.backoff(); backoff != maxActivationBackoff {
+		t.Errorf("wrong max backoff: %s", backoff)
+	}
+
+	retries.reset()
+	if err := retries.failed(errors.ErrLostSyncthing); err != nil {
+		t.Errorf("retries not reset: %s", err)
+	}
+}
+
+func Test_activationRetriesUnlimited(t *testing.T) {
+	retries := &activationRetries{}
+	for i := 0; i < 100; i++ {
+		if err := retries.failed(errors.ErrLostSyncthing); err != nil {
+			t.Fatalf("unlimited retries failed: %s", err)
+		}
+	}
+	if backoff := retries.backoff(); backoff != time.Second {
+		t.Errorf("wrong unlimited backoff: %s", backoff)
+	}
+	if len(retries.errors) > 0 {
+		t.Errorf("unlimited retries recorded %d errors", len(retries.errors))
+	}
+}
+
+func Test_checkDevPodStatus(t *testing.T) {
+	now := metav1.Now()
+	var tests = []struct {
+		name     string
+		pod      *apiv1.Pod
+		restarts int32
+		expected bool
+		err      error
+	}{
+		{
+			name: "running",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "dev", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "running-dev-container-waiting",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "sidecar", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{Name: "dev", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name:     "running-without-container-statuses",
+			pod:      &apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodRunning}},
+			expected: false,
+		},
+		{
+			name:     "pending",
+			pod:      &apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodPending}},
+			expected: false,
+		},
+		{
+			name: "oomkilled",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Name:  "dev",
+							State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled"}},
+						},
+					},
+				},
+			},
+			err: errors.ErrDevContainerOOMKilled,
+		},
+		{
+			name: "oomkilled-before-session",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Name:                 "dev",
+							RestartCount:         1,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled"}},
+						},
+					},
+				},
+			},
+			restarts: 1,
+			expected: true,
+		},
+		{
+			name: "crashloop",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Name:                 "dev",
+							RestartCount:         3,
+							State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+						},
+					},
+				},
+			},
+			err: errors.ErrDevContainerCrashLoop,
+		},
+		{
+			name: "crashloop-before-session",
+			pod: &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodPending,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Name:                 "dev",
+							RestartCount:         3,
+							State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+						},
+					},
+				},
+			},
+			restarts: 3,
+			expected: false,
+		},
+		{
+			name: "deleted",
+			pod: &apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+			},
+			err: errors.ErrDevPodDeleted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &upContext{
+				Dev:         &model.Dev{Container: "dev"},
+				devRestarts: map[string]int32{tt.pod.Name: tt.restarts},
+			}
+			running, err := up.checkDevPodStatus(tt.pod)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			} else {
+				uErr, ok := err.(errors.UserError)
+				if ok {
+					err = uErr.E
+				}
+				if err != tt.err {
+					t.Fatalf("expected error '%s', got '%v'", tt.err, err)
+				}
+			}
+			if running != tt.expected {
+				t.Errorf("expected running %t, got %t", tt.expected, running)
+			}
+		})
+	}
+}
+
+func Test_validateImageArchitecture(t *testing.T) {
+	var tests = []struct {
+		name      string
+		arch      string
+		nodeArchs []string
+		expectErr bool
+	}{
+		{name: "same-architecture", arch: "amd64", nodeArchs: []string{"amd64"}},
+		{name: "heterogeneous-cluster", arch: "arm64", nodeArchs: []string{"amd64", "arm64"}},
+		{name: "unknown-node-architecture", arch: "arm64", nodeArchs: []string{}},
+		{name: "mismatched-architecture", arch: "arm64", nodeArchs: []string{"amd64"}, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageArchitecture("okteto.dev/app:dev", tt.arch, tt.nodeArchs)
+			if !tt.expectErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			uErr, ok := err.(errors.UserError)
+			if !ok {
+				t.Fatalf("expected user error, got: %v", err)
+			}
+			if uErr.E.Error() != "image 'okteto.dev/app:dev' is built for 'arm64' but the nodes of your deployment run 'amd64'" {
+				t.Errorf("wrong error: %s", uErr.E)
+			}
+		})
+	}
+}
+
+func Test_isGPUUnavailable(t *testing.T) {
+	var tests = []struct {
+		name     string
+		event    *apiv1.Event
+		expected bool
+	}{
+		{
+			name:     "nvidia",
+			event:    &apiv1.Event{Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu."},
+			expected: true,
+		},
+		{
+			name:     "amd",
+			event:    &apiv1.Event{Reason: "FailedScheduling", Message: "0/3 nodes are available: 1 Insufficient cpu, 2 Insufficient amd.com/gpu."},
+			expected: true,
+		},
+		{
+			name:     "cpu",
+			event:    &apiv1.Event{Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+			expected: false,
+		},
+		{
+			name:     "other-reason",
+			event:    &apiv1.Event{Reason: "Failed", Message: "Insufficient nvidia.com/gpu"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isGPUUnavailable(tt.event); result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_trackDevRestarts(t *testing.T) {
+	up := &upContext{Dev: &model.Dev{Container: "dev"}}
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev-pod"},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{{Name: "dev", RestartCount: 2}},
+		},
+	}
+	if restarts := up.trackDevRestarts(pod); restarts != 2 {
+		t.Errorf("wrong restarts when the pod is first seen: %d", restarts)
+	}
+
+	pod.Status.ContainerStatuses[0].RestartCount = 5
+	if restarts := up.trackDevRestarts(pod); restarts != 2 {
+		t.Errorf("restarts of the session start not kept: %d", restarts)
+	}
+
+	other := pod.DeepCopy()
+	other.Name = "other-pod"
+	if restarts := up.trackDevRestarts(other); restarts != 5 {
+		t.Errorf("wrong restarts for a new pod: %d", restarts)
+	}
+}
@@ -29,7 +29,8 @@ var spinnerSupport bool
 
 //Spinner represents an okteto spinner
 type Spinner struct {
-	sp *sp.Spinner
+	sp    *sp.Spinner
+	quiet bool
 }
 
 //NewSpinner returns a new Spinner
@@ -39,7 +40,8 @@ func NewSpinner(suffix string) *Spinner {
 	s.HideCursor = true
 	s.Suffix = fmt.Sprintf(" %s", suffix)
 	return &Spinner{
-		sp: s,
+		sp:    s,
+		quiet: log.IsQuiet(),
 	}
 }
 
@@ -59,6 +61,9 @@ func loadBoolean(k string) bool {
 
 //Start starts the spinner
 func (p *Spinner) Start() {
+	if p.quiet {
+		return
+	}
 	if spinnerSupport {
 		p.sp.Start()
 	} else {
@@ -68,6 +73,9 @@ func (p *Spinner) Start() {
 
 //Stop stops the spinner
 func (p *Spinner) Stop() {
+	if p.quiet {
+		return
+	}
 	if spinnerSupport {
 		p.sp.Stop()
 	}
@@ -76,7 +84,7 @@ func (p *Spinner) Stop() {
 //Update updates the spinner message
 func (p *Spinner) Update(text string) {
 	p.sp.Suffix = fmt.Sprintf(" %s", ucFirst(text))
-	if !spinnerSupport {
+	if !spinnerSupport && !p.quiet {
 		fmt.Println(strings.TrimSpace(p.sp.Suffix))
 	}
 }
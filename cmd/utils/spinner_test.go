@@ -0,0 +1,63 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/log"
+)
+
+func captureSpinnerOutput(t *testing.T, quiet bool) string {
+	t.Helper()
+	os.Setenv("OKTETO_DISABLE_SPINNER", "true")
+	defer os.Unsetenv("OKTETO_DISABLE_SPINNER")
+	log.SetQuiet(quiet)
+	defer log.SetQuiet(false)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	s := NewSpinner("Activating your development container...")
+	s.Start()
+	s.Update("pulling images...")
+	s.Stop()
+
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func Test_SpinnerQuiet(t *testing.T) {
+	out := captureSpinnerOutput(t, false)
+	expected := "Activating your development container...\nPulling images...\n"
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	out = captureSpinnerOutput(t, true)
+	if out != "" {
+		t.Errorf("quiet mode didn't suppress spinner output: %s", out)
+	}
+}
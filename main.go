@@ -65,6 +65,7 @@ func main() {
 	ctx := context.Background()
 	log.Init(logrus.WarnLevel)
 	var logLevel string
+	var quiet bool
 
 	root := &cobra.Command{
 		Use:           fmt.Sprintf("%s COMMAND [ARG...]", config.GetBinaryName()),
@@ -73,6 +74,7 @@ func main() {
 		PersistentPreRun: func(ccmd *cobra.Command, args []string) {
 			ccmd.SilenceUsage = true
 			log.SetLevel(logLevel)
+			log.SetQuiet(quiet)
 			log.Infof("started %s", strings.Join(os.Args, " "))
 
 		},
@@ -82,6 +84,7 @@ func main() {
 	}
 
 	root.PersistentFlags().StringVarP(&logLevel, "loglevel", "l", "warn", "amount of information outputted (debug, info, warn, error)")
+	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only output phase transitions, warnings and errors")
 	root.AddCommand(cmd.Analytics())
 	root.AddCommand(cmd.Version())
 	root.AddCommand(cmd.Login())
@@ -100,6 +103,7 @@ func main() {
 	root.AddCommand(cmd.Doctor())
 	root.AddCommand(cmd.Exec())
 	root.AddCommand(cmd.Restart())
+	root.AddCommand(cmd.Original())
 
 	err := utils.RunWithRetry(root.Execute)
 
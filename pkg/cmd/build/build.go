@@ -15,8 +15,10 @@ package build
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/log"
@@ -64,12 +66,42 @@ func Run(ctx context.Context, namespace, buildKitHost string, isOktetoCluster bo
 	if registry.IsTransientError(err) {
 		log.Yellow("Failed to push '%s' to the registry, retrying ...", tag)
 		success := true
-		err := solveBuild(ctx, buildkitClient, opt, progress)
+		err = solveBuild(ctx, buildkitClient, opt, progress)
 		if err != nil {
 			success = false
 		}
 		analytics.TrackBuildTransientError(buildKitHost, success)
+	}
+	return redactSecrets(err, secrets)
+}
+
+//redactSecrets removes the content of the build secrets from the build error
+func redactSecrets(err error, secrets []string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := false
+	for _, secret := range secrets {
+		for _, field := range strings.Split(secret, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 || (kv[0] != "src" && kv[0] != "source") {
+				continue
+			}
+			content, readErr := ioutil.ReadFile(kv[1])
+			if readErr != nil {
+				continue
+			}
+			value := strings.TrimSpace(string(content))
+			if value == "" || !strings.Contains(msg, value) {
+				continue
+			}
+			msg = strings.ReplaceAll(msg, value, "********")
+			redacted = true
+		}
+	}
+	if !redacted {
 		return err
 	}
-	return err
+	return errors.New(msg)
 }
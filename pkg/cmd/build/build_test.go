@@ -0,0 +1,98 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_getSolveOptBuildArgsAndSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("OKTETO_FOLDER", dir)
+	defer os.Unsetenv("OKTETO_FOLDER")
+
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	if err := ioutil.WriteFile(dockerfile, []byte("FROM alpine\nRUN --mount=type=secret,id=token cat /run/secrets/token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := []string{fmt.Sprintf("id=token,src=%s", tokenFile)}
+	opt, err := getSolveOpt(dir, dockerfile, "", "", false, nil, []string{"VERSION=1.2.3", "EMPTY="}, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opt.FrontendAttrs["build-arg:VERSION"] != "1.2.3" {
+		t.Errorf("build arg VERSION didn't reach the build: %v", opt.FrontendAttrs)
+	}
+	if v, ok := opt.FrontendAttrs["build-arg:EMPTY"]; !ok || v != "" {
+		t.Errorf("build arg EMPTY didn't reach the build: %v", opt.FrontendAttrs)
+	}
+	for k, v := range opt.FrontendAttrs {
+		if strings.Contains(v, "s3cr3t") {
+			t.Errorf("secret leaked into frontend attribute '%s'", k)
+		}
+	}
+	if len(opt.Session) != 2 {
+		t.Errorf("expected the auth and secret providers, got %d attachables", len(opt.Session))
+	}
+
+	if _, err := getSolveOpt(dir, dockerfile, "", "", false, nil, []string{"VERSION"}, nil); err == nil {
+		t.Error("expected error for a malformed build arg")
+	}
+}
+
+func Test_redactSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	secrets := []string{fmt.Sprintf("id=token,src=%s", tokenFile)}
+
+	if err := redactSecrets(nil, secrets); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	original := fmt.Errorf("failed to solve: process \"/bin/sh -c echo s3cr3t && exit 1\" did not complete successfully")
+	redacted := redactSecrets(original, secrets)
+	if strings.Contains(redacted.Error(), "s3cr3t") {
+		t.Errorf("secret was not redacted: %s", redacted)
+	}
+	if !strings.Contains(redacted.Error(), "echo ******** && exit 1") {
+		t.Errorf("wrong redacted error: %s", redacted)
+	}
+
+	other := fmt.Errorf("failed to solve: unknown instruction")
+	if redactSecrets(other, secrets) != other {
+		t.Error("errors without secrets must not be modified")
+	}
+}
@@ -219,7 +219,7 @@ func generatePodFile(ctx context.Context, dev *model.Dev, c *kubernetes.Clientse
 	}
 	defer podFile.Close()
 
-	devContainer := deployments.GetDevContainer(&pod.Spec, dev.Container)
+	devContainer := deployments.GetDevContainer(&pod.Spec, pods.GetContainerName(pod, dev))
 	cpu := "unlimited"
 	memory := "unlimited"
 	limits := devContainer.Resources.Limits
@@ -18,12 +18,14 @@ import (
 
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/secrets"
+	"github.com/okteto/okteto/pkg/k8s/serviceaccounts"
 	"github.com/okteto/okteto/pkg/k8s/services"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/ssh"
 	"github.com/okteto/okteto/pkg/syncthing"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -34,21 +36,19 @@ func Run(dev *model.Dev, d *appsv1.Deployment, trList map[string]*model.Translat
 		log.Info("no translations available in the deployment")
 	}
 
-	for _, tr := range trList {
-		if tr.Deployment == nil {
-			continue
-		}
-		dTmp, err := deployments.TranslateDevModeOff(tr.Deployment)
-		if err != nil {
-			return err
-		}
-		tr.Deployment = dTmp
+	originals := map[string]*appsv1.Deployment{}
+	if dev.VerifyDown {
+		originals = getOriginals(trList)
 	}
-	if err := deployments.UpdateDeployments(ctx, trList, c); err != nil {
+
+	restoreErr := restoreDeployments(ctx, dev, trList, c)
+	if err := destroySecrets(ctx, dev, restoreErr, c); err != nil {
 		return err
 	}
 
-	if err := secrets.Destroy(ctx, dev, c); err != nil {
+	verifyDeployments(ctx, originals, c)
+
+	if err := serviceaccounts.DestroyDev(ctx, dev, c); err != nil {
 		return err
 	}
 
@@ -79,6 +79,66 @@ func Run(dev *model.Dev, d *appsv1.Deployment, trList map[string]*model.Translat
 	return nil
 }
 
+func restoreDeployments(ctx context.Context, dev *model.Dev, trList map[string]*model.Translation, c *kubernetes.Clientset) error {
+	for _, tr := range trList {
+		if tr.Deployment == nil {
+			continue
+		}
+		dTmp, err := deployments.TranslateDevModeOff(tr.Deployment, dev.PreserveAnnotations)
+		if err != nil {
+			return err
+		}
+		tr.Deployment = dTmp
+	}
+	return deployments.UpdateDeployments(ctx, trList, c)
+}
+
+//getOriginals returns the original spec of the deployments in dev mode, skipping the ones translated server side
+func getOriginals(trList map[string]*model.Translation) map[string]*appsv1.Deployment {
+	originals := map[string]*appsv1.Deployment{}
+	for name, tr := range trList {
+		if tr.Deployment == nil {
+			continue
+		}
+		dOrig, err := deployments.GetOriginal(tr.Deployment)
+		if err != nil {
+			log.Infof("skipping the verification of deployment '%s': %s", name, err)
+			continue
+		}
+		originals[name] = dOrig
+	}
+	return originals
+}
+
+//verifyDeployments checks that the deployments were restored to their original spec, warning on every mismatch found
+func verifyDeployments(ctx context.Context, originals map[string]*appsv1.Deployment, c kubernetes.Interface) []string {
+	mismatches := []string{}
+	for _, dOrig := range originals {
+		d, err := c.AppsV1().Deployments(dOrig.Namespace).Get(ctx, dOrig.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Infof("failed to get deployment '%s' to verify it was restored: %s", dOrig.Name, err)
+			continue
+		}
+		for _, m := range deployments.CheckRestored(d, dOrig) {
+			log.Yellow("Deployment '%s' wasn't restored to its original spec: %s", dOrig.Name, m)
+			mismatches = append(mismatches, m)
+		}
+	}
+	return mismatches
+}
+
+//destroySecrets deletes the okteto secret even if the deployments couldn't be restored, and returns the restore error first
+func destroySecrets(ctx context.Context, dev *model.Dev, restoreErr error, c kubernetes.Interface) error {
+	if err := secrets.Destroy(ctx, dev, c); err != nil {
+		if restoreErr != nil {
+			log.Infof("failed to delete okteto secret: %s", err)
+			return restoreErr
+		}
+		return err
+	}
+	return restoreErr
+}
+
 func stopSyncthing(dev *model.Dev) {
 	sy, err := syncthing.New(dev)
 	if err != nil {
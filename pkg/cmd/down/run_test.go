@@ -0,0 +1,135 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package down
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/k8s/secrets"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_destroySecrets(t *testing.T) {
+	var tests = []struct {
+		name       string
+		exists     bool
+		restoreErr error
+		expectErr  bool
+	}{
+		{
+			name:      "exists",
+			exists:    true,
+			expectErr: false,
+		},
+		{
+			name:      "not-found",
+			exists:    false,
+			expectErr: false,
+		},
+		{
+			name:       "restore-failed",
+			exists:     true,
+			restoreErr: fmt.Errorf("failed to update deployment"),
+			expectErr:  true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := &model.Dev{Name: "dev", Namespace: "test"}
+			c := fake.NewSimpleClientset()
+			if tt.exists {
+				s := &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secrets.GetSecretName(dev),
+						Namespace: dev.Namespace,
+					},
+				}
+				if _, err := c.CoreV1().Secrets(dev.Namespace).Create(ctx, s, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := destroySecrets(ctx, dev, tt.restoreErr, c)
+			if tt.expectErr && err != tt.restoreErr {
+				t.Fatalf("expected restore error, got %v", err)
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.CoreV1().Secrets(dev.Namespace).Get(ctx, secrets.GetSecretName(dev), metav1.GetOptions{}); err == nil {
+				t.Fatal("okteto secret wasn't deleted")
+			}
+
+			if err := destroySecrets(ctx, dev, nil, c); err != nil {
+				t.Fatalf("cleanup is not idempotent: %s", err)
+			}
+		})
+	}
+}
+
+func Test_verifyDeployments(t *testing.T) {
+	ctx := context.Background()
+	dOrig := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "test",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "web", Image: "okteto/web"}},
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		name       string
+		image      string
+		mismatches int
+	}{
+		{
+			name:       "restored",
+			image:      "okteto/web",
+			mismatches: 0,
+		},
+		{
+			name:       "not-restored",
+			image:      "okteto/dev",
+			mismatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dOrig.DeepCopy()
+			d.Spec.Template.Spec.Containers[0].Image = tt.image
+			c := fake.NewSimpleClientset(d)
+
+			mismatches := verifyDeployments(ctx, map[string]*appsv1.Deployment{"web": dOrig}, c)
+			if len(mismatches) != tt.mismatches {
+				t.Errorf("expected %d mismatches, got %v", tt.mismatches, mismatches)
+			}
+		})
+	}
+}
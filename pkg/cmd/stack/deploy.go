@@ -15,7 +15,6 @@ package stack
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -50,7 +49,7 @@ func Deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool)
 	cfg := translateConfigMap(s)
 	output := fmt.Sprintf("Deploying stack '%s'...", s.Name)
 	cfg.Data[statusField] = progressingStatus
-	cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+	setOutput(cfg, output)
 	if err := configmaps.Deploy(ctx, cfg, s.Namespace, c); err != nil {
 		return err
 	}
@@ -59,11 +58,11 @@ func Deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool)
 	if err != nil {
 		output = fmt.Sprintf("%s\nStack '%s' deployment failed: %s", output, s.Name, err.Error())
 		cfg.Data[statusField] = errorStatus
-		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+		setOutput(cfg, output)
 	} else {
 		output = fmt.Sprintf("%s\nStack '%s' successfully deployed", output, s.Name)
 		cfg.Data[statusField] = deployedStatus
-		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+		setOutput(cfg, output)
 	}
 
 	if err := configmaps.Deploy(ctx, cfg, s.Namespace, c); err != nil {
@@ -103,8 +102,10 @@ func deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool,
 		spinner.Start()
 	}
 
-	if err := destroyServicesNotInStack(ctx, spinner, s, c); err != nil {
-		return err
+	for _, namespace := range s.GetNamespaces() {
+		if err := destroyServicesNotInStack(ctx, spinner, s, namespace, c); err != nil {
+			return err
+		}
 	}
 
 	if !wait {
@@ -118,7 +119,7 @@ func deploy(ctx context.Context, s *model.Stack, forceBuild, wait, noCache bool,
 
 func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c *kubernetes.Clientset) error {
 	d := translateDeployment(svcName, s)
-	old, err := c.AppsV1().Deployments(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	old, err := c.AppsV1().Deployments(d.Namespace).Get(ctx, svcName, metav1.GetOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return fmt.Errorf("error getting deployment of service '%s': %s", svcName, err.Error())
 	}
@@ -134,7 +135,7 @@ func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c *ku
 			deployments.RestoreDevModeFrom(d, old)
 		}
 	}
-	if err := deployments.Deploy(ctx, d, isNewDeployment, c); err != nil {
+	if err := deployments.Deploy(ctx, d, isNewDeployment, model.DeployStrategyReplace, c); err != nil {
 		if isNewDeployment {
 			return fmt.Errorf("error creating deployment of service '%s': %s", svcName, err.Error())
 		}
@@ -145,7 +146,7 @@ func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c *ku
 
 func deployStatefulSet(ctx context.Context, svcName string, s *model.Stack, c *kubernetes.Clientset) error {
 	sfs := translateStatefulSet(svcName, s)
-	old, err := c.AppsV1().StatefulSets(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	old, err := c.AppsV1().StatefulSets(sfs.Namespace).Get(ctx, svcName, metav1.GetOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		return fmt.Errorf("error getting statefulset of service '%s': %s", svcName, err.Error())
 	}
@@ -175,7 +176,7 @@ func deployStatefulSet(ctx context.Context, svcName string, s *model.Stack, c *k
 	return nil
 }
 
-func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c *kubernetes.Clientset) error {
+func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
 	var numPods int32 = 0
 	for _, svc := range s.Services {
 		numPods += svc.Replicas
@@ -188,13 +189,15 @@ func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c *kubernetes.C
 	for time.Now().Before(timeout) {
 		<-ticker.C
 		pendingPods := numPods
-		podList, err := pods.ListBySelector(ctx, s.Namespace, selector, c)
-		if err != nil {
-			return err
-		}
-		for i := range podList {
-			if podList[i].Status.Phase == apiv1.PodRunning {
-				pendingPods--
+		for _, namespace := range s.GetNamespaces() {
+			podList, err := pods.ListBySelector(ctx, namespace, selector, c)
+			if err != nil {
+				return err
+			}
+			for i := range podList {
+				if podList[i].Status.Phase == apiv1.PodRunning {
+					pendingPods--
+				}
 			}
 		}
 		if pendingPods == 0 {
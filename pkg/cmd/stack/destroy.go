@@ -15,12 +15,13 @@ package stack
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/k8s/configmaps"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
@@ -31,23 +32,36 @@ import (
 	"github.com/okteto/okteto/pkg/k8s/volumes"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/crypto/ssh/terminal"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
+	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-//Destroy destroys a stack
-func Destroy(ctx context.Context, s *model.Stack, removeVolumes bool) error {
+//Destroy destroys a stack.
+//When removeVolumes is set, the volumes to be destroyed are listed and must be confirmed unless force is set
+func Destroy(ctx context.Context, s *model.Stack, removeVolumes, force bool) error {
 	if s.Namespace == "" {
 		s.Namespace = client.GetContextNamespace("")
 	}
 
 	c, _, _ := client.GetLocal()
 
+	if removeVolumes {
+		vList, err := listStackVolumes(ctx, s, s.GetNamespaces(), c)
+		if err != nil {
+			return err
+		}
+		if err := confirmVolumesDestruction(vList, force, terminal.IsTerminal(int(os.Stdin.Fd()))); err != nil {
+			return err
+		}
+	}
+
 	cfg := translateConfigMap(s)
 	output := fmt.Sprintf("Destroying stack '%s'...", s.Name)
 	cfg.Data[statusField] = destroyingStatus
-	cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+	setOutput(cfg, output)
 	if err := configmaps.Deploy(ctx, cfg, s.Namespace, c); err != nil {
 		return err
 	}
@@ -56,7 +70,7 @@ func Destroy(ctx context.Context, s *model.Stack, removeVolumes bool) error {
 	if err != nil {
 		output = fmt.Sprintf("%s\nStack '%s' destruction failed: %s", output, s.Name, err.Error())
 		cfg.Data[statusField] = errorStatus
-		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+		setOutput(cfg, output)
 		if err := configmaps.Deploy(ctx, cfg, s.Namespace, c); err != nil {
 			return err
 		}
@@ -75,24 +89,40 @@ func destroy(ctx context.Context, s *model.Stack, removeVolumes bool, c *kuberne
 		return err
 	}
 
+	namespaces := s.GetNamespaces()
 	s.Services = nil
-	if err := destroyServicesNotInStack(ctx, spinner, s, c); err != nil {
+	if err := destroyNamespaces(ctx, spinner, s, namespaces, removeVolumes, c); err != nil {
 		return err
 	}
 
-	spinner.Update("Waiting for services to be destroyed...")
-	if err := waitForPodsToBeDestroyed(ctx, s, c); err != nil {
-		return err
+	return configmaps.Destroy(ctx, s.GetConfigMapName(), s.Namespace, c)
+}
+
+func destroyNamespaces(ctx context.Context, spinner *utils.Spinner, s *model.Stack, namespaces []string, removeVolumes bool, c kubernetes.Interface) error {
+	for _, namespace := range namespaces {
+		if err := destroyServicesNotInStack(ctx, spinner, s, namespace, c); err != nil {
+			return err
+		}
 	}
 
-	if removeVolumes {
-		spinner.Update("Destroying volumes...")
-		if err := destroyStackVolumes(ctx, spinner, s, c); err != nil {
+	spinner.Update("Waiting for services to be destroyed...")
+	for _, namespace := range namespaces {
+		if err := waitForPodsToBeDestroyed(ctx, s, namespace, c); err != nil {
 			return err
 		}
 	}
 
-	return configmaps.Destroy(ctx, s.GetConfigMapName(), s.Namespace, c)
+	if !removeVolumes {
+		return nil
+	}
+
+	spinner.Update("Destroying volumes...")
+	for _, namespace := range namespaces {
+		if err := destroyStackVolumes(ctx, spinner, s, namespace, c); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func helmReleaseExist(c *action.List, name string) (bool, error) {
@@ -126,7 +156,7 @@ func destroyHelmRelease(ctx context.Context, spinner *utils.Spinner, s *model.St
 		return fmt.Errorf("error listing stacks: %s", err)
 	}
 	if exists {
-		uClient := action.NewUninstall(actionConfig)
+		uClient := translateHelmUninstall(actionConfig, s)
 		if _, err := uClient.Run(s.Name); err != nil {
 			return fmt.Errorf("error destroying stack '%s': %s", s.Name, err.Error())
 		}
@@ -134,13 +164,13 @@ func destroyHelmRelease(ctx context.Context, spinner *utils.Spinner, s *model.St
 	return nil
 }
 
-func destroyServicesNotInStack(ctx context.Context, spinner *utils.Spinner, s *model.Stack, c *kubernetes.Clientset) error {
-	dList, err := deployments.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+func destroyServicesNotInStack(ctx context.Context, spinner *utils.Spinner, s *model.Stack, namespace string, c kubernetes.Interface) error {
+	dList, err := deployments.List(ctx, namespace, s.GetLabelSelector(), c)
 	if err != nil {
 		return err
 	}
 	for i := range dList {
-		if _, ok := s.Services[dList[i].Name]; ok {
+		if _, ok := s.Services[dList[i].Name]; ok && s.GetServiceNamespace(dList[i].Name) == namespace {
 			continue
 		}
 		if err := deployments.Destroy(ctx, dList[i].Name, dList[i].Namespace, c); err != nil {
@@ -154,12 +184,12 @@ func destroyServicesNotInStack(ctx context.Context, spinner *utils.Spinner, s *m
 		spinner.Start()
 	}
 
-	sfsList, err := statefulsets.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	sfsList, err := statefulsets.List(ctx, namespace, s.GetLabelSelector(), c)
 	if err != nil {
 		return err
 	}
 	for i := range sfsList {
-		if _, ok := s.Services[sfsList[i].Name]; ok {
+		if _, ok := s.Services[sfsList[i].Name]; ok && s.GetServiceNamespace(sfsList[i].Name) == namespace {
 			continue
 		}
 		if err := statefulsets.Destroy(ctx, sfsList[i].Name, sfsList[i].Namespace, c); err != nil {
@@ -176,14 +206,14 @@ func destroyServicesNotInStack(ctx context.Context, spinner *utils.Spinner, s *m
 	return nil
 }
 
-func waitForPodsToBeDestroyed(ctx context.Context, s *model.Stack, c *kubernetes.Clientset) error {
+func waitForPodsToBeDestroyed(ctx context.Context, s *model.Stack, namespace string, c kubernetes.Interface) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	timeout := time.Now().Add(300 * time.Second)
 
 	selector := map[string]string{okLabels.StackNameLabel: s.Name}
 	for time.Now().Before(timeout) {
 		<-ticker.C
-		podList, err := pods.ListBySelector(ctx, s.Namespace, selector, c)
+		podList, err := pods.ListBySelector(ctx, namespace, selector, c)
 		if err != nil {
 			return err
 		}
@@ -194,8 +224,8 @@ func waitForPodsToBeDestroyed(ctx context.Context, s *model.Stack, c *kubernetes
 	return fmt.Errorf("kubernetes is taking too long to destroy your stack. Please check for errors and try again")
 }
 
-func destroyStackVolumes(ctx context.Context, spinner *utils.Spinner, s *model.Stack, c *kubernetes.Clientset) error {
-	vList, err := volumes.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+func destroyStackVolumes(ctx context.Context, spinner *utils.Spinner, s *model.Stack, namespace string, c kubernetes.Interface) error {
+	vList, err := volumes.List(ctx, namespace, s.GetLabelSelector(), c)
 	if err != nil {
 		return err
 	}
@@ -211,3 +241,56 @@ func destroyStackVolumes(ctx context.Context, spinner *utils.Spinner, s *model.S
 	}
 	return nil
 }
+
+func listStackVolumes(ctx context.Context, s *model.Stack, namespaces []string, c kubernetes.Interface) ([]apiv1.PersistentVolumeClaim, error) {
+	result := []apiv1.PersistentVolumeClaim{}
+	for _, namespace := range namespaces {
+		vList, err := volumes.List(ctx, namespace, s.GetLabelSelector(), c)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vList {
+			if v.Labels[okLabels.StackNameLabel] == s.Name {
+				result = append(result, v)
+			}
+		}
+	}
+	return result, nil
+}
+
+func getVolumeSize(v apiv1.PersistentVolumeClaim) string {
+	if size, ok := v.Status.Capacity[apiv1.ResourceStorage]; ok {
+		return size.String()
+	}
+	if size, ok := v.Spec.Resources.Requests[apiv1.ResourceStorage]; ok {
+		return size.String()
+	}
+	return "unknown size"
+}
+
+func confirmVolumesDestruction(vList []apiv1.PersistentVolumeClaim, force, interactive bool) error {
+	if len(vList) == 0 || force {
+		return nil
+	}
+
+	log.Yellow("The following volumes and all their data will be destroyed:")
+	for _, v := range vList {
+		log.Yellow("  - %s/%s (%s)", v.Namespace, v.Name, getVolumeSize(v))
+	}
+
+	if !interactive {
+		return errors.UserError{
+			E:    fmt.Errorf("destroying the volumes of the stack requires confirmation"),
+			Hint: "Run the command with '--force' to destroy them without confirmation",
+		}
+	}
+
+	confirmed, err := utils.AskYesNo("Do you want to destroy these volumes? [y/n]: ")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("stack destruction canceled")
+	}
+	return nil
+}
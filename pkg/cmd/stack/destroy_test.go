@@ -0,0 +1,176 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/errors"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_destroyNamespaces(t *testing.T) {
+	ctx := context.Background()
+	stackLabels := map[string]string{okLabels.StackNameLabel: "stack"}
+	otherLabels := map[string]string{okLabels.StackNameLabel: "other"}
+	c := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "apps", Labels: stackLabels}},
+		&apiv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "apps", Labels: stackLabels}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "infra", Labels: stackLabels}},
+		&apiv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "infra", Labels: stackLabels}},
+		&apiv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "infra", Labels: stackLabels}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "infra", Labels: otherLabels}},
+		&apiv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: "infra", Labels: otherLabels}},
+	)
+
+	s := &model.Stack{
+		Name:      "stack",
+		Namespace: "apps",
+		Services: map[string]model.Service{
+			"api": {Image: "api"},
+			"db":  {Image: "db", Namespace: "infra"},
+		},
+	}
+	namespaces := s.GetNamespaces()
+	s.Services = nil
+
+	spinner := utils.NewSpinner("Destroying stack 'stack'...")
+	defer spinner.Stop()
+	if err := destroyNamespaces(ctx, spinner, s, namespaces, true, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if dList, _ := c.AppsV1().Deployments("apps").List(ctx, metav1.ListOptions{}); len(dList.Items) != 0 {
+		t.Errorf("deployments in namespace 'apps' were not destroyed: %+v", dList.Items)
+	}
+	if sfsList, _ := c.AppsV1().StatefulSets("infra").List(ctx, metav1.ListOptions{}); len(sfsList.Items) != 0 {
+		t.Errorf("statefulsets in namespace 'infra' were not destroyed: %+v", sfsList.Items)
+	}
+	for _, ns := range []string{"apps", "infra"} {
+		if svcList, _ := c.CoreV1().Services(ns).List(ctx, metav1.ListOptions{}); len(svcList.Items) != 0 {
+			t.Errorf("services in namespace '%s' were not destroyed: %+v", ns, svcList.Items)
+		}
+	}
+
+	if _, err := c.AppsV1().Deployments("infra").Get(ctx, "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("deployment of another stack was destroyed: %s", err)
+	}
+	vList, _ := c.CoreV1().PersistentVolumeClaims("infra").List(ctx, metav1.ListOptions{})
+	if len(vList.Items) != 1 || vList.Items[0].Name != "data-web-0" {
+		t.Errorf("wrong volumes after destroying the stack: %+v", vList.Items)
+	}
+}
+
+func Test_listStackVolumes(t *testing.T) {
+	ctx := context.Background()
+	stackLabels := map[string]string{okLabels.StackNameLabel: "stack"}
+	otherLabels := map[string]string{okLabels.StackNameLabel: "other"}
+	c := fake.NewSimpleClientset(
+		&apiv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-api-0", Namespace: "apps", Labels: stackLabels},
+			Spec: apiv1.PersistentVolumeClaimSpec{
+				Resources: apiv1.ResourceRequirements{
+					Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		},
+		&apiv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "infra", Labels: stackLabels},
+			Spec: apiv1.PersistentVolumeClaimSpec{
+				Resources: apiv1.ResourceRequirements{
+					Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			Status: apiv1.PersistentVolumeClaimStatus{
+				Capacity: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("5Gi")},
+			},
+		},
+		&apiv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: "infra", Labels: otherLabels}},
+		&apiv1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "infra", Labels: stackLabels}},
+	)
+
+	s := &model.Stack{Name: "stack", Namespace: "apps"}
+	vList, err := listStackVolumes(ctx, s, []string{"apps", "infra"}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"apps/data-api-0": "1Gi",
+		"infra/cache":     "unknown size",
+		"infra/data-db-0": "5Gi",
+	}
+	result := map[string]string{}
+	for _, v := range vList {
+		result[v.Namespace+"/"+v.Name] = getVolumeSize(v)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("wrong volumes: expected %v, got %v", expected, result)
+	}
+}
+
+func Test_confirmVolumesDestruction(t *testing.T) {
+	vList := []apiv1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "infra"}},
+	}
+	var tests = []struct {
+		name        string
+		volumes     []apiv1.PersistentVolumeClaim
+		force       bool
+		expectedErr bool
+	}{
+		{
+			name:        "no-volumes",
+			volumes:     []apiv1.PersistentVolumeClaim{},
+			force:       false,
+			expectedErr: false,
+		},
+		{
+			name:        "force",
+			volumes:     vList,
+			force:       true,
+			expectedErr: false,
+		},
+		{
+			name:        "non-interactive-without-force",
+			volumes:     vList,
+			force:       false,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := confirmVolumesDestruction(tt.volumes, tt.force, false)
+			if tt.expectedErr {
+				if _, ok := err.(errors.UserError); !ok {
+					t.Errorf("expected a user error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@
 package stack
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -33,6 +35,7 @@ import (
 	"github.com/okteto/okteto/pkg/registry"
 	"github.com/subosito/gotenv"
 	yaml "gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/action"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -49,6 +52,16 @@ const (
 	yamlField   = "yaml"
 	outputField = "output"
 
+	//outputEncodingField marks how the output field is encoded when it isn't plain base64
+	outputEncodingField = "outputEncoding"
+	gzipEncoding        = "gzip"
+
+	//compressOutputThreshold is the output size from which the output is compressed
+	compressOutputThreshold = 64 * 1024
+	//maxOutputSize keeps the output well below the 1MB limit of configmaps
+	maxOutputSize   = 512 * 1024
+	truncatedMarker = "[output truncated]\n"
+
 	progressingStatus = "progressing"
 	deployedStatus    = "deployed"
 	errorStatus       = "error"
@@ -191,12 +204,64 @@ func translateConfigMap(s *model.Stack) *apiv1.ConfigMap {
 	}
 }
 
+//setOutput stores the output in the configmap, compressing and truncating it to fit the configmap size limit
+func setOutput(cfg *apiv1.ConfigMap, output string) {
+	delete(cfg.Data, outputEncodingField)
+	if len(output) < compressOutputThreshold {
+		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+		return
+	}
+
+	truncated := output
+	for {
+		encoded, err := compressOutput(truncated)
+		if err != nil {
+			log.Infof("failed to compress the stack output: %s", err)
+			break
+		}
+		if len(encoded) <= maxOutputSize {
+			cfg.Data[outputField] = encoded
+			cfg.Data[outputEncodingField] = gzipEncoding
+			return
+		}
+		truncated = truncatedMarker + output[len(output)-len(truncated)/2:]
+	}
+
+	if len(output) > maxOutputSize/2 {
+		output = truncatedMarker + output[len(output)-maxOutputSize/2:]
+	}
+	cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
+}
+
+func compressOutput(output string) (string, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write([]byte(output)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+//translateHelmUninstall returns the uninstall action of the legacy helm release of a stack with the stack overrides
+func translateHelmUninstall(actionConfig *action.Configuration, s *model.Stack) *action.Uninstall {
+	uClient := action.NewUninstall(actionConfig)
+	if s.Helm == nil {
+		return uClient
+	}
+	uClient.KeepHistory = s.Helm.KeepHistory
+	uClient.DisableHooks = s.Helm.DisableHooks
+	return uClient
+}
+
 func translateDeployment(svcName string, s *model.Stack) *appsv1.Deployment {
 	svc := s.Services[svcName]
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        svcName,
-			Namespace:   s.Namespace,
+			Namespace:   s.GetServiceNamespace(svcName),
 			Labels:      translateLabels(svcName, s),
 			Annotations: translateAnnotations(&svc),
 		},
@@ -235,7 +300,7 @@ func translateStatefulSet(name string, s *model.Stack) *appsv1.StatefulSet {
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
-			Namespace:   s.Namespace,
+			Namespace:   s.GetServiceNamespace(name),
 			Labels:      translateLabels(name, s),
 			Annotations: translateAnnotations(&svc),
 		},
@@ -312,7 +377,7 @@ func translateService(svcName string, s *model.Stack) *apiv1.Service {
 	return &apiv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        svcName,
-			Namespace:   s.Namespace,
+			Namespace:   s.GetServiceNamespace(svcName),
 			Labels:      translateLabels(svcName, s),
 			Annotations: annotations,
 		},
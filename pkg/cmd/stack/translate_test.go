@@ -14,15 +14,21 @@
 package stack
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/model"
+	"helm.sh/helm/v3/pkg/action"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -127,6 +133,132 @@ func Test_translateConfigMap(t *testing.T) {
 	}
 }
 
+//decodeOutput returns the output stored in the configmap by setOutput
+func decodeOutput(cfg *apiv1.ConfigMap) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cfg.Data[outputField])
+	if err != nil {
+		return "", err
+	}
+	if cfg.Data[outputEncodingField] != gzipEncoding {
+		return string(decoded), nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(decoded))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	output, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func Test_setOutput(t *testing.T) {
+	random := make([]byte, 2*maxOutputSize)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	incompressible := base64.StdEncoding.EncodeToString(random)
+
+	var tests = []struct {
+		name         string
+		output       string
+		expectedGzip bool
+		truncated    bool
+	}{
+		{
+			name:         "small",
+			output:       "Destroying stack 'stackName'...",
+			expectedGzip: false,
+		},
+		{
+			name:         "compressed",
+			output:       strings.Repeat("Destroying service 'svcName'...\n", 100000),
+			expectedGzip: true,
+		},
+		{
+			name:         "truncated",
+			output:       incompressible,
+			expectedGzip: true,
+			truncated:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &apiv1.ConfigMap{Data: map[string]string{outputEncodingField: "previous"}}
+			setOutput(cfg, tt.output)
+
+			if len(cfg.Data[outputField]) > maxOutputSize {
+				t.Errorf("output is too large: %d", len(cfg.Data[outputField]))
+			}
+			if (cfg.Data[outputEncodingField] == gzipEncoding) != tt.expectedGzip {
+				t.Errorf("wrong output encoding: '%s'", cfg.Data[outputEncodingField])
+			}
+			if !tt.expectedGzip {
+				if _, ok := cfg.Data[outputEncodingField]; ok {
+					t.Errorf("output encoding was not removed")
+				}
+			}
+
+			output, err := decodeOutput(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.truncated {
+				if output != tt.output {
+					t.Errorf("wrong output after round-trip")
+				}
+				return
+			}
+			if !strings.HasPrefix(output, truncatedMarker) {
+				t.Errorf("truncated output doesn't start with the truncated marker")
+			}
+			if !strings.HasSuffix(tt.output, strings.TrimPrefix(output, truncatedMarker)) {
+				t.Errorf("truncated output doesn't keep the end of the output")
+			}
+		})
+	}
+}
+
+func Test_translateHelmUninstall(t *testing.T) {
+	tests := []struct {
+		name         string
+		helm         *model.StackHelm
+		keepHistory  bool
+		disableHooks bool
+	}{
+		{
+			name: "no-overrides",
+		},
+		{
+			name:        "keep-history",
+			helm:        &model.StackHelm{KeepHistory: true},
+			keepHistory: true,
+		},
+		{
+			name:         "all-overrides",
+			helm:         &model.StackHelm{KeepHistory: true, DisableHooks: true},
+			keepHistory:  true,
+			disableHooks: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &model.Stack{Name: "stackName", Helm: tt.helm}
+			result := translateHelmUninstall(&action.Configuration{}, s)
+			if result.KeepHistory != tt.keepHistory {
+				t.Errorf("Wrong keepHistory: %t", result.KeepHistory)
+			}
+			if result.DisableHooks != tt.disableHooks {
+				t.Errorf("Wrong disableHooks: %t", result.DisableHooks)
+			}
+		})
+	}
+}
+
 func Test_translateDeployment(t *testing.T) {
 	s := &model.Stack{
 		Name: "stackName",
@@ -75,11 +75,20 @@ var (
 	// ErrLostSyncthing is raised when we lose connectivity with syncthing
 	ErrLostSyncthing = fmt.Errorf("synchronization service is disconnected")
 
+	// ErrDevContainerOOMKilled is raised when the development container is killed for running out of memory
+	ErrDevContainerOOMKilled = fmt.Errorf("your development container has been killed because it ran out of memory")
+
+	// ErrDevContainerCrashLoop is raised when the development container keeps restarting
+	ErrDevContainerCrashLoop = fmt.Errorf("your development container is crashing repeatedly")
+
 	// ErrNotInDevMode is raised when the eployment is not in dev mode
 	ErrNotInDevMode = fmt.Errorf("Deployment is not in development mode anymore")
 
 	// ErrDevPodDeleted raised if dev pod is deleted in the middle of the "okteto up" sequence
 	ErrDevPodDeleted = fmt.Errorf("development container has been removed")
+
+	// ErrGPUUnavailable is raised when the development container cannot be scheduled because there are no GPUs available
+	ErrGPUUnavailable = fmt.Errorf("there are no GPUs available to schedule your development container")
 )
 
 // IsNotFound returns true if err is of the type not found
@@ -29,8 +29,12 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
 )
 
 //List returns the list of deployments
@@ -162,14 +166,17 @@ func GetTranslations(ctx context.Context, dev *model.Dev, d *appsv1.Deployment,
 		rule := dev.ToTranslationRule(dev)
 		replicas := getPreviousDeploymentReplicas(d)
 		result[d.Name] = &model.Translation{
-			Interactive: true,
-			Name:        dev.Name,
-			Version:     model.TranslationVersion,
-			Deployment:  d,
-			Annotations: dev.Annotations,
-			Tolerations: dev.Tolerations,
-			Replicas:    replicas,
-			Rules:       []*model.TranslationRule{rule},
+			Interactive:     true,
+			Name:            dev.Name,
+			Version:         model.TranslationVersion,
+			Deployment:      d,
+			Annotations:     dev.Annotations,
+			Tolerations:     dev.ToTolerations(),
+			Replicas:        replicas,
+			DevReplicas:     dev.Replicas,
+			BackupExclusion: dev.BackupExclusionEnabled(),
+			PreventEviction: !dev.IsSafeToEvict(),
+			Rules:           []*model.TranslationRule{rule},
 		}
 	}
 
@@ -191,18 +198,24 @@ func loadServiceTranslations(ctx context.Context, dev *model.Dev, result map[str
 
 		if _, ok := result[d.Name]; ok {
 			result[d.Name].Rules = append(result[d.Name].Rules, rule)
+			if s.Replicas != nil {
+				result[d.Name].DevReplicas = s.Replicas
+			}
 			continue
 		}
 
 		result[d.Name] = &model.Translation{
-			Name:        dev.Name,
-			Interactive: false,
-			Version:     model.TranslationVersion,
-			Deployment:  d,
-			Annotations: dev.Annotations,
-			Tolerations: dev.Tolerations,
-			Replicas:    *d.Spec.Replicas,
-			Rules:       []*model.TranslationRule{rule},
+			Name:            dev.Name,
+			Interactive:     false,
+			Version:         model.TranslationVersion,
+			Deployment:      d,
+			Annotations:     dev.Annotations,
+			Tolerations:     dev.ToTolerations(),
+			Replicas:        *d.Spec.Replicas,
+			DevReplicas:     s.Replicas,
+			SkipAffinity:    dev.SkipPodAffinity,
+			BackupExclusion: dev.BackupExclusionEnabled(),
+			Rules:           []*model.TranslationRule{rule},
 		}
 
 	}
@@ -211,11 +224,15 @@ func loadServiceTranslations(ctx context.Context, dev *model.Dev, result map[str
 }
 
 //Deploy creates or updates a deployment
-func Deploy(ctx context.Context, d *appsv1.Deployment, forceCreate bool, client *kubernetes.Clientset) error {
+func Deploy(ctx context.Context, d *appsv1.Deployment, forceCreate bool, strategy string, client kubernetes.Interface) error {
 	if forceCreate {
 		if err := create(ctx, d, client); err != nil {
 			return err
 		}
+	} else if strategy == model.DeployStrategyPatch {
+		if err := patch(ctx, d, client); err != nil {
+			return err
+		}
 	} else {
 		if err := update(ctx, d, client); err != nil {
 			return err
@@ -314,8 +331,93 @@ func UpdateDeployments(ctx context.Context, trList map[string]*model.Translation
 	return nil
 }
 
-//TranslateDevModeOff reverses the dev mode translation
-func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
+//GetOriginal returns the original deployment stored in the annotations of a deployment in dev mode
+func GetOriginal(d *appsv1.Deployment) (*appsv1.Deployment, error) {
+	dManifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
+	if dManifest == "" {
+		return nil, fmt.Errorf("original spec of deployment '%s' %w", d.Name, errors.ErrNotFound)
+	}
+	dOrig := &appsv1.Deployment{}
+	if err := json.Unmarshal([]byte(dManifest), dOrig); err != nil {
+		return nil, fmt.Errorf("malformed manifest: %s", err)
+	}
+	return dOrig, nil
+}
+
+//CheckRestored compares the replicas, images and resources of a restored deployment against its original spec and returns the mismatches found
+func CheckRestored(d, dOrig *appsv1.Deployment) []string {
+	mismatches := []string{}
+	if getReplicas(d) != getReplicas(dOrig) {
+		mismatches = append(mismatches, fmt.Sprintf("replicas is %d instead of %d", getReplicas(d), getReplicas(dOrig)))
+	}
+	for i := range dOrig.Spec.Template.Spec.Containers {
+		cOrig := &dOrig.Spec.Template.Spec.Containers[i]
+		c := GetDevContainer(&d.Spec.Template.Spec, cOrig.Name)
+		if c == nil {
+			mismatches = append(mismatches, fmt.Sprintf("container '%s' is missing", cOrig.Name))
+			continue
+		}
+		if c.Image != cOrig.Image {
+			mismatches = append(mismatches, fmt.Sprintf("image of container '%s' is '%s' instead of '%s'", c.Name, c.Image, cOrig.Image))
+		}
+		if !equality.Semantic.DeepEqual(c.Resources, cOrig.Resources) {
+			mismatches = append(mismatches, fmt.Sprintf("resources of container '%s' don't match the original ones", c.Name))
+		}
+	}
+	return mismatches
+}
+
+func getReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
+//GetOriginalYAML returns the original deployment stored in the annotations of a deployment in dev mode as YAML
+func GetOriginalYAML(d *appsv1.Deployment) ([]byte, error) {
+	dOrig, err := GetOriginal(d)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(dOrig)
+}
+
+//mergePreservedAnnotations copies the preserved annotations of the live deployment into the restored one.
+//Keys ending in '*' match any annotation with that prefix. Okteto annotations are never preserved
+func mergePreservedAnnotations(restored, live *appsv1.Deployment, preserve []string) {
+	if len(preserve) == 0 {
+		return
+	}
+	for key, value := range live.GetObjectMeta().GetAnnotations() {
+		if strings.HasPrefix(key, okLabels.DevLabel+"/") {
+			continue
+		}
+		if !isPreservedAnnotation(key, preserve) {
+			continue
+		}
+		setAnnotation(restored.GetObjectMeta(), key, value)
+	}
+}
+
+func isPreservedAnnotation(key string, preserve []string) bool {
+	for _, p := range preserve {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+			continue
+		}
+		if key == p {
+			return true
+		}
+	}
+	return false
+}
+
+//TranslateDevModeOff reverses the dev mode translation.
+//The annotations listed in preserve are kept from the live deployment when the original one is restored
+func TranslateDevModeOff(d *appsv1.Deployment, preserve []string) (*appsv1.Deployment, error) {
 	trRulesJSON := getAnnotation(d.Spec.Template.GetObjectMeta(), okLabels.TranslationAnnotation)
 	if trRulesJSON == "" {
 		dManifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
@@ -327,6 +429,7 @@ func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
 		if err := json.Unmarshal([]byte(dManifest), dOrig); err != nil {
 			return nil, fmt.Errorf("malformed manifest: %s", err)
 		}
+		mergePreservedAnnotations(dOrig, d, preserve)
 		return dOrig, nil
 	}
 	trRules := &model.Translation{}
@@ -334,6 +437,13 @@ func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
 		return nil, fmt.Errorf("malformed tr rules: %s", err)
 	}
 	d.Spec.Replicas = &trRules.Replicas
+	d.Spec.Paused = trRules.Paused
+	d.Spec.MinReadySeconds = trRules.MinReadySeconds
+	if trRules.HistoryLimit != nil {
+		d.Spec.RevisionHistoryLimit = trRules.HistoryLimit
+	} else if d.Spec.RevisionHistoryLimit != nil && *d.Spec.RevisionHistoryLimit == devRevisionHistoryLimit {
+		d.Spec.RevisionHistoryLimit = nil
+	}
 	annotations := d.GetObjectMeta().GetAnnotations()
 	delete(annotations, oktetoVersionAnnotation)
 	if err := deleteUserAnnotations(annotations, trRules); err != nil {
@@ -356,7 +466,7 @@ func TranslateDevModeOff(d *appsv1.Deployment) (*appsv1.Deployment, error) {
 	return d, nil
 }
 
-func create(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset) error {
+func create(ctx context.Context, d *appsv1.Deployment, c kubernetes.Interface) error {
 	_, err := c.AppsV1().Deployments(d.Namespace).Create(ctx, d, metav1.CreateOptions{})
 	if err != nil {
 		return err
@@ -364,7 +474,7 @@ func create(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset)
 	return nil
 }
 
-func update(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset) error {
+func update(ctx context.Context, d *appsv1.Deployment, c kubernetes.Interface) error {
 	d.ResourceVersion = ""
 	d.Status = appsv1.DeploymentStatus{}
 	_, err := c.AppsV1().Deployments(d.Namespace).Update(ctx, d, metav1.UpdateOptions{})
@@ -374,6 +484,61 @@ func update(ctx context.Context, d *appsv1.Deployment, c *kubernetes.Clientset)
 	return nil
 }
 
+//patch applies to the live deployment only the changes made by okteto since the last applied deployment.
+//The base of the patch is the last applied dev deployment if the live deployment is in dev mode, or the stored original deployment otherwise
+func patch(ctx context.Context, d *appsv1.Deployment, c kubernetes.Interface) error {
+	manifest := getAnnotation(d.GetObjectMeta(), oktetoDeploymentAnnotation)
+	if manifest == "" {
+		log.Infof("deployment '%s' has no original manifest, replacing it", d.Name)
+		return update(ctx, d, c)
+	}
+	live, err := c.AppsV1().Deployments(d.Namespace).Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if IsDevModeOn(live) {
+		manifest = getAnnotation(live.GetObjectMeta(), oktetoLastAppliedAnnotation)
+		if manifest == "" {
+			log.Infof("deployment '%s' has no last applied manifest, replacing it", d.Name)
+			return update(ctx, d, c)
+		}
+	}
+	dOrig := &appsv1.Deployment{}
+	if err := json.Unmarshal([]byte(manifest), dOrig); err != nil {
+		return fmt.Errorf("malformed manifest: %s", err)
+	}
+	dOrig.ResourceVersion = ""
+	dOrig.Status = appsv1.DeploymentStatus{}
+	dModified := d.DeepCopy()
+	dModified.ResourceVersion = ""
+	dModified.Status = appsv1.DeploymentStatus{}
+	live.ResourceVersion = ""
+	live.Status = appsv1.DeploymentStatus{}
+
+	origBytes, err := json.Marshal(dOrig)
+	if err != nil {
+		return err
+	}
+	modifiedBytes, err := json.Marshal(dModified)
+	if err != nil {
+		return err
+	}
+	liveBytes, err := json.Marshal(live)
+	if err != nil {
+		return err
+	}
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(appsv1.Deployment{})
+	if err != nil {
+		return err
+	}
+	patchBytes, err := strategicpatch.CreateThreeWayMergePatch(origBytes, modifiedBytes, liveBytes, patchMeta, true)
+	if err != nil {
+		return fmt.Errorf("error creating deployment patch: %s", err)
+	}
+	_, err = c.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
 func deleteUserAnnotations(annotations map[string]string, tr *model.Translation) error {
 	if tr.Annotations == nil {
 		return nil
@@ -390,7 +555,7 @@ func DestroyDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) er
 }
 
 //Destroy destroys a k8s deployment
-func Destroy(ctx context.Context, name, namespace string, c *kubernetes.Clientset) error {
+func Destroy(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
 	log.Infof("deleting deployment '%s'", name)
 	dClient := c.AppsV1().Deployments(namespace)
 	err := dClient.Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &devTerminationGracePeriodSeconds})
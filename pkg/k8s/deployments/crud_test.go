@@ -15,10 +15,13 @@ package deployments
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/okteto/okteto/pkg/errors"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"github.com/okteto/okteto/pkg/model"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
@@ -190,3 +193,332 @@ func TestCheckConditionErrors(t *testing.T) {
 	}
 
 }
+
+func TestGetOriginalYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    string
+		expectErr   bool
+		notFound    bool
+	}{
+		{
+			name:        "stored",
+			annotations: map[string]string{oktetoDeploymentAnnotation: `{"metadata":{"name":"web"},"spec":{"replicas":2}}`},
+			expected:    "metadata:\n  creationTimestamp: null\n  name: web\nspec:\n  replicas: 2\n  selector: null\n  strategy: {}\n  template:\n    metadata:\n      creationTimestamp: null\n    spec:\n      containers: null\nstatus: {}\n",
+		},
+		{
+			name:      "missing",
+			expectErr: true,
+			notFound:  true,
+		},
+		{
+			name:        "malformed",
+			annotations: map[string]string{oktetoDeploymentAnnotation: `{"metadata":`},
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "web",
+					Namespace:   "test",
+					Annotations: tt.annotations,
+				},
+			}
+			result, err := GetOriginalYAML(d)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if errors.IsNotFound(err) != tt.notFound {
+					t.Fatalf("wrong error: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("wrong yaml:\n%s", string(result))
+			}
+		})
+	}
+}
+
+func TestCheckRestored(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+image: okteto/dev
+resources:
+  requests:
+    cpu: 1
+sync:
+  - .:/app`)
+	tests := []struct {
+		name       string
+		modify     func(d *appsv1.Deployment)
+		mismatches int
+	}{
+		{
+			name:       "restored",
+			modify:     func(d *appsv1.Deployment) {},
+			mismatches: 0,
+		},
+		{
+			name: "wrong-image",
+			modify: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Image = "okteto/other"
+			},
+			mismatches: 1,
+		},
+		{
+			name: "wrong-replicas-and-resources",
+			modify: func(d *appsv1.Deployment) {
+				replicas := int32(0)
+				d.Spec.Replicas = &replicas
+				d.Spec.Template.Spec.Containers[0].Resources.Requests = apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("1"),
+				}
+			},
+			mismatches: 2,
+		},
+		{
+			name: "missing-container",
+			modify: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Name = "other"
+			},
+			mismatches: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+			dOrig, err := GetOriginal(tr.Deployment)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			restored, err := TranslateDevModeOff(tr.Deployment, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(restored)
+
+			mismatches := CheckRestored(restored, dOrig)
+			if len(mismatches) != tt.mismatches {
+				t.Errorf("expected %d mismatches, got %v", tt.mismatches, mismatches)
+			}
+		})
+	}
+}
+
+func TestTranslateDevModeOffPreservedAnnotations(t *testing.T) {
+	original := `{"metadata":{"name":"web","namespace":"test","annotations":{"app":"original","team":"a"}}}`
+	tests := []struct {
+		name     string
+		preserve []string
+		expected map[string]string
+	}{
+		{
+			name:     "no-preserved-annotations",
+			preserve: nil,
+			expected: map[string]string{"app": "original", "team": "a"},
+		},
+		{
+			name:     "preserved-key",
+			preserve: []string{"operator.io/managed", "team"},
+			expected: map[string]string{"app": "original", "team": "b", "operator.io/managed": "true"},
+		},
+		{
+			name:     "preserved-prefix",
+			preserve: []string{"operator.io/*"},
+			expected: map[string]string{"app": "original", "team": "a", "operator.io/managed": "true", "operator.io/generation": "3"},
+		},
+		{
+			name:     "okteto-annotations-are-not-preserved",
+			preserve: []string{"dev.okteto.com/*"},
+			expected: map[string]string{"app": "original", "team": "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web",
+					Namespace: "test",
+					Annotations: map[string]string{
+						oktetoDeploymentAnnotation: original,
+						oktetoVersionAnnotation:    "1.0",
+						"app":                      "dev",
+						"team":                     "b",
+						"operator.io/managed":      "true",
+						"operator.io/generation":   "3",
+					},
+				},
+			}
+			result, err := TranslateDevModeOff(d, tt.preserve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Annotations, tt.expected) {
+				t.Errorf("wrong annotations: expected %v, got %v", tt.expected, result.Annotations)
+			}
+		})
+	}
+}
+
+func TestDeployStrategy(t *testing.T) {
+	var tests = []struct {
+		name               string
+		strategy           string
+		expectedAnnotation string
+	}{
+		{
+			name:               "replace",
+			strategy:           model.DeployStrategyReplace,
+			expectedAnnotation: "",
+		},
+		{
+			name:               "patch",
+			strategy:           model.DeployStrategyPatch,
+			expectedAnnotation: "value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			original := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+			}
+			manifest, err := json.Marshal(original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			live := original.DeepCopy()
+			live.Annotations = map[string]string{"controller/managed": "value"}
+			c := fake.NewSimpleClientset(live)
+
+			d := original.DeepCopy()
+			d.Annotations = map[string]string{oktetoDeploymentAnnotation: string(manifest)}
+			d.Labels = map[string]string{okLabels.DevLabel: "true"}
+			if err := Deploy(ctx, d, false, tt.strategy, c); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := c.AppsV1().Deployments("test").Get(ctx, "test", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Annotations["controller/managed"] != tt.expectedAnnotation {
+				t.Errorf("wrong controller annotation: '%s'", result.Annotations["controller/managed"])
+			}
+			if result.Annotations[oktetoDeploymentAnnotation] != string(manifest) {
+				t.Errorf("original manifest annotation not applied")
+			}
+			if result.Labels[okLabels.DevLabel] != "true" {
+				t.Errorf("dev label not applied")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%s-twice", tt.name), func(t *testing.T) {
+			ctx := context.Background()
+			first, err := model.Read([]byte(`name: web
+namespace: n
+environment:
+  - FOO=bar
+podLabels:
+  foo: bar`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := model.Read([]byte(`name: web
+namespace: n`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			live := first.GevSandbox()
+			c := fake.NewSimpleClientset(live)
+
+			for _, dev := range []*model.Dev{first, second} {
+				d, err := c.AppsV1().Deployments(live.Namespace).Get(ctx, live.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				tr := &model.Translation{
+					Interactive: true,
+					Name:        dev.Name,
+					Version:     model.TranslationVersion,
+					Deployment:  d,
+					Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+				}
+				if err := translate(tr, nil, false); err != nil {
+					t.Fatal(err)
+				}
+				if err := Deploy(ctx, tr.Deployment, false, tt.strategy, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := c.AppsV1().Deployments(live.Namespace).Get(ctx, live.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, env := range result.Spec.Template.Spec.Containers[0].Env {
+				if env.Name == "FOO" {
+					t.Errorf("env var of the previous translation was not removed")
+				}
+			}
+			if _, ok := result.Spec.Template.Labels["foo"]; ok {
+				t.Errorf("pod label of the previous translation was not removed")
+			}
+		})
+	}
+}
+
+func TestPatchKeepsDeployment(t *testing.T) {
+	ctx := context.Background()
+	original := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+	manifest, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewSimpleClientset(original.DeepCopy())
+
+	d := original.DeepCopy()
+	d.ResourceVersion = "5"
+	d.Annotations = map[string]string{oktetoDeploymentAnnotation: string(manifest)}
+	d.Status = appsv1.DeploymentStatus{Replicas: 1}
+	if err := patch(ctx, d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.ResourceVersion != "5" {
+		t.Errorf("patch modified the resource version of the deployment: '%s'", d.ResourceVersion)
+	}
+	if d.Status.Replicas != 1 {
+		t.Errorf("patch modified the status of the deployment: %+v", d.Status)
+	}
+}
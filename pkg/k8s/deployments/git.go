@@ -0,0 +1,43 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"github.com/go-git/go-git/v5"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+)
+
+//GetGitAnnotations returns the annotations with the git commit and branch of the repository containing path
+func GetGitAnnotations(path string) map[string]string {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		log.Infof("'%s' is not a git repository, skipping git annotations: %s", path, err)
+		return nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		log.Infof("failed to get the git head of '%s', skipping git annotations: %s", path, err)
+		return nil
+	}
+
+	annotations := map[string]string{
+		okLabels.GitCommitAnnotation: head.Hash().String(),
+	}
+	if head.Name().IsBranch() {
+		annotations[okLabels.GitBranchAnnotation] = head.Name().Short()
+	}
+	return annotations
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func TestGetGitAnnotations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if annotations := GetGitAnnotations(dir); annotations != nil {
+		t.Errorf("expected no annotations outside of a git repo, got %v", annotations)
+	}
+
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotations := GetGitAnnotations(dir); annotations != nil {
+		t.Errorf("expected no annotations without commits, got %v", annotations)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("src/main.go"); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "John Doe", Email: "john@doe.org", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := plumbing.NewHashReference("refs/heads/feature/login", commit)
+	if err := r.Storer.SetReference(ref); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: ref.Name()}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		okLabels.GitCommitAnnotation: commit.String(),
+		okLabels.GitBranchAnnotation: "feature/login",
+	}
+	if annotations := GetGitAnnotations(filepath.Join(dir, "src")); !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("wrong git annotations: %v", annotations)
+	}
+
+	if err := w.Checkout(&git.CheckoutOptions{Hash: commit}); err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]string{okLabels.GitCommitAnnotation: commit.String()}
+	if annotations := GetGitAnnotations(dir); !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("wrong git annotations on a detached head: %v", annotations)
+	}
+}
+
+func Test_translateGitAnnotations(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+gitAnnotations: true
+sync:
+  - .:/app`)
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive:    true,
+		Name:           dev.Name,
+		Version:        model.TranslationVersion,
+		Deployment:     d,
+		GitAnnotations: map[string]string{okLabels.GitCommitAnnotation: "abc123", okLabels.GitBranchAnnotation: "main"},
+		Rules:          []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	annotations := tr.Deployment.Spec.Template.Annotations
+	if annotations[okLabels.GitCommitAnnotation] != "abc123" || annotations[okLabels.GitBranchAnnotation] != "main" {
+		t.Errorf("git annotations not applied to the pod template: %v", annotations)
+	}
+	if _, ok := tr.Deployment.Annotations[okLabels.GitCommitAnnotation]; ok {
+		t.Errorf("git annotations applied to the deployment: %v", tr.Deployment.Annotations)
+	}
+}
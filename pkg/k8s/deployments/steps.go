@@ -0,0 +1,69 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+//stepRecorder records the translation steps that change the pod template of a deployment
+type stepRecorder struct {
+	t    *model.Translation
+	last *apiv1.PodTemplateSpec
+}
+
+func newStepRecorder(t *model.Translation) *stepRecorder {
+	if !t.RecordSteps {
+		return nil
+	}
+	return &stepRecorder{t: t, last: t.Deployment.Spec.Template.DeepCopy()}
+}
+
+//record adds a step to the translation if the pod template changed since the previous step
+func (r *stepRecorder) record(name, container string) {
+	if r == nil {
+		return
+	}
+	current := &r.t.Deployment.Spec.Template
+	if fields := changedFields(r.last, current); len(fields) > 0 {
+		r.t.Steps = append(r.t.Steps, model.TranslationStep{Name: name, Container: container, Fields: fields})
+	}
+	r.last = current.DeepCopy()
+}
+
+func changedFields(before, after *apiv1.PodTemplateSpec) []string {
+	fields := []string{}
+	if !apiequality.Semantic.DeepEqual(before.Labels, after.Labels) {
+		fields = append(fields, "metadata.labels")
+	}
+	if !apiequality.Semantic.DeepEqual(before.Annotations, after.Annotations) {
+		fields = append(fields, "metadata.annotations")
+	}
+
+	b := reflect.ValueOf(before.Spec)
+	a := reflect.ValueOf(after.Spec)
+	for i := 0; i < b.NumField(); i++ {
+		if apiequality.Semantic.DeepEqual(b.Field(i).Interface(), a.Field(i).Interface()) {
+			continue
+		}
+		name := strings.Split(b.Type().Field(i).Tag.Get("json"), ",")[0]
+		fields = append(fields, "spec."+name)
+	}
+	return fields
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+func Test_translateSteps(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+hostname: web-dev
+sync:
+  - .:/app`)
+
+	var tests = []struct {
+		name        string
+		recordSteps bool
+		expected    []model.TranslationStep
+	}{
+		{
+			name:        "disabled",
+			recordSteps: false,
+			expected:    nil,
+		},
+		{
+			name:        "enabled",
+			recordSteps: true,
+			expected: []model.TranslationStep{
+				{Name: "commonTranslation", Fields: []string{"metadata.labels"}},
+				{Name: "setDevLabel", Fields: []string{"metadata.labels"}},
+				{Name: "TranslateOktetoSyncSecret", Fields: []string{"spec.volumes"}},
+				{Name: "TranslateDevContainer", Container: "dev", Fields: []string{"spec.containers"}},
+				{Name: "TranslateDevContainerTTY", Container: "dev", Fields: []string{"spec.containers"}},
+				{Name: "TranslateOktetoVolumes", Container: "dev", Fields: []string{"spec.volumes"}},
+				{Name: "TranslatePodSecurityContext", Container: "dev", Fields: []string{"spec.securityContext"}},
+				{Name: "TranslatePodHostname", Container: "dev", Fields: []string{"spec.hostname"}},
+				{Name: "TranslateOktetoBinVolumeMounts", Container: "dev", Fields: []string{"spec.containers"}},
+				{Name: "TranslateOktetoInitBinContainer", Container: "dev", Fields: []string{"spec.initContainers"}},
+				{Name: "TranslateOktetoBinVolume", Container: "dev", Fields: []string{"spec.volumes"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  dev.GevSandbox(),
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+				RecordSteps: tt.recordSteps,
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tr.Steps, tt.expected) {
+				t.Errorf("wrong translation steps:\n%v", tr.Steps)
+			}
+		})
+	}
+}
+
+func TestTranslationStepString(t *testing.T) {
+	s := model.TranslationStep{Name: "TranslatePodHostname", Container: "dev", Fields: []string{"spec.hostname", "spec.subdomain"}}
+	if s.String() != "TranslatePodHostname [dev]: spec.hostname, spec.subdomain" {
+		t.Errorf("wrong step description: %s", s)
+	}
+	s = model.TranslationStep{Name: "setDevLabel", Fields: []string{"metadata.labels"}}
+	if s.String() != "setDevLabel: metadata.labels" {
+		t.Errorf("wrong step description: %s", s)
+	}
+}
@@ -17,8 +17,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/k8s/namespaces"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 
@@ -33,6 +39,18 @@ const (
 	oktetoDeploymentAnnotation = "dev.okteto.com/deployment"
 	oktetoVersionAnnotation    = "dev.okteto.com/version"
 	revisionAnnotation         = "deployment.kubernetes.io/revision"
+
+	//oktetoLastAppliedAnnotation is the annotation with the last dev deployment applied by okteto, used as the base of the patch strategy
+	oktetoLastAppliedAnnotation = "dev.okteto.com/last-applied"
+
+	//defaultContainerAnnotation and defaultLogsContainerAnnotation select the container used by kubectl
+	defaultContainerAnnotation     = "kubectl.kubernetes.io/default-container"
+	defaultLogsContainerAnnotation = "kubectl.kubernetes.io/default-logs-container"
+
+	//backupVolumesExcludesAnnotation lists the pod volumes skipped by velero backups
+	backupVolumesExcludesAnnotation = "backup.velero.io/backup-volumes-excludes"
+	//safeToEvictAnnotation tells the cluster autoscaler if it can evict a pod when scaling down nodes
+	safeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
 	//OktetoBinName name of the okteto bin init container
 	OktetoBinName = "okteto-bin"
 
@@ -40,13 +58,50 @@ const (
 	oktetoSyncSecretVolume = "okteto-sync-secret" // skipcq GSC-G101  not a secret
 	oktetoDevSecretVolume  = "okteto-dev-secret"  // skipcq GSC-G101  not a secret
 	oktetoSecretTemplate   = "okteto-%s"
+
+	//oktetoOverlayVolumeTemplate name of the emptyDir volumes mounted over the synced code
+	oktetoOverlayVolumeTemplate = "okteto-overlay-%d"
+
+	//oktetoLogFifoName name of the volume and init container of the log fifo
+	oktetoLogFifoName = "okteto-log-fifo"
+
+	//runtimeHeapPercentage percentage of the container memory limit given to the JVM and Node.js heaps
+	runtimeHeapPercentage = 75
+
+	//oktetoBusyboxPath path of the static busybox binary in the okteto bin image
+	oktetoBusyboxPath = "/bin/busybox"
+
+	//oktetoWaitForName name of the init container that waits for the dependencies of the dev container
+	oktetoWaitForName = "okteto-wait-for"
+
+	//oktetoHydrateName name of the init container that extracts an artifact into a new persistent volume
+	oktetoHydrateName    = "okteto-hydrate"
+	oktetoHydrateArchive = "/tmp/okteto-hydrate.tar.gz"
+	oktetoHydrateMarker  = ".okteto-hydrated"
+
+	//oktetoInotifyName name of the privileged init container that raises the inotify limits of the node
+	oktetoInotifyName = "okteto-inotify"
+
+	//defaultProbePeriodSeconds is the kubernetes default for probe.periodSeconds
+	defaultProbePeriodSeconds = 10
+
+	//maxDNSNameservers and maxDNSSearches are the kubernetes limits of a pod dns config
+	maxDNSNameservers = 3
+	maxDNSSearches    = 6
 )
 
 var (
 	devReplicas                      int32 = 1
+	devRevisionHistoryLimit          int32 = 2
 	devTerminationGracePeriodSeconds int64
 	falseBoolean                     = false
 
+	//semverTagRegex matches full semver image tags like '1.2.3', 'v1.2.3' or '1.2.3-alpine'
+	semverTagRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+
+	//getLocalUserIDs returns the uid and gid of the local user, which are -1 on platforms without them
+	getLocalUserIDs = func() (int64, int64) { return int64(os.Getuid()), int64(os.Getgid()) }
+
 	//OktetoUpInitContainerRequestsCPU cpu requests used by the up init container
 	OktetoUpInitContainerRequestsCPU = resource.MustParse("10m")
 	//OktetoUpInitContainerRequestsMemory memory requests used by the up init container
@@ -58,14 +113,6 @@ var (
 )
 
 func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace bool) error {
-	for _, rule := range t.Rules {
-		devContainer := GetDevContainer(&t.Deployment.Spec.Template.Spec, rule.Container)
-		if devContainer == nil {
-			return fmt.Errorf("Container '%s' not found in deployment '%s'", rule.Container, t.Deployment.Name)
-		}
-		rule.Container = devContainer.Name
-	}
-
 	manifest := getAnnotation(t.Deployment.GetObjectMeta(), oktetoDeploymentAnnotation)
 	if manifest != "" {
 		dOrig := &appsv1.Deployment{}
@@ -74,6 +121,14 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 		}
 		t.Deployment = dOrig
 	}
+
+	for _, rule := range t.Rules {
+		devContainer := GetDevContainer(&t.Deployment.Spec.Template.Spec, rule.Container)
+		if devContainer == nil {
+			return fmt.Errorf("Container '%s' not found in deployment '%s'", rule.Container, t.Deployment.Name)
+		}
+		rule.Container = devContainer.Name
+	}
 	annotations := t.Deployment.GetObjectMeta().GetAnnotations()
 	delete(annotations, revisionAnnotation)
 	t.Deployment.GetObjectMeta().SetAnnotations(annotations)
@@ -95,35 +150,143 @@ func translate(t *model.Translation, c *kubernetes.Clientset, isOktetoNamespace
 	}
 	setAnnotation(t.Deployment.GetObjectMeta(), oktetoDeploymentAnnotation, string(manifestBytes))
 
+	originalVolumes := getVolumeNames(&t.Deployment.Spec.Template.Spec)
+	steps := newStepRecorder(t)
 	commonTranslation(t)
+	steps.record("commonTranslation", "")
 	setLabel(t.Deployment.Spec.Template.GetObjectMeta(), okLabels.DevLabel, "true")
+	steps.record("setDevLabel", "")
 	TranslateDevAnnotations(t.Deployment.Spec.Template.GetObjectMeta(), t.Annotations)
+	TranslateDevAnnotations(t.Deployment.Spec.Template.GetObjectMeta(), t.GitAnnotations)
+	steps.record("TranslateDevAnnotations", "")
 	TranslateDevTolerations(&t.Deployment.Spec.Template.Spec, t.Tolerations)
+	steps.record("TranslateDevTolerations", "")
 	t.Deployment.Spec.Template.Spec.TerminationGracePeriodSeconds = &devTerminationGracePeriodSeconds
+	steps.record("setTerminationGracePeriodSeconds", "")
 
 	if t.Interactive {
 		TranslateOktetoSyncSecret(&t.Deployment.Spec.Template.Spec, t.Name)
-	} else {
+		steps.record("TranslateOktetoSyncSecret", "")
+	} else if !t.SkipAffinity {
 		TranslatePodAffinity(&t.Deployment.Spec.Template.Spec, t.Name)
+		steps.record("TranslatePodAffinity", "")
 	}
 	for _, rule := range t.Rules {
 		devContainer := GetDevContainer(&t.Deployment.Spec.Template.Spec, rule.Container)
 		if devContainer == nil {
 			return fmt.Errorf("Container '%s' not found in deployment '%s'", rule.Container, t.Deployment.Name)
 		}
+		readOnlyRootFilesystem := devContainer.SecurityContext != nil && devContainer.SecurityContext.ReadOnlyRootFilesystem != nil && *devContainer.SecurityContext.ReadOnlyRootFilesystem
 
 		TranslateDevContainer(devContainer, rule)
-		TranslateInitContainer(&rule.InitContainer)
+		steps.record("TranslateDevContainer", rule.Container)
+		if err := TranslateSidecarResources(&t.Deployment.Spec.Template.Spec, rule); err != nil {
+			return err
+		}
+		steps.record("TranslateSidecarResources", rule.Container)
+		if t.Interactive && rule.IsMainDevContainer() {
+			TranslateDevContainerTTY(devContainer, rule.StdinOnce)
+			steps.record("TranslateDevContainerTTY", rule.Container)
+		}
+		TranslateInitContainer(&rule.InitContainer, rule.RegistryRewrites)
 		TranslateOktetoVolumes(&t.Deployment.Spec.Template.Spec, rule)
+		steps.record("TranslateOktetoVolumes", rule.Container)
+		if err := TranslateProjectedVolumes(&t.Deployment.Spec.Template.Spec, devContainer, rule); err != nil {
+			return err
+		}
+		steps.record("TranslateProjectedVolumes", rule.Container)
 		TranslatePodSecurityContext(&t.Deployment.Spec.Template.Spec, rule.SecurityContext)
+		steps.record("TranslatePodSecurityContext", rule.Container)
 		TranslatePodServiceAccount(&t.Deployment.Spec.Template.Spec, rule.ServiceAccount)
+		steps.record("TranslatePodServiceAccount", rule.Container)
+		TranslatePodShareProcessNamespace(&t.Deployment.Spec.Template.Spec, rule.ShareProcessNamespace)
+		steps.record("TranslatePodShareProcessNamespace", rule.Container)
+		TranslatePodHostname(&t.Deployment.Spec.Template.Spec, rule.Hostname, rule.Subdomain)
+		steps.record("TranslatePodHostname", rule.Container)
+		TranslatePodReadinessGates(&t.Deployment.Spec.Template.Spec, rule.ReadinessGates)
+		steps.record("TranslatePodReadinessGates", rule.Container)
+		TranslatePodTopologySpreadConstraints(&t.Deployment.Spec.Template.Spec, rule.TopologySpread)
+		steps.record("TranslatePodTopologySpreadConstraints", rule.Container)
+		if err := TranslatePodDNSConfig(&t.Deployment.Spec.Template.Spec, rule.DNSConfig); err != nil {
+			return err
+		}
+		steps.record("TranslatePodDNSConfig", rule.Container)
+		if err := TranslatePodLabels(t.Deployment, rule.PodLabels); err != nil {
+			return err
+		}
+		steps.record("TranslatePodLabels", rule.Container)
 		TranslateOktetoDevSecret(&t.Deployment.Spec.Template.Spec, t.Name, rule.Secrets)
+		steps.record("TranslateOktetoDevSecret", rule.Container)
 		if rule.IsMainDevContainer() {
-			TranslateOktetoBinVolumeMounts(devContainer)
+			TranslateOktetoBinVolumeMounts(devContainer, rule.BinPath)
+			steps.record("TranslateOktetoBinVolumeMounts", rule.Container)
 			TranslateOktetoInitBinContainer(rule.InitContainer, &t.Deployment.Spec.Template.Spec)
+			steps.record("TranslateOktetoInitBinContainer", rule.Container)
+			TranslateInotifyInitContainer(&t.Deployment.Spec.Template.Spec, rule)
+			steps.record("TranslateInotifyInitContainer", rule.Container)
+			if err := TranslateHydrateInitContainer(&t.Deployment.Spec.Template.Spec, rule); err != nil {
+				return err
+			}
+			steps.record("TranslateHydrateInitContainer", rule.Container)
+			TranslateWaitForInitContainer(&t.Deployment.Spec.Template.Spec, rule)
+			steps.record("TranslateWaitForInitContainer", rule.Container)
 			TranslateOktetoBinVolume(&t.Deployment.Spec.Template.Spec)
+			steps.record("TranslateOktetoBinVolume", rule.Container)
+			TranslateLogFifo(&t.Deployment.Spec.Template.Spec, devContainer, rule)
+			steps.record("TranslateLogFifo", rule.Container)
+			TranslateOpenTelemetry(&t.Deployment.Spec.Template.Spec, rule.OpenTelemetry, rule.RegistryRewrites)
+			steps.record("TranslateOpenTelemetry", rule.Container)
+			TranslatePodTerminationGracePeriod(&t.Deployment.Spec.Template.Spec, rule.GracePeriodSeconds)
+			steps.record("TranslatePodTerminationGracePeriod", rule.Container)
 		}
+		if err := TranslateInitContainerVolumeMounts(&t.Deployment.Spec.Template.Spec, rule); err != nil {
+			return err
+		}
+		steps.record("TranslateInitContainerVolumeMounts", rule.Container)
+		if err := validateSecurityContext(&t.Deployment.Spec.Template.Spec, devContainer); err != nil {
+			return err
+		}
+		if readOnlyRootFilesystem && t.PodSecurity == namespaces.PodSecurityRestricted {
+			return fmt.Errorf("container '%s' sets 'readOnlyRootFilesystem' to true, but the development container needs a writable root filesystem and the namespace '%s' enforces the '%s' pod security standard", rule.Container, t.Deployment.Namespace, t.PodSecurity)
+		}
+		if err := validateVolumeMounts(devContainer); err != nil {
+			return err
+		}
+		if !t.AllowHostPath {
+			if err := validateHostPathVolumes(&t.Deployment.Spec.Template.Spec, rule); err != nil {
+				return err
+			}
+		}
+		if rule.ContainerSuffix != "" {
+			TranslateContainerName(&t.Deployment.Spec.Template, devContainer, rule.ContainerSuffix)
+			steps.record("TranslateContainerName", rule.Container)
+		}
+	}
+	if t.BackupExclusion {
+		TranslateBackupExclusion(&t.Deployment.Spec.Template, originalVolumes)
+		steps.record("TranslateBackupExclusion", "")
+	}
+	if t.PreventEviction {
+		TranslatePreventEviction(&t.Deployment.Spec.Template)
+		steps.record("TranslatePreventEviction", "")
+	}
+	return setLastAppliedAnnotation(t.Deployment)
+}
+
+//setLastAppliedAnnotation stores the translated deployment so the next translation is patched against it
+func setLastAppliedAnnotation(d *appsv1.Deployment) error {
+	applied := d.DeepCopy()
+	applied.ResourceVersion = ""
+	applied.Status = appsv1.DeploymentStatus{}
+	annotations := applied.GetObjectMeta().GetAnnotations()
+	delete(annotations, oktetoDeploymentAnnotation)
+	delete(annotations, oktetoLastAppliedAnnotation)
+	applied.GetObjectMeta().SetAnnotations(annotations)
+	appliedBytes, err := json.Marshal(applied)
+	if err != nil {
+		return err
 	}
+	setAnnotation(d.GetObjectMeta(), oktetoLastAppliedAnnotation, string(appliedBytes))
 	return nil
 }
 
@@ -138,7 +301,59 @@ func commonTranslation(t *model.Translation) {
 		setLabel(t.Deployment.Spec.Template.GetObjectMeta(), okLabels.DetachedDevLabel, t.Name)
 	}
 
-	t.Deployment.Spec.Replicas = &devReplicas
+	t.Deployment.Spec.Replicas = getDevReplicas(t)
+
+	if t.Deployment.Spec.Paused {
+		log.Yellow("Deployment '%s' is paused. It will be resumed while in development mode and paused again on 'okteto down'", t.Deployment.Name)
+		t.Paused = true
+		t.Deployment.Spec.Paused = false
+	}
+
+	if t.Deployment.Spec.MinReadySeconds > 0 {
+		t.MinReadySeconds = t.Deployment.Spec.MinReadySeconds
+		t.Deployment.Spec.MinReadySeconds = 0
+	}
+
+	//each okteto up creates a new replica set, keep only the latest ones while in development mode
+	t.HistoryLimit = t.Deployment.Spec.RevisionHistoryLimit
+	if t.HistoryLimit == nil || *t.HistoryLimit > devRevisionHistoryLimit {
+		t.Deployment.Spec.RevisionHistoryLimit = &devRevisionHistoryLimit
+	}
+}
+
+//TranslatePreventEviction keeps the cluster autoscaler from evicting the dev pod while scaling down nodes
+func TranslatePreventEviction(template *apiv1.PodTemplateSpec) {
+	setAnnotation(template.GetObjectMeta(), safeToEvictAnnotation, "false")
+}
+
+//TranslateBackupExclusion excludes from backups the volumes added to the pod template by the translation
+func TranslateBackupExclusion(template *apiv1.PodTemplateSpec, originalVolumes map[string]bool) {
+	excluded := []string{}
+	seen := map[string]bool{}
+	if current := getAnnotation(template.GetObjectMeta(), backupVolumesExcludesAnnotation); current != "" {
+		excluded = strings.Split(current, ",")
+		for _, name := range excluded {
+			seen[name] = true
+		}
+	}
+	for _, v := range template.Spec.Volumes {
+		if originalVolumes[v.Name] || seen[v.Name] {
+			continue
+		}
+		excluded = append(excluded, v.Name)
+	}
+	if len(excluded) == 0 {
+		return
+	}
+	setAnnotation(template.GetObjectMeta(), backupVolumesExcludesAnnotation, strings.Join(excluded, ","))
+}
+
+func getVolumeNames(spec *apiv1.PodSpec) map[string]bool {
+	result := map[string]bool{}
+	for _, v := range spec.Volumes {
+		result[v.Name] = true
+	}
+	return result
 }
 
 //GetDevContainer returns the dev container of a given deployment
@@ -156,6 +371,59 @@ func GetDevContainer(spec *apiv1.PodSpec, name string) *apiv1.Container {
 	return nil
 }
 
+//TranslateContainerName appends the suffix to the name of the dev container and updates the references to it in the pod template
+func TranslateContainerName(template *apiv1.PodTemplateSpec, c *apiv1.Container, suffix string) {
+	oldName := c.Name
+	newName := oldName + suffix
+	c.Name = newName
+
+	for _, key := range []string{defaultContainerAnnotation, defaultLogsContainerAnnotation} {
+		if getAnnotation(template.GetObjectMeta(), key) == oldName {
+			setAnnotation(template.GetObjectMeta(), key, newName)
+		}
+	}
+
+	spec := &template.Spec
+	for i := range spec.InitContainers {
+		renameEnvContainerRefs(spec.InitContainers[i].Env, oldName, newName)
+	}
+	for i := range spec.Containers {
+		renameEnvContainerRefs(spec.Containers[i].Env, oldName, newName)
+	}
+	for i := range spec.Volumes {
+		if spec.Volumes[i].DownwardAPI != nil {
+			renameDownwardAPIContainerRefs(spec.Volumes[i].DownwardAPI.Items, oldName, newName)
+		}
+		if spec.Volumes[i].Projected == nil {
+			continue
+		}
+		for _, source := range spec.Volumes[i].Projected.Sources {
+			if source.DownwardAPI != nil {
+				renameDownwardAPIContainerRefs(source.DownwardAPI.Items, oldName, newName)
+			}
+		}
+	}
+}
+
+func renameEnvContainerRefs(env []apiv1.EnvVar, oldName, newName string) {
+	for i := range env {
+		if env[i].ValueFrom == nil || env[i].ValueFrom.ResourceFieldRef == nil {
+			continue
+		}
+		if env[i].ValueFrom.ResourceFieldRef.ContainerName == oldName {
+			env[i].ValueFrom.ResourceFieldRef.ContainerName = newName
+		}
+	}
+}
+
+func renameDownwardAPIContainerRefs(items []apiv1.DownwardAPIVolumeFile, oldName, newName string) {
+	for i := range items {
+		if items[i].ResourceFieldRef != nil && items[i].ResourceFieldRef.ContainerName == oldName {
+			items[i].ResourceFieldRef.ContainerName = newName
+		}
+	}
+}
+
 //TranslateDevAnnotations sets the user provided annotations
 func TranslateDevAnnotations(o metav1.Object, annotations map[string]string) {
 	for key, value := range annotations {
@@ -192,13 +460,21 @@ func TranslatePodAffinity(spec *apiv1.PodSpec, name string) {
 	)
 }
 
-//TranslateDevContainer translates a dev container
+//TranslateDevContainer translates a dev container.
+//A rule command replaces both the command and the args of the container, clearing the args if the rule has none.
+//Rule args without a command only replace the args, preserving the original command
 func TranslateDevContainer(c *apiv1.Container, rule *model.TranslationRule) {
 	if rule.Image == "" {
 		rule.Image = c.Image
 	}
-	c.Image = rule.Image
+	c.Image = model.RewriteImage(rule.Image, rule.RegistryRewrites)
+	if rule.ImageDigest != "" {
+		c.Image = pinImageDigest(c.Image, rule.ImageDigest)
+	}
 	c.ImagePullPolicy = rule.ImagePullPolicy
+	if c.ImagePullPolicy == model.PullAuto {
+		c.ImagePullPolicy = getAutoPullPolicy(c.Image)
+	}
 
 	if rule.WorkDir != "" {
 		c.WorkingDir = rule.WorkDir
@@ -208,15 +484,50 @@ func TranslateDevContainer(c *apiv1.Container, rule *model.TranslationRule) {
 		c.Command = rule.Command
 		c.Args = rule.Args
 	}
+	if len(rule.Args) > 0 {
+		c.Args = rule.Args
+	}
 
-	TranslateProbes(c, *rule.Probes)
+	TranslateProbes(c, getContainerProbes(c.Name, rule))
+	TranslateLivenessGracePeriod(c, rule.LivenessGracePeriod)
+	TranslateLivenessFailureAction(c, rule.LivenessFailureAction)
 
 	TranslateResources(c, rule.Resources)
+	if rule.GPUDisabled {
+		TranslateDisableGPU(c)
+	}
+	TranslateContainerPorts(c, rule.ContainerPorts)
 	TranslateEnvVars(c, rule)
 	TranslateVolumeMounts(c, rule)
 	TranslateContainerSecurityContext(c, rule.SecurityContext)
 }
 
+//pinImageDigest replaces the tag of an image by its digest
+func pinImageDigest(image, digest string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return fmt.Sprintf("%s@%s", image, digest)
+}
+
+//getAutoPullPolicy returns 'IfNotPresent' for immutable image references (digests and semver tags) and 'Always' for floating tags
+func getAutoPullPolicy(image string) apiv1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return apiv1.PullIfNotPresent
+	}
+	i := strings.LastIndex(image, ":")
+	if i <= strings.LastIndex(image, "/") {
+		return apiv1.PullAlways
+	}
+	if semverTagRegex.MatchString(image[i+1:]) {
+		return apiv1.PullIfNotPresent
+	}
+	return apiv1.PullAlways
+}
+
 //TranslateProbes translates the healthchecks attached to a container
 func TranslateProbes(c *apiv1.Container, h model.Probes) {
 	if !h.Liveness {
@@ -230,7 +541,45 @@ func TranslateProbes(c *apiv1.Container, h model.Probes) {
 	}
 }
 
-func TranslateInitContainer(initContainer *model.InitContainer) {
+//getContainerProbes returns the probes to keep in a container: the override for the container if any, the rule probes otherwise
+func getContainerProbes(container string, rule *model.TranslationRule) model.Probes {
+	if probes, ok := rule.ContainerProbes[container]; ok {
+		return probes
+	}
+	return *rule.Probes
+}
+
+//TranslateLivenessGracePeriod gives the dev command time to (re)start before the liveness probe kills the container
+func TranslateLivenessGracePeriod(c *apiv1.Container, grace int32) {
+	if c.LivenessProbe == nil || grace <= 0 {
+		return
+	}
+	if c.LivenessProbe.InitialDelaySeconds < grace {
+		c.LivenessProbe.InitialDelaySeconds = grace
+	}
+
+	period := c.LivenessProbe.PeriodSeconds
+	if period <= 0 {
+		period = defaultProbePeriodSeconds
+	}
+	threshold := (grace + period - 1) / period
+	if c.LivenessProbe.FailureThreshold < threshold {
+		c.LivenessProbe.FailureThreshold = threshold
+	}
+}
+
+//TranslateLivenessFailureAction removes the liveness probe when liveness failures must not restart the container.
+//The probe is removed instead of replaced because the image of the container might not have any command to run it
+func TranslateLivenessFailureAction(c *apiv1.Container, action string) {
+	if action != model.LivenessFailureActionIgnore {
+		return
+	}
+	c.LivenessProbe = nil
+}
+
+//TranslateInitContainer sets the image and the default resources of the okteto init container
+func TranslateInitContainer(initContainer *model.InitContainer, rewrites []model.RegistryRewrite) {
+	initContainer.Image = model.RewriteImage(initContainer.Image, rewrites)
 	if initContainer.Resources.Limits == nil {
 		initContainer.Resources.Limits = make(map[apiv1.ResourceName]resource.Quantity)
 	}
@@ -250,66 +599,185 @@ func setDefaultResourceValueIfNotPresent(resourceList model.ResourceList, resour
 	}
 }
 
-//TranslateResources translates the resources attached to a container
+//TranslateResources copies every resource requested in the manifest (cpu, memory, gpus or any extended resource) to a container
 func TranslateResources(c *apiv1.Container, r model.ResourceRequirements) {
 	if c.Resources.Requests == nil {
 		c.Resources.Requests = make(map[apiv1.ResourceName]resource.Quantity)
 	}
 
-	if v, ok := r.Requests[apiv1.ResourceMemory]; ok {
-		c.Resources.Requests[apiv1.ResourceMemory] = v
+	for name, v := range r.Requests {
+		c.Resources.Requests[name] = v
 	}
 
-	if v, ok := r.Requests[apiv1.ResourceCPU]; ok {
-		c.Resources.Requests[apiv1.ResourceCPU] = v
+	if c.Resources.Limits == nil {
+		c.Resources.Limits = make(map[apiv1.ResourceName]resource.Quantity)
 	}
 
-	if v, ok := r.Requests[model.ResourceAMDGPU]; ok {
-		c.Resources.Requests[model.ResourceAMDGPU] = v
+	for name, v := range r.Limits {
+		c.Resources.Limits[name] = v
 	}
+}
 
-	if v, ok := r.Requests[model.ResourceNVIDIAGPU]; ok {
-		c.Resources.Requests[model.ResourceNVIDIAGPU] = v
+//getDevReplicas returns the replicas of a deployment in dev mode. Only detached translations can run more than one replica
+func getDevReplicas(t *model.Translation) *int32 {
+	if t.DevReplicas == nil {
+		return &devReplicas
 	}
-
-	if c.Resources.Limits == nil {
-		c.Resources.Limits = make(map[apiv1.ResourceName]resource.Quantity)
+	if t.Interactive {
+		if *t.DevReplicas != devReplicas {
+			log.Yellow("'replicas' is ignored for '%s': interactive development containers always run one replica", t.Name)
+		}
+		return &devReplicas
 	}
+	replicas := *t.DevReplicas
+	return &replicas
+}
 
-	if v, ok := r.Limits[apiv1.ResourceMemory]; ok {
-		c.Resources.Limits[apiv1.ResourceMemory] = v
+//TranslateDevContainerTTY allocates stdin and a tty to the dev container so interactive sessions can attach to it.
+//If stdinOnce is set, stdin is closed after the first attached session disconnects
+func TranslateDevContainerTTY(c *apiv1.Container, stdinOnce bool) {
+	c.Stdin = true
+	c.StdinOnce = stdinOnce
+	c.TTY = true
+}
+
+//TranslateDisableGPU removes the GPU requests and limits of a container so it can be scheduled on nodes without GPUs
+func TranslateDisableGPU(c *apiv1.Container) {
+	for _, gpu := range []apiv1.ResourceName{model.ResourceAMDGPU, model.ResourceNVIDIAGPU} {
+		delete(c.Resources.Requests, gpu)
+		delete(c.Resources.Limits, gpu)
 	}
+}
 
-	if v, ok := r.Limits[apiv1.ResourceCPU]; ok {
-		c.Resources.Limits[apiv1.ResourceCPU] = v
+//TranslateSidecarResources overrides the resources of the non-dev containers listed in the rule
+func TranslateSidecarResources(spec *apiv1.PodSpec, rule *model.TranslationRule) error {
+	names := make([]string, 0, len(rule.SidecarResources))
+	for name := range rule.SidecarResources {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	if v, ok := r.Limits[model.ResourceAMDGPU]; ok {
-		c.Resources.Limits[model.ResourceAMDGPU] = v
+	for _, name := range names {
+		c := GetDevContainer(spec, name)
+		if c == nil {
+			return fmt.Errorf("Container '%s' of 'sidecarResources' not found in deployment", name)
+		}
+		TranslateResources(c, rule.SidecarResources[name])
 	}
+	return nil
+}
 
-	if v, ok := r.Limits[model.ResourceNVIDIAGPU]; ok {
-		c.Resources.Limits[model.ResourceNVIDIAGPU] = v
+//TranslateContainerPorts replaces the ports declared by a container with the ports served by the dev command
+func TranslateContainerPorts(c *apiv1.Container, ports []model.ContainerPort) {
+	if len(ports) == 0 {
+		return
+	}
+	c.Ports = []apiv1.ContainerPort{}
+	for _, p := range ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = apiv1.ProtocolTCP
+		}
+		c.Ports = append(c.Ports, apiv1.ContainerPort{
+			Name:          p.Name,
+			ContainerPort: p.ContainerPort,
+			Protocol:      protocol,
+		})
 	}
 }
 
-//TranslateEnvVars translates the variables attached to a container
+//TranslateEnvVars merges the rule variables into the variables of a container.
+//Each name keeps a single entry in its first position, and rule values override container values
 func TranslateEnvVars(c *apiv1.Container, rule *model.TranslationRule) {
-	unusedDevEnvVar := map[string]string{}
-	for _, val := range rule.Environment {
-		unusedDevEnvVar[val.Name] = val.Value
-	}
-	for i, envvar := range c.Env {
-		if value, ok := unusedDevEnvVar[envvar.Name]; ok {
-			c.Env[i] = apiv1.EnvVar{Name: envvar.Name, Value: value}
-			delete(unusedDevEnvVar, envvar.Name)
+	var env []apiv1.EnvVar
+	index := map[string]int{}
+	merge := func(e apiv1.EnvVar) {
+		if i, ok := index[e.Name]; ok {
+			env[i] = e
+			return
 		}
+		index[e.Name] = len(env)
+		env = append(env, e)
+	}
+	for _, envvar := range c.Env {
+		merge(envvar)
 	}
 	for _, envvar := range rule.Environment {
-		if value, ok := unusedDevEnvVar[envvar.Name]; ok {
-			c.Env = append(c.Env, apiv1.EnvVar{Name: envvar.Name, Value: value})
+		merge(translateEnvVar(envvar))
+	}
+	c.Env = env
+	if rule.RuntimeMemoryFlags {
+		TranslateRuntimeMemoryFlags(c)
+	}
+}
+
+func translateEnvVar(e model.EnvVar) apiv1.EnvVar {
+	if e.ValueFrom == nil {
+		return apiv1.EnvVar{Name: e.Name, Value: e.Value}
+	}
+	source := &apiv1.EnvVarSource{}
+	if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+		source.SecretKeyRef = &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: ref.Name},
+			Key:                  ref.Key,
+		}
+	}
+	if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+		source.ConfigMapKeyRef = &apiv1.ConfigMapKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: ref.Name},
+			Key:                  ref.Key,
+		}
+	}
+	return apiv1.EnvVar{Name: e.Name, ValueFrom: source}
+}
+
+//runtimeMemoryFlag is a heap size option appended to the options env var of a runtime
+type runtimeMemoryFlag struct {
+	envVar string
+	option string
+	value  string
+}
+
+func (f runtimeMemoryFlag) String() string {
+	return f.option + f.value
+}
+
+//TranslateRuntimeMemoryFlags sizes the JVM and Node.js heaps to the memory limit of the container
+func TranslateRuntimeMemoryFlags(c *apiv1.Container) {
+	limit, ok := c.Resources.Limits[apiv1.ResourceMemory]
+	if !ok || limit.IsZero() {
+		log.Yellow("'runtimeMemoryFlags' is ignored for container '%s': it has no memory limit", c.Name)
+		return
+	}
+
+	for _, flag := range getRuntimeMemoryFlags(limit) {
+		translateRuntimeMemoryFlag(c, flag)
+	}
+}
+
+func getRuntimeMemoryFlags(limit resource.Quantity) []runtimeMemoryFlag {
+	heap := limit.Value() * runtimeHeapPercentage / 100 / (1024 * 1024)
+	if heap < 1 {
+		heap = 1
+	}
+	return []runtimeMemoryFlag{
+		{envVar: "JAVA_OPTS", option: "-Xmx", value: fmt.Sprintf("%dm", heap)},
+		{envVar: "NODE_OPTIONS", option: "--max-old-space-size=", value: fmt.Sprintf("%d", heap)},
+	}
+}
+
+func translateRuntimeMemoryFlag(c *apiv1.Container, flag runtimeMemoryFlag) {
+	for i := range c.Env {
+		if c.Env[i].Name != flag.envVar {
+			continue
+		}
+		if c.Env[i].ValueFrom != nil || strings.Contains(c.Env[i].Value, flag.option) {
+			return
 		}
+		c.Env[i].Value = strings.TrimSpace(fmt.Sprintf("%s %s", c.Env[i].Value, flag.String()))
+		return
 	}
+	c.Env = append(c.Env, apiv1.EnvVar{Name: flag.envVar, Value: flag.String()})
 }
 
 //TranslateVolumeMounts translates the volumes attached to a container
@@ -329,6 +797,16 @@ func TranslateVolumeMounts(c *apiv1.Container, rule *model.TranslationRule) {
 		)
 	}
 
+	for i, overlay := range rule.Overlays {
+		c.VolumeMounts = append(
+			c.VolumeMounts,
+			apiv1.VolumeMount{
+				Name:      fmt.Sprintf(oktetoOverlayVolumeTemplate, i),
+				MountPath: overlay,
+			},
+		)
+	}
+
 	if rule.Marker == "" {
 		return
 	}
@@ -351,7 +829,10 @@ func TranslateVolumeMounts(c *apiv1.Container, rule *model.TranslationRule) {
 }
 
 //TranslateOktetoBinVolumeMounts translates the binaries mount attached to a container
-func TranslateOktetoBinVolumeMounts(c *apiv1.Container) {
+func TranslateOktetoBinVolumeMounts(c *apiv1.Container, binPath string) {
+	if binPath == "" {
+		binPath = model.OktetoBinMountPath
+	}
 	if c.VolumeMounts == nil {
 		c.VolumeMounts = []apiv1.VolumeMount{}
 	}
@@ -362,12 +843,65 @@ func TranslateOktetoBinVolumeMounts(c *apiv1.Container) {
 	}
 	vm := apiv1.VolumeMount{
 		Name:      OktetoBinName,
-		MountPath: "/var/okteto/bin",
+		MountPath: binPath,
 	}
 	c.VolumeMounts = append(c.VolumeMounts, vm)
 }
 
-//TranslateOktetoVolumes translates the dev volumes
+//TranslateInitContainerVolumeMounts mounts the volumes of the dev container in the init containers listed in 'initContainerVolumes'
+func TranslateInitContainerVolumeMounts(spec *apiv1.PodSpec, rule *model.TranslationRule) error {
+	for _, icv := range rule.InitContainerVolumes {
+		var c *apiv1.Container
+		for i := range spec.InitContainers {
+			if spec.InitContainers[i].Name == icv.Container {
+				c = &spec.InitContainers[i]
+				break
+			}
+		}
+		if c == nil {
+			return fmt.Errorf("init container '%s' defined in 'initContainerVolumes' doesn't exist", icv.Container)
+		}
+
+		for _, mountPath := range icv.MountPaths {
+			v := getRuleVolume(rule, mountPath)
+			if v == nil {
+				return fmt.Errorf("'initContainerVolumes' mount path '%s' of init container '%s' is not a volume of container '%s'", mountPath, icv.Container, rule.Container)
+			}
+			if isMountedAt(c, v.MountPath) {
+				continue
+			}
+			c.VolumeMounts = append(
+				c.VolumeMounts,
+				apiv1.VolumeMount{
+					Name:      v.Name,
+					MountPath: v.MountPath,
+					SubPath:   v.SubPath,
+				},
+			)
+		}
+	}
+	return nil
+}
+
+func getRuleVolume(rule *model.TranslationRule, mountPath string) *model.VolumeMount {
+	for i := range rule.Volumes {
+		if path.Clean(rule.Volumes[i].MountPath) == path.Clean(mountPath) {
+			return &rule.Volumes[i]
+		}
+	}
+	return nil
+}
+
+func isMountedAt(c *apiv1.Container, mountPath string) bool {
+	for _, vm := range c.VolumeMounts {
+		if path.Clean(vm.MountPath) == path.Clean(mountPath) {
+			return true
+		}
+	}
+	return false
+}
+
+//TranslateOktetoVolumes translates the dev volumes. Volumes already defined in the pod spec keep their original source (e.g. ephemeral or csi volumes)
 func TranslateOktetoVolumes(spec *apiv1.PodSpec, rule *model.TranslationRule) {
 	if spec.Volumes == nil {
 		spec.Volumes = []apiv1.Volume{}
@@ -401,6 +935,117 @@ func TranslateOktetoVolumes(spec *apiv1.PodSpec, rule *model.TranslationRule) {
 
 		spec.Volumes = append(spec.Volumes, v)
 	}
+
+	for i := range rule.Overlays {
+		spec.Volumes = append(
+			spec.Volumes,
+			apiv1.Volume{
+				Name: fmt.Sprintf(oktetoOverlayVolumeTemplate, i),
+				VolumeSource: apiv1.VolumeSource{
+					EmptyDir: &apiv1.EmptyDirVolumeSource{},
+				},
+			},
+		)
+	}
+}
+
+//TranslateProjectedVolumes adds the projected volumes of the dev container. Sources are appended to projected volumes already defined in the pod spec, keeping their original sources
+func TranslateProjectedVolumes(spec *apiv1.PodSpec, c *apiv1.Container, rule *model.TranslationRule) error {
+	for _, pv := range rule.ProjectedVolumes {
+		sources := translateVolumeProjections(pv.Sources)
+
+		var v *apiv1.Volume
+		for i := range spec.Volumes {
+			if spec.Volumes[i].Name == pv.Name {
+				v = &spec.Volumes[i]
+				break
+			}
+		}
+
+		switch {
+		case v == nil:
+			spec.Volumes = append(
+				spec.Volumes,
+				apiv1.Volume{
+					Name: pv.Name,
+					VolumeSource: apiv1.VolumeSource{
+						Projected: &apiv1.ProjectedVolumeSource{Sources: sources},
+					},
+				},
+			)
+		case v.Projected == nil:
+			return fmt.Errorf("'projectedVolumes' volume '%s' already exists in deployment and it is not a projected volume", pv.Name)
+		default:
+			for _, source := range sources {
+				if !hasVolumeProjection(v.Projected.Sources, source) {
+					v.Projected.Sources = append(v.Projected.Sources, source)
+				}
+			}
+		}
+
+		if pv.MountPath == "" || isMountedAt(c, pv.MountPath) {
+			continue
+		}
+		c.VolumeMounts = append(
+			c.VolumeMounts,
+			apiv1.VolumeMount{
+				Name:      pv.Name,
+				MountPath: pv.MountPath,
+				ReadOnly:  true,
+			},
+		)
+	}
+	return nil
+}
+
+func translateVolumeProjections(sources []model.ProjectedVolumeSource) []apiv1.VolumeProjection {
+	result := []apiv1.VolumeProjection{}
+	for _, s := range sources {
+		projection := apiv1.VolumeProjection{}
+		if s.Secret != nil {
+			projection.Secret = &apiv1.SecretProjection{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: s.Secret.Name},
+				Items:                translateKeyToPaths(s.Secret.Items),
+			}
+		}
+		if s.ConfigMap != nil {
+			projection.ConfigMap = &apiv1.ConfigMapProjection{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: s.ConfigMap.Name},
+				Items:                translateKeyToPaths(s.ConfigMap.Items),
+			}
+		}
+		if s.DownwardAPI != nil {
+			projection.DownwardAPI = &apiv1.DownwardAPIProjection{}
+			for _, item := range s.DownwardAPI.Items {
+				projection.DownwardAPI.Items = append(
+					projection.DownwardAPI.Items,
+					apiv1.DownwardAPIVolumeFile{
+						Path:     item.Path,
+						FieldRef: &apiv1.ObjectFieldSelector{FieldPath: item.FieldPath},
+					},
+				)
+			}
+		}
+		result = append(result, projection)
+	}
+	return result
+}
+
+func translateKeyToPaths(items []model.ProjectedItem) []apiv1.KeyToPath {
+	var result []apiv1.KeyToPath
+	for _, item := range items {
+		result = append(result, apiv1.KeyToPath{Key: item.Key, Path: item.Path})
+	}
+	return result
+}
+
+func hasVolumeProjection(sources []apiv1.VolumeProjection, source apiv1.VolumeProjection) bool {
+	for i := range sources {
+		if reflect.DeepEqual(sources[i], source) {
+			return true
+		}
+	}
+	return false
 }
 
 //TranslateOktetoBinVolume translates the binaries volume attached to a container
@@ -436,6 +1081,24 @@ func TranslatePodSecurityContext(spec *apiv1.PodSpec, s *model.SecurityContext)
 	if s.FSGroup != nil {
 		spec.SecurityContext.FSGroup = s.FSGroup
 	}
+
+	if s.FSGroupChangePolicy != nil {
+		spec.SecurityContext.FSGroupChangePolicy = s.FSGroupChangePolicy
+	}
+
+	if len(s.SupplementalGroups) > 0 {
+		spec.SecurityContext.SupplementalGroups = s.SupplementalGroups
+	}
+
+	if s.SELinuxOptions != nil {
+		spec.SecurityContext.SELinuxOptions = s.SELinuxOptions
+	}
+
+	if s.LocalUser && s.FSGroup == nil {
+		if _, gid := getLocalUserIDs(); gid >= 0 {
+			spec.SecurityContext.FSGroup = &gid
+		}
+	}
 }
 
 //TranslatePodServiceAccount translates the security accout the pod uses
@@ -445,8 +1108,149 @@ func TranslatePodServiceAccount(spec *apiv1.PodSpec, sa string) {
 	}
 }
 
-//TranslateContainerSecurityContext translates the security context attached to a container
+//TranslatePodTerminationGracePeriod gives the dev command time to clean up when the dev pod is terminated
+func TranslatePodTerminationGracePeriod(spec *apiv1.PodSpec, seconds *int64) {
+	if seconds != nil {
+		spec.TerminationGracePeriodSeconds = seconds
+	}
+}
+
+//TranslatePodShareProcessNamespace enables a shared process namespace between the containers of the pod
+func TranslatePodShareProcessNamespace(spec *apiv1.PodSpec, share bool) {
+	if share {
+		spec.ShareProcessNamespace = &share
+	}
+}
+
+//TranslatePodLabels overrides the labels of the pod template, removing the labels with an empty value
+func TranslatePodLabels(d *appsv1.Deployment, podLabels map[string]string) error {
+	if len(podLabels) == 0 {
+		return nil
+	}
+	if d.Spec.Selector != nil {
+		for k := range podLabels {
+			if _, ok := d.Spec.Selector.MatchLabels[k]; ok {
+				return fmt.Errorf("'podLabels' cannot override the label '%s' used by the selector of deployment '%s'", k, d.Name)
+			}
+		}
+	}
+
+	o := d.Spec.Template.GetObjectMeta()
+	for k, v := range podLabels {
+		if v == "" {
+			labels := o.GetLabels()
+			delete(labels, k)
+			o.SetLabels(labels)
+			continue
+		}
+		setLabel(o, k, v)
+	}
+	return nil
+}
+
+//TranslatePodHostname sets the hostname and subdomain of the pod, to be resolved through a headless service
+func TranslatePodHostname(spec *apiv1.PodSpec, hostname, subdomain string) {
+	if hostname != "" {
+		spec.Hostname = hostname
+	}
+	if subdomain != "" {
+		spec.Subdomain = subdomain
+	}
+}
+
+//TranslatePodDNSConfig replaces the dns config of the pod, or merges it with the original one if 'merge' is set
+func TranslatePodDNSConfig(spec *apiv1.PodSpec, d *model.DNSConfig) error {
+	if d == nil {
+		return nil
+	}
+	result := &apiv1.PodDNSConfig{}
+	if d.Merge && spec.DNSConfig != nil {
+		result = spec.DNSConfig.DeepCopy()
+	}
+	result.Nameservers = mergeDNSValues(result.Nameservers, d.Nameservers)
+	result.Searches = mergeDNSValues(result.Searches, d.Searches)
+	for _, o := range d.Options {
+		option := apiv1.PodDNSConfigOption{Name: o.Name, Value: o.Value}
+		found := false
+		for i := range result.Options {
+			if result.Options[i].Name == o.Name {
+				result.Options[i] = option
+				found = true
+				break
+			}
+		}
+		if !found {
+			result.Options = append(result.Options, option)
+		}
+	}
+
+	if len(result.Nameservers) > maxDNSNameservers {
+		return fmt.Errorf("'dnsConfig' exceeds the maximum of %d nameservers: %s", maxDNSNameservers, strings.Join(result.Nameservers, ", "))
+	}
+	if len(result.Searches) > maxDNSSearches {
+		return fmt.Errorf("'dnsConfig' exceeds the maximum of %d searches: %s", maxDNSSearches, strings.Join(result.Searches, ", "))
+	}
+	spec.DNSConfig = result
+	return nil
+}
+
+func mergeDNSValues(original, values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, v := range append(original, values...) {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+//TranslatePodReadinessGates strips or extends the readiness gates of the pod, so the dev pod can become ready
+func TranslatePodReadinessGates(spec *apiv1.PodSpec, r *model.ReadinessGates) {
+	if r == nil {
+		return
+	}
+	if r.Strip {
+		spec.ReadinessGates = nil
+	}
+	for _, conditionType := range r.ConditionTypes {
+		found := false
+		for _, gate := range spec.ReadinessGates {
+			if gate.ConditionType == conditionType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			spec.ReadinessGates = append(spec.ReadinessGates, apiv1.PodReadinessGate{ConditionType: conditionType})
+		}
+	}
+}
+
+//TranslatePodTopologySpreadConstraints relaxes the topology spread constraints of the pod, so the dev pod can be scheduled.
+//Constraints are set to 'ScheduleAnyway' unless 'topologySpread.whenUnsatisfiable' says otherwise
+func TranslatePodTopologySpreadConstraints(spec *apiv1.PodSpec, t *model.TopologySpread) {
+	whenUnsatisfiable := apiv1.ScheduleAnyway
+	if t != nil && t.WhenUnsatisfiable != "" {
+		whenUnsatisfiable = t.WhenUnsatisfiable
+	}
+	for i := range spec.TopologySpreadConstraints {
+		spec.TopologySpreadConstraints[i].WhenUnsatisfiable = whenUnsatisfiable
+	}
+}
+
+//TranslateContainerSecurityContext translates the security context attached to a container.
+//ReadOnlyRootFilesystem is always cleared so the dev container can write the synchronized files
 func TranslateContainerSecurityContext(c *apiv1.Container, s *model.SecurityContext) {
+	if c.SecurityContext != nil {
+		c.SecurityContext.ReadOnlyRootFilesystem = nil
+	}
+
 	if s == nil {
 		return
 	}
@@ -455,20 +1259,37 @@ func TranslateContainerSecurityContext(c *apiv1.Container, s *model.SecurityCont
 		c.SecurityContext = &apiv1.SecurityContext{}
 	}
 
-	if s.RunAsUser != nil {
-		c.SecurityContext.RunAsUser = s.RunAsUser
-		if *s.RunAsUser == 0 {
+	runAsUser, runAsGroup := s.RunAsUser, s.RunAsGroup
+	if s.LocalUser {
+		runAsUser, runAsGroup = translateLocalUser()
+	}
+
+	if runAsUser != nil {
+		c.SecurityContext.RunAsUser = runAsUser
+		if *runAsUser == 0 {
 			c.SecurityContext.RunAsNonRoot = &falseBoolean
 		}
 	}
 
-	if s.RunAsGroup != nil {
-		c.SecurityContext.RunAsGroup = s.RunAsGroup
-		if *s.RunAsGroup == 0 {
+	if runAsGroup != nil {
+		c.SecurityContext.RunAsGroup = runAsGroup
+		if *runAsGroup == 0 {
 			c.SecurityContext.RunAsNonRoot = &falseBoolean
 		}
 	}
 
+	if s.Privileged != nil {
+		c.SecurityContext.Privileged = s.Privileged
+		if *s.Privileged {
+			// privileged containers always allow privilege escalation, an inherited 'false' would be rejected
+			c.SecurityContext.AllowPrivilegeEscalation = nil
+		}
+	}
+
+	if s.AllowPrivilegeEscalation != nil {
+		c.SecurityContext.AllowPrivilegeEscalation = s.AllowPrivilegeEscalation
+	}
+
 	if s.Capabilities == nil {
 		return
 	}
@@ -476,23 +1297,127 @@ func TranslateContainerSecurityContext(c *apiv1.Container, s *model.SecurityCont
 		c.SecurityContext.Capabilities = &apiv1.Capabilities{}
 	}
 
-	c.SecurityContext.ReadOnlyRootFilesystem = nil
 	c.SecurityContext.Capabilities.Add = append(c.SecurityContext.Capabilities.Add, s.Capabilities.Add...)
 	c.SecurityContext.Capabilities.Drop = append(c.SecurityContext.Capabilities.Drop, s.Capabilities.Drop...)
+	c.SecurityContext.Capabilities.Drop = removeCapabilities(c.SecurityContext.Capabilities.Drop, s.Capabilities.Add)
+}
+
+//removeCapabilities removes the capabilities added by the okteto manifest from the dropped ones
+func removeCapabilities(dropped, added []apiv1.Capability) []apiv1.Capability {
+	if len(added) == 0 {
+		return dropped
+	}
+	isAdded := map[apiv1.Capability]bool{}
+	for _, capability := range added {
+		isAdded[capability] = true
+	}
+	var result []apiv1.Capability
+	for _, capability := range dropped {
+		if !isAdded[capability] {
+			result = append(result, capability)
+		}
+	}
+	return result
+}
+
+//translateLocalUser returns the uid and gid of the local user, or nil if they are not available
+func translateLocalUser() (*int64, *int64) {
+	uid, gid := getLocalUserIDs()
+	if uid < 0 || gid < 0 {
+		log.Infof("the local user ids are not available, 'securityContext.localUser' is ignored")
+		return nil, nil
+	}
+	return &uid, &gid
+}
+
+//validateVolumeMounts checks that the volume mounts of a container don't collide on the same mount path
+func validateVolumeMounts(c *apiv1.Container) error {
+	seen := map[string]string{}
+	for _, vm := range c.VolumeMounts {
+		mountPath := path.Clean(vm.MountPath)
+		if name, ok := seen[mountPath]; ok {
+			return fmt.Errorf("volumes '%s' and '%s' of container '%s' are mounted at the same path '%s'", name, vm.Name, c.Name, mountPath)
+		}
+		seen[mountPath] = vm.Name
+	}
+	return nil
+}
+
+//validateHostPathVolumes rejects the volumes of a rule that resolve to a hostPath volume of the pod
+func validateHostPathVolumes(spec *apiv1.PodSpec, rule *model.TranslationRule) error {
+	names := map[string]bool{}
+	for _, v := range rule.Volumes {
+		names[v.Name] = true
+	}
+	for _, v := range spec.Volumes {
+		if names[v.Name] && v.HostPath != nil {
+			return fmt.Errorf("volume '%s' of container '%s' resolves to the hostPath '%s': hostPath volumes are only allowed in namespaces labeled with '%s=true'", v.Name, rule.Container, v.HostPath.Path, okLabels.AllowHostPathLabel)
+		}
+	}
+	return nil
+}
+
+//validateSecurityContext detects security context settings that the API server would reject
+func validateSecurityContext(spec *apiv1.PodSpec, c *apiv1.Container) error {
+	var runAsNonRoot *bool
+	var runAsUser *int64
+	if spec.SecurityContext != nil {
+		runAsNonRoot = spec.SecurityContext.RunAsNonRoot
+		runAsUser = spec.SecurityContext.RunAsUser
+	}
+
+	s := c.SecurityContext
+	if s == nil {
+		s = &apiv1.SecurityContext{}
+	}
+	if s.RunAsNonRoot != nil {
+		runAsNonRoot = s.RunAsNonRoot
+	}
+	if s.RunAsUser != nil {
+		runAsUser = s.RunAsUser
+	}
+
+	if runAsNonRoot != nil && *runAsNonRoot && runAsUser != nil && *runAsUser == 0 {
+		return fmt.Errorf("container '%s' sets 'runAsNonRoot' to true but runs as user 0", c.Name)
+	}
+
+	noPrivilegeEscalation := s.AllowPrivilegeEscalation != nil && !*s.AllowPrivilegeEscalation
+	if noPrivilegeEscalation && s.Privileged != nil && *s.Privileged {
+		return fmt.Errorf("container '%s' sets 'privileged' to true but 'allowPrivilegeEscalation' to false", c.Name)
+	}
+
+	if s.Capabilities == nil {
+		return nil
+	}
+	for _, capability := range s.Capabilities.Add {
+		if noPrivilegeEscalation && (capability == "SYS_ADMIN" || capability == "CAP_SYS_ADMIN") {
+			return fmt.Errorf("container '%s' adds the capability '%s' but sets 'allowPrivilegeEscalation' to false", c.Name, capability)
+		}
+	}
+	return nil
 }
 
 //TranslateOktetoInitBinContainer translates the bin init container of a pod
 func TranslateOktetoInitBinContainer(initContainer model.InitContainer, spec *apiv1.PodSpec) {
+	mountPath := initContainer.MountPath
+	if mountPath == "" {
+		mountPath = model.OktetoInitBinMountPath
+	}
+
+	command := fmt.Sprintf("cp /usr/local/bin/* %s", mountPath)
+	if initContainer.InjectShell {
+		command = fmt.Sprintf("%s && cp %s %s && ln -sf busybox %s", command, oktetoBusyboxPath, path.Join(mountPath, "busybox"), path.Join(mountPath, model.OktetoShellName))
+	}
 
 	c := apiv1.Container{
 		Name:            OktetoBinName,
 		Image:           initContainer.Image,
 		ImagePullPolicy: apiv1.PullIfNotPresent,
-		Command:         []string{"sh", "-c", "cp /usr/local/bin/* /okteto/bin"},
+		Command:         []string{"sh", "-c", command},
 		VolumeMounts: []apiv1.VolumeMount{
 			{
 				Name:      OktetoBinName,
-				MountPath: "/okteto/bin",
+				MountPath: mountPath,
 			},
 		},
 		Resources: apiv1.ResourceRequirements{
@@ -513,6 +1438,169 @@ func TranslateOktetoInitBinContainer(initContainer model.InitContainer, spec *ap
 	spec.InitContainers = append(spec.InitContainers, c)
 }
 
+//TranslateInotifyInitContainer adds a privileged init container that raises the inotify limits of the node running the dev pod
+func TranslateInotifyInitContainer(spec *apiv1.PodSpec, rule *model.TranslationRule) {
+	if rule.InotifyTuning == nil {
+		return
+	}
+
+	watches := rule.InotifyTuning.MaxUserWatches
+	if watches == 0 {
+		watches = model.OktetoDefaultInotifyMaxUserWatches
+	}
+	scripts := []string{getRaiseSysctlScript("fs/inotify/max_user_watches", watches)}
+	if rule.InotifyTuning.MaxUserInstances > 0 {
+		scripts = append(scripts, getRaiseSysctlScript("fs/inotify/max_user_instances", rule.InotifyTuning.MaxUserInstances))
+	}
+
+	log.Yellow("'inotifyTuning' runs a privileged init container that raises the inotify limits of the cluster node")
+	privileged := true
+	var rootUser int64
+	spec.InitContainers = append(spec.InitContainers, apiv1.Container{
+		Name:            oktetoInotifyName,
+		Image:           rule.InitContainer.Image,
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", strings.Join(scripts, " && ")},
+		SecurityContext: &apiv1.SecurityContext{
+			Privileged: &privileged,
+			RunAsUser:  &rootUser,
+		},
+	})
+}
+
+//getRaiseSysctlScript only raises a sysctl, it never lowers the value already configured in the node
+func getRaiseSysctlScript(key string, value int) string {
+	return fmt.Sprintf("{ [ $(cat /proc/sys/%[1]s) -ge %[2]d ] || echo %[2]d > /proc/sys/%[1]s; }", key, value)
+}
+
+//TranslateWaitForInitContainer adds an init container that waits for the dependencies of the dev container to accept connections
+func TranslateWaitForInitContainer(spec *apiv1.PodSpec, rule *model.TranslationRule) {
+	if len(rule.WaitFor) == 0 {
+		return
+	}
+
+	scripts := []string{}
+	for _, w := range rule.WaitFor {
+		scripts = append(scripts, getWaitForScript(w))
+	}
+
+	spec.InitContainers = append(spec.InitContainers, apiv1.Container{
+		Name:            oktetoWaitForName,
+		Image:           rule.InitContainer.Image,
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", strings.Join(scripts, " && ")},
+	})
+}
+
+//TranslateHydrateInitContainer adds an init container that extracts the hydrate artifact into the persistent volume.
+//The init container is always part of the pod template and skips the extraction once a marker file exists in the volume,
+//so recreated pods don't overwrite the user data and failed hydrations are retried
+func TranslateHydrateInitContainer(spec *apiv1.PodSpec, rule *model.TranslationRule) error {
+	if rule.Hydrate == nil {
+		return nil
+	}
+	v := getRuleVolume(rule, rule.Hydrate.Path)
+	if v == nil {
+		return fmt.Errorf("'persistentVolume.hydrate.path' '%s' is not a volume of container '%s'", rule.Hydrate.Path, rule.Container)
+	}
+
+	image := rule.InitContainer.Image
+	if rule.Hydrate.Image != "" {
+		image = model.RewriteImage(rule.Hydrate.Image, rule.RegistryRewrites)
+	}
+
+	spec.InitContainers = append(spec.InitContainers, apiv1.Container{
+		Name:            oktetoHydrateName,
+		Image:           image,
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Command: []string{
+			"sh",
+			"-c",
+			fmt.Sprintf(
+				`set -e; if [ -f "$OKTETO_HYDRATE_PATH/%s" ]; then exit 0; fi; wget -qO %s "$OKTETO_HYDRATE_URL"; tar -xzf %s -C "$OKTETO_HYDRATE_PATH"; rm %s; touch "$OKTETO_HYDRATE_PATH/%s"`,
+				oktetoHydrateMarker, oktetoHydrateArchive, oktetoHydrateArchive, oktetoHydrateArchive, oktetoHydrateMarker,
+			),
+		},
+		Env: []apiv1.EnvVar{
+			{Name: "OKTETO_HYDRATE_URL", Value: rule.Hydrate.URL},
+			{Name: "OKTETO_HYDRATE_PATH", Value: v.MountPath},
+		},
+		VolumeMounts: []apiv1.VolumeMount{
+			{
+				Name:      v.Name,
+				MountPath: v.MountPath,
+				SubPath:   v.SubPath,
+			},
+		},
+	})
+	return nil
+}
+
+func getWaitForScript(w model.WaitFor) string {
+	address := fmt.Sprintf("%s:%d", w.Host, w.Port)
+	if w.Timeout == 0 {
+		return fmt.Sprintf("until nc -z %s %d; do echo 'waiting for %s'; sleep 1; done", w.Host, w.Port, address)
+	}
+	return fmt.Sprintf(
+		"end=$(($(date +%%s)+%d)); until nc -z %s %d; do if [ $(date +%%s) -ge $end ]; then echo 'timeout waiting for %s'; exit 1; fi; echo 'waiting for %s'; sleep 1; done",
+		w.Timeout, w.Host, w.Port, address, address,
+	)
+}
+
+//TranslateLogFifo shares a fifo between the dev container and a sidecar that prints everything written to it
+func TranslateLogFifo(spec *apiv1.PodSpec, c *apiv1.Container, rule *model.TranslationRule) {
+	if rule.LogFifo == "" {
+		return
+	}
+	fifoDir := path.Dir(rule.LogFifo)
+	mount := apiv1.VolumeMount{
+		Name:      oktetoLogFifoName,
+		MountPath: fifoDir,
+	}
+
+	spec.Volumes = append(spec.Volumes, apiv1.Volume{
+		Name: oktetoLogFifoName,
+		VolumeSource: apiv1.VolumeSource{
+			EmptyDir: &apiv1.EmptyDirVolumeSource{},
+		},
+	})
+	c.VolumeMounts = append(c.VolumeMounts, mount)
+	spec.InitContainers = append(spec.InitContainers, apiv1.Container{
+		Name:            oktetoLogFifoName,
+		Image:           rule.InitContainer.Image,
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", fmt.Sprintf("[ -p %[1]s ] || mkfifo %[1]s", rule.LogFifo)},
+		VolumeMounts:    []apiv1.VolumeMount{mount},
+	})
+	spec.Containers = append(spec.Containers, apiv1.Container{
+		Name:            model.OktetoLogTailContainer,
+		Image:           rule.InitContainer.Image,
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", fmt.Sprintf("while true; do cat %s; done", rule.LogFifo)},
+		VolumeMounts:    []apiv1.VolumeMount{mount},
+	})
+}
+
+//TranslateOpenTelemetry adds an OpenTelemetry collector sidecar to the pod
+func TranslateOpenTelemetry(spec *apiv1.PodSpec, otel *model.OpenTelemetry, rewrites []model.RegistryRewrite) {
+	if otel == nil {
+		return
+	}
+	spec.Containers = append(spec.Containers, apiv1.Container{
+		Name:            model.OktetoOpenTelemetryContainer,
+		Image:           model.RewriteImage(otel.Image, rewrites),
+		ImagePullPolicy: apiv1.PullIfNotPresent,
+		Args:            otel.Args,
+		Ports: []apiv1.ContainerPort{
+			{
+				Name:          "otlp",
+				ContainerPort: model.OpenTelemetryPort,
+				Protocol:      apiv1.ProtocolTCP,
+			},
+		},
+	})
+}
+
 //TranslateOktetoSyncSecret translates the syncthing secret container of a pod
 func TranslateOktetoSyncSecret(spec *apiv1.PodSpec, name string) {
 	if spec.Volumes == nil {
@@ -14,27 +14,33 @@
 package deployments
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/k8s/namespaces"
 	"github.com/okteto/okteto/pkg/model"
 	yaml "gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var (
 	rootUser int64
 	mode444  int32 = 0444
 	mode420  int32 = 420
+
+	trueBoolean = true
 )
 
 func Test_translateWithVolumes(t *testing.T) {
@@ -205,6 +211,8 @@ services:
 							Command:         []string{"/var/okteto/bin/start.sh"},
 							Args:            []string{"-r", "-s", "remote:/remote"},
 							WorkingDir:      "/app",
+							Stdin:           true,
+							TTY:             true,
 							Env: []apiv1.EnvVar{
 								{
 									Name:  "OKTETO_NAMESPACE",
@@ -288,7 +296,7 @@ services:
 		t.Fatalf("Wrong d1 pod annotations: '%s'", d1.Spec.Template.Annotations["key"])
 	}
 
-	d1Down, err := TranslateDevModeOff(d1)
+	d1Down, err := TranslateDevModeOff(d1, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -406,7 +414,7 @@ services:
 		t.Fatalf("Wrong d2 pod annotations: '%s'", d2.Spec.Template.Annotations["key"])
 	}
 
-	d2Down, err := TranslateDevModeOff(d2)
+	d2Down, err := TranslateDevModeOff(d2, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -527,6 +535,8 @@ persistentVolume:
 							Command:         []string{"/var/okteto/bin/start.sh"},
 							Args:            []string{"-r", "-e"},
 							WorkingDir:      "",
+							Stdin:           true,
+							TTY:             true,
 							Env: []apiv1.EnvVar{
 								{
 									Name:  "OKTETO_NAMESPACE",
@@ -686,6 +696,32 @@ func Test_translateResources(t *testing.T) {
 				apiv1.ResourceCPU:    resource.MustParse("2"),
 			},
 		},
+		{
+			name: "ephemeral-storage",
+			args: args{
+				c: &apiv1.Container{
+					Resources: apiv1.ResourceRequirements{
+						Limits: map[apiv1.ResourceName]resource.Quantity{
+							apiv1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				r: model.ResourceRequirements{
+					Limits: model.ResourceList{
+						apiv1.ResourceEphemeralStorage: resource.MustParse("20Gi"),
+					},
+					Requests: model.ResourceList{
+						apiv1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+			expectedRequests: map[apiv1.ResourceName]resource.Quantity{
+				apiv1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
+			},
+			expectedLimits: map[apiv1.ResourceName]resource.Quantity{
+				apiv1.ResourceEphemeralStorage: resource.MustParse("20Gi"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -718,10 +754,61 @@ func Test_translateResources(t *testing.T) {
 			if a.Cmp(b) != 0 {
 				t.Errorf("limits %s: expected %s, got %s", apiv1.ResourceCPU, b.String(), a.String())
 			}
+
+			a = tt.args.c.Resources.Requests[apiv1.ResourceEphemeralStorage]
+			b = tt.expectedRequests[apiv1.ResourceEphemeralStorage]
+
+			if a.Cmp(b) != 0 {
+				t.Errorf("requests %s: expected %s, got %s", apiv1.ResourceEphemeralStorage, b.String(), a.String())
+			}
+
+			a = tt.args.c.Resources.Limits[apiv1.ResourceEphemeralStorage]
+			b = tt.expectedLimits[apiv1.ResourceEphemeralStorage]
+
+			if a.Cmp(b) != 0 {
+				t.Errorf("limits %s: expected %s, got %s", apiv1.ResourceEphemeralStorage, b.String(), a.String())
+			}
 		})
 	}
 }
 
+func Test_translateExtendedResources(t *testing.T) {
+	c := &apiv1.Container{
+		Resources: apiv1.ResourceRequirements{
+			Limits: map[apiv1.ResourceName]resource.Quantity{
+				apiv1.ResourceCPU: resource.MustParse("1"),
+			},
+		},
+	}
+	r := model.ResourceRequirements{
+		Requests: model.ResourceList{
+			"hugepages-2Mi": resource.MustParse("128Mi"),
+		},
+		Limits: model.ResourceList{
+			"hugepages-2Mi":         resource.MustParse("128Mi"),
+			"squat.ai/fuse":         resource.MustParse("1"),
+			model.ResourceNVIDIAGPU: resource.MustParse("1"),
+		},
+	}
+	TranslateResources(c, r)
+
+	expectedRequests := apiv1.ResourceList{
+		"hugepages-2Mi": resource.MustParse("128Mi"),
+	}
+	expectedLimits := apiv1.ResourceList{
+		apiv1.ResourceCPU:       resource.MustParse("1"),
+		"hugepages-2Mi":         resource.MustParse("128Mi"),
+		"squat.ai/fuse":         resource.MustParse("1"),
+		model.ResourceNVIDIAGPU: resource.MustParse("1"),
+	}
+	if !reflect.DeepEqual(c.Resources.Requests, expectedRequests) {
+		t.Errorf("wrong requests: expected %v, got %v", expectedRequests, c.Resources.Requests)
+	}
+	if !reflect.DeepEqual(c.Resources.Limits, expectedLimits) {
+		t.Errorf("wrong limits: expected %v, got %v", expectedLimits, c.Resources.Limits)
+	}
+}
+
 func Test_translateSecurityContext(t *testing.T) {
 	var trueB = true
 
@@ -811,6 +898,254 @@ func Test_translateSecurityContext(t *testing.T) {
 	}
 }
 
+func Test_translateReadOnlyRootFilesystem(t *testing.T) {
+	var trueB = true
+
+	tests := []struct {
+		name string
+		s    *model.SecurityContext
+	}{
+		{
+			name: "nil",
+			s:    nil,
+		},
+		{
+			name: "no-capabilities",
+			s:    &model.SecurityContext{},
+		},
+		{
+			name: "run-as-user",
+			s:    &model.SecurityContext{RunAsUser: pointer.Int64Ptr(1000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{
+				SecurityContext: &apiv1.SecurityContext{
+					ReadOnlyRootFilesystem: &trueB,
+				},
+			}
+			TranslateContainerSecurityContext(c, tt.s)
+			if c.SecurityContext.ReadOnlyRootFilesystem != nil {
+				t.Errorf("ReadOnlyRootFilesystem was not removed")
+			}
+		})
+	}
+}
+
+func Test_translatePrivilegedSecurityContext(t *testing.T) {
+	var trueB = true
+	var falseB = false
+
+	tests := []struct {
+		name                     string
+		c                        *apiv1.Container
+		s                        *model.SecurityContext
+		privileged               *bool
+		allowPrivilegeEscalation *bool
+	}{
+		{
+			name: "not-set",
+			c:    &apiv1.Container{},
+			s:    &model.SecurityContext{},
+		},
+		{
+			name:       "privileged",
+			c:          &apiv1.Container{},
+			s:          &model.SecurityContext{Privileged: &trueB},
+			privileged: &trueB,
+		},
+		{
+			name:                     "allow-privilege-escalation",
+			c:                        &apiv1.Container{},
+			s:                        &model.SecurityContext{AllowPrivilegeEscalation: &falseB},
+			allowPrivilegeEscalation: &falseB,
+		},
+		{
+			name: "keep-existing",
+			c: &apiv1.Container{
+				SecurityContext: &apiv1.SecurityContext{
+					Privileged:               &trueB,
+					AllowPrivilegeEscalation: &trueB,
+				},
+			},
+			s:                        &model.SecurityContext{},
+			privileged:               &trueB,
+			allowPrivilegeEscalation: &trueB,
+		},
+		{
+			name: "privileged-clears-inherited-privilege-escalation",
+			c: &apiv1.Container{
+				SecurityContext: &apiv1.SecurityContext{
+					AllowPrivilegeEscalation: &falseB,
+				},
+			},
+			s:          &model.SecurityContext{Privileged: &trueB},
+			privileged: &trueB,
+		},
+		{
+			name: "privileged-with-privilege-escalation",
+			c: &apiv1.Container{
+				SecurityContext: &apiv1.SecurityContext{
+					AllowPrivilegeEscalation: &falseB,
+				},
+			},
+			s:                        &model.SecurityContext{Privileged: &trueB, AllowPrivilegeEscalation: &trueB},
+			privileged:               &trueB,
+			allowPrivilegeEscalation: &trueB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TranslateContainerSecurityContext(tt.c, tt.s)
+			if !reflect.DeepEqual(tt.c.SecurityContext.Privileged, tt.privileged) {
+				t.Errorf("wrong privileged: expected %v, got %v", tt.privileged, tt.c.SecurityContext.Privileged)
+			}
+			if !reflect.DeepEqual(tt.c.SecurityContext.AllowPrivilegeEscalation, tt.allowPrivilegeEscalation) {
+				t.Errorf("wrong allowPrivilegeEscalation: expected %v, got %v", tt.allowPrivilegeEscalation, tt.c.SecurityContext.AllowPrivilegeEscalation)
+			}
+		})
+	}
+}
+
+func Test_translatePodSecurityContext(t *testing.T) {
+	fsGroup := int64(1000)
+	onRootMismatch := apiv1.FSGroupChangeOnRootMismatch
+	seLinuxOptions := &apiv1.SELinuxOptions{Level: "s0:c123,c456"}
+
+	tests := []struct {
+		name     string
+		existing *apiv1.PodSecurityContext
+		s        *model.SecurityContext
+		expected *apiv1.PodSecurityContext
+	}{
+		{
+			name:     "nil",
+			s:        nil,
+			expected: nil,
+		},
+		{
+			name:     "fs-group",
+			s:        &model.SecurityContext{FSGroup: &fsGroup},
+			expected: &apiv1.PodSecurityContext{FSGroup: &fsGroup},
+		},
+		{
+			name: "fs-group-change-policy",
+			s:    &model.SecurityContext{FSGroup: &fsGroup, FSGroupChangePolicy: &onRootMismatch},
+			expected: &apiv1.PodSecurityContext{
+				FSGroup:             &fsGroup,
+				FSGroupChangePolicy: &onRootMismatch,
+			},
+		},
+		{
+			name:     "se-linux-options",
+			s:        &model.SecurityContext{SELinuxOptions: seLinuxOptions},
+			expected: &apiv1.PodSecurityContext{SELinuxOptions: seLinuxOptions},
+		},
+		{
+			name:     "se-linux-options-not-set",
+			existing: &apiv1.PodSecurityContext{SELinuxOptions: &apiv1.SELinuxOptions{Type: "spc_t"}},
+			s:        &model.SecurityContext{FSGroup: &fsGroup},
+			expected: &apiv1.PodSecurityContext{
+				FSGroup:        &fsGroup,
+				SELinuxOptions: &apiv1.SELinuxOptions{Type: "spc_t"},
+			},
+		},
+		{
+			name:     "supplemental-groups",
+			s:        &model.SecurityContext{SupplementalGroups: []int64{5555, 6666}},
+			expected: &apiv1.PodSecurityContext{SupplementalGroups: []int64{5555, 6666}},
+		},
+		{
+			name:     "supplemental-groups-empty",
+			existing: &apiv1.PodSecurityContext{SupplementalGroups: []int64{1234}},
+			s:        &model.SecurityContext{SupplementalGroups: []int64{}},
+			expected: &apiv1.PodSecurityContext{SupplementalGroups: []int64{1234}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &apiv1.PodSpec{SecurityContext: tt.existing}
+			TranslatePodSecurityContext(spec, tt.s)
+			if !reflect.DeepEqual(spec.SecurityContext, tt.expected) {
+				t.Errorf("Expected: %+v, Got: %+v", tt.expected, spec.SecurityContext)
+			}
+		})
+	}
+}
+
+func Test_translateLocalUser(t *testing.T) {
+	defer func(f func() (int64, int64)) { getLocalUserIDs = f }(getLocalUserIDs)
+	fsGroup := int64(2000)
+
+	tests := []struct {
+		name            string
+		uid             int64
+		gid             int64
+		s               *model.SecurityContext
+		expectedUser    *int64
+		expectedGroup   *int64
+		expectedFSGroup *int64
+	}{
+		{
+			name:            "local-user",
+			uid:             501,
+			gid:             20,
+			s:               &model.SecurityContext{LocalUser: true},
+			expectedUser:    pointer.Int64Ptr(501),
+			expectedGroup:   pointer.Int64Ptr(20),
+			expectedFSGroup: pointer.Int64Ptr(20),
+		},
+		{
+			name:            "local-user-keeps-fs-group",
+			uid:             501,
+			gid:             20,
+			s:               &model.SecurityContext{LocalUser: true, FSGroup: &fsGroup},
+			expectedUser:    pointer.Int64Ptr(501),
+			expectedGroup:   pointer.Int64Ptr(20),
+			expectedFSGroup: &fsGroup,
+		},
+		{
+			name: "local-user-not-available",
+			uid:  -1,
+			gid:  -1,
+			s:    &model.SecurityContext{LocalUser: true},
+		},
+		{
+			name:          "local-user-disabled",
+			uid:           501,
+			gid:           20,
+			s:             &model.SecurityContext{RunAsUser: pointer.Int64Ptr(1000), RunAsGroup: pointer.Int64Ptr(1000)},
+			expectedUser:  pointer.Int64Ptr(1000),
+			expectedGroup: pointer.Int64Ptr(1000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getLocalUserIDs = func() (int64, int64) { return tt.uid, tt.gid }
+
+			c := &apiv1.Container{}
+			TranslateContainerSecurityContext(c, tt.s)
+			if !reflect.DeepEqual(c.SecurityContext.RunAsUser, tt.expectedUser) {
+				t.Errorf("wrong runAsUser: expected %v, got %v", tt.expectedUser, c.SecurityContext.RunAsUser)
+			}
+			if !reflect.DeepEqual(c.SecurityContext.RunAsGroup, tt.expectedGroup) {
+				t.Errorf("wrong runAsGroup: expected %v, got %v", tt.expectedGroup, c.SecurityContext.RunAsGroup)
+			}
+
+			spec := &apiv1.PodSpec{}
+			TranslatePodSecurityContext(spec, tt.s)
+			if !reflect.DeepEqual(spec.SecurityContext.FSGroup, tt.expectedFSGroup) {
+				t.Errorf("wrong fsGroup: expected %v, got %v", tt.expectedFSGroup, spec.SecurityContext.FSGroup)
+			}
+		})
+	}
+}
+
 func TestTranslateOktetoVolumes(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -893,3 +1228,3180 @@ func TestTranslateOktetoVolumes(t *testing.T) {
 		})
 	}
 }
+
+func Test_translateShareProcessNamespace(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected *bool
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: nil,
+		},
+		{
+			name: "enabled",
+			manifest: []byte(`name: web
+namespace: n
+shareProcessNamespace: true
+sync:
+  - .:/app`),
+			expected: &trueBoolean,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(d.Spec.Template.Spec.ShareProcessNamespace, tt.expected) {
+				t.Errorf("wrong shareProcessNamespace: expected %v, got %v", tt.expected, d.Spec.Template.Spec.ShareProcessNamespace)
+			}
+		})
+	}
+}
+
+func Test_translateSkipAffinity(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected bool
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app
+services:
+  - name: worker
+    sync:
+       - worker:/src`),
+			expected: true,
+		},
+		{
+			name: "skip",
+			manifest: []byte(`name: web
+namespace: n
+skipPodAffinity: true
+sync:
+  - .:/app
+services:
+  - name: worker
+    sync:
+       - worker:/src`),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := dev.Services[0]
+			d := s.GevSandbox()
+			tr := &model.Translation{
+				Interactive:  false,
+				Name:         dev.Name,
+				Version:      model.TranslationVersion,
+				Deployment:   d,
+				SkipAffinity: dev.SkipPodAffinity,
+				Rules:        []*model.TranslationRule{s.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			hasAffinity := d.Spec.Template.Spec.Affinity != nil && d.Spec.Template.Spec.Affinity.PodAffinity != nil
+			if hasAffinity != tt.expected {
+				t.Errorf("wrong pod affinity: expected %t, got %+v", tt.expected, d.Spec.Template.Spec.Affinity)
+			}
+		})
+	}
+}
+
+func Test_translateContainerPorts(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected []apiv1.ContainerPort
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: []apiv1.ContainerPort{{Name: "http", ContainerPort: 80, Protocol: apiv1.ProtocolTCP}},
+		},
+		{
+			name: "replace",
+			manifest: []byte(`name: web
+namespace: n
+containerPorts:
+  - name: http
+    containerPort: 8080
+  - name: metrics
+    containerPort: 9090
+    protocol: UDP
+sync:
+  - .:/app`),
+			expected: []apiv1.ContainerPort{
+				{Name: "http", ContainerPort: 8080, Protocol: apiv1.ProtocolTCP},
+				{Name: "metrics", ContainerPort: 9090, Protocol: apiv1.ProtocolUDP},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.Containers[0].Ports = []apiv1.ContainerPort{{Name: "http", ContainerPort: 80, Protocol: apiv1.ProtocolTCP}}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(d.Spec.Template.Spec.Containers[0].Ports, tt.expected) {
+				t.Errorf("wrong ports: expected %+v, got %+v", tt.expected, d.Spec.Template.Spec.Containers[0].Ports)
+			}
+		})
+	}
+}
+
+func Test_translateLivenessGracePeriod(t *testing.T) {
+	var tests = []struct {
+		name     string
+		probe    *apiv1.Probe
+		grace    int32
+		expected *apiv1.Probe
+	}{
+		{
+			name:     "no-probe",
+			probe:    nil,
+			grace:    30,
+			expected: nil,
+		},
+		{
+			name:     "no-grace",
+			probe:    &apiv1.Probe{InitialDelaySeconds: 5, PeriodSeconds: 10, FailureThreshold: 3},
+			grace:    0,
+			expected: &apiv1.Probe{InitialDelaySeconds: 5, PeriodSeconds: 10, FailureThreshold: 3},
+		},
+		{
+			name:     "extends-window",
+			probe:    &apiv1.Probe{InitialDelaySeconds: 5, PeriodSeconds: 10, FailureThreshold: 3},
+			grace:    45,
+			expected: &apiv1.Probe{InitialDelaySeconds: 45, PeriodSeconds: 10, FailureThreshold: 5},
+		},
+		{
+			name:     "default-period",
+			probe:    &apiv1.Probe{},
+			grace:    60,
+			expected: &apiv1.Probe{InitialDelaySeconds: 60, FailureThreshold: 6},
+		},
+		{
+			name:     "already-larger",
+			probe:    &apiv1.Probe{InitialDelaySeconds: 120, PeriodSeconds: 5, FailureThreshold: 10},
+			grace:    30,
+			expected: &apiv1.Probe{InitialDelaySeconds: 120, PeriodSeconds: 5, FailureThreshold: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{LivenessProbe: tt.probe}
+			TranslateLivenessGracePeriod(c, tt.grace)
+			if !reflect.DeepEqual(c.LivenessProbe, tt.expected) {
+				t.Errorf("wrong liveness probe: expected %+v, got %+v", tt.expected, c.LivenessProbe)
+			}
+		})
+	}
+}
+
+func Test_translateLivenessFailureAction(t *testing.T) {
+	httpProbe := &apiv1.Probe{
+		Handler: apiv1.Handler{
+			HTTPGet: &apiv1.HTTPGetAction{Path: "/healthz"},
+		},
+		PeriodSeconds:    5,
+		FailureThreshold: 3,
+	}
+	var tests = []struct {
+		name     string
+		probe    *apiv1.Probe
+		action   string
+		expected *apiv1.Probe
+	}{
+		{
+			name:     "default",
+			probe:    httpProbe,
+			action:   "",
+			expected: httpProbe,
+		},
+		{
+			name:     "restart",
+			probe:    httpProbe,
+			action:   model.LivenessFailureActionRestart,
+			expected: httpProbe,
+		},
+		{
+			name:     "ignore",
+			probe:    httpProbe,
+			action:   model.LivenessFailureActionIgnore,
+			expected: nil,
+		},
+		{
+			name:     "ignore-no-probe",
+			probe:    nil,
+			action:   model.LivenessFailureActionIgnore,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{LivenessProbe: tt.probe}
+			TranslateLivenessFailureAction(c, tt.action)
+			if !reflect.DeepEqual(c.LivenessProbe, tt.expected) {
+				t.Errorf("wrong liveness probe: expected %+v, got %+v", tt.expected, c.LivenessProbe)
+			}
+		})
+	}
+}
+
+func Test_translateCustomBinPaths(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+binPath: /opt/okteto/bin
+initContainer:
+  mountPath: /tmp/okteto/bin
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := d.Spec.Template.Spec
+	if len(spec.InitContainers) != 1 {
+		t.Fatalf("wrong init containers: %+v", spec.InitContainers)
+	}
+	initContainer := spec.InitContainers[0]
+	expectedCommand := []string{"sh", "-c", "cp /usr/local/bin/* /tmp/okteto/bin"}
+	if !reflect.DeepEqual(initContainer.Command, expectedCommand) {
+		t.Errorf("wrong init container command: %v", initContainer.Command)
+	}
+	expectedInitMounts := []apiv1.VolumeMount{{Name: OktetoBinName, MountPath: "/tmp/okteto/bin"}}
+	if !reflect.DeepEqual(initContainer.VolumeMounts, expectedInitMounts) {
+		t.Errorf("wrong init container volume mounts: %v", initContainer.VolumeMounts)
+	}
+
+	devContainer := spec.Containers[0]
+	if !reflect.DeepEqual(devContainer.Command, []string{"/opt/okteto/bin/start.sh"}) {
+		t.Errorf("wrong dev container command: %v", devContainer.Command)
+	}
+	found := false
+	for _, vm := range devContainer.VolumeMounts {
+		if vm.Name == OktetoBinName {
+			found = true
+			if vm.MountPath != "/opt/okteto/bin" {
+				t.Errorf("wrong okteto bin mount path: %s", vm.MountPath)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("okteto bin volume mount not found: %v", devContainer.VolumeMounts)
+	}
+}
+
+func Test_validateSecurityContext(t *testing.T) {
+	var nonRootUser int64 = 1000
+	var tests = []struct {
+		name      string
+		pod       *apiv1.PodSecurityContext
+		container *apiv1.SecurityContext
+		expectErr bool
+	}{
+		{
+			name:      "empty",
+			expectErr: false,
+		},
+		{
+			name:      "non-root-with-non-root-user",
+			container: &apiv1.SecurityContext{RunAsNonRoot: &trueBoolean, RunAsUser: &nonRootUser},
+			expectErr: false,
+		},
+		{
+			name:      "non-root-with-root-user",
+			container: &apiv1.SecurityContext{RunAsNonRoot: &trueBoolean, RunAsUser: &rootUser},
+			expectErr: true,
+		},
+		{
+			name:      "pod-non-root-with-container-root-user",
+			pod:       &apiv1.PodSecurityContext{RunAsNonRoot: &trueBoolean},
+			container: &apiv1.SecurityContext{RunAsUser: &rootUser},
+			expectErr: true,
+		},
+		{
+			name:      "container-overrides-pod-non-root",
+			pod:       &apiv1.PodSecurityContext{RunAsNonRoot: &trueBoolean},
+			container: &apiv1.SecurityContext{RunAsUser: &rootUser, RunAsNonRoot: &falseBoolean},
+			expectErr: false,
+		},
+		{
+			name:      "privileged-without-privilege-escalation",
+			container: &apiv1.SecurityContext{Privileged: &trueBoolean, AllowPrivilegeEscalation: &falseBoolean},
+			expectErr: true,
+		},
+		{
+			name: "sys-admin-without-privilege-escalation",
+			container: &apiv1.SecurityContext{
+				AllowPrivilegeEscalation: &falseBoolean,
+				Capabilities:             &apiv1.Capabilities{Add: []apiv1.Capability{"SYS_ADMIN"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "added-and-dropped-capability",
+			container: &apiv1.SecurityContext{
+				Capabilities: &apiv1.Capabilities{Add: []apiv1.Capability{"NET_ADMIN"}, Drop: []apiv1.Capability{"NET_ADMIN"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "drop-all-add-one",
+			container: &apiv1.SecurityContext{
+				Capabilities: &apiv1.Capabilities{Add: []apiv1.Capability{"NET_BIND_SERVICE"}, Drop: []apiv1.Capability{"ALL"}},
+			},
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &apiv1.PodSpec{SecurityContext: tt.pod}
+			c := &apiv1.Container{Name: "dev", SecurityContext: tt.container}
+			err := validateSecurityContext(spec, c)
+			if tt.expectErr && err == nil {
+				t.Error("expected error")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func Test_translateConflictingSecurityContext(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+securityContext:
+  capabilities:
+    add:
+      - NET_ADMIN
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.Containers[0].SecurityContext = &apiv1.SecurityContext{
+		Capabilities: &apiv1.Capabilities{Drop: []apiv1.Capability{"NET_ADMIN"}},
+	}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	capabilities := d.Spec.Template.Spec.Containers[0].SecurityContext.Capabilities
+	if !reflect.DeepEqual(capabilities.Add, []apiv1.Capability{"NET_ADMIN"}) {
+		t.Errorf("wrong added capabilities: %v", capabilities.Add)
+	}
+	if len(capabilities.Drop) != 0 {
+		t.Errorf("wrong dropped capabilities: %v", capabilities.Drop)
+	}
+}
+
+func Test_validateVolumeMounts(t *testing.T) {
+	var tests = []struct {
+		name      string
+		mounts    []apiv1.VolumeMount
+		expectErr bool
+	}{
+		{
+			name:      "empty",
+			expectErr: false,
+		},
+		{
+			name: "different-paths",
+			mounts: []apiv1.VolumeMount{
+				{Name: "okteto", MountPath: "/app"},
+				{Name: oktetoSyncSecretVolume, MountPath: "/var/syncthing/secret/"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "same-path",
+			mounts: []apiv1.VolumeMount{
+				{Name: "okteto", MountPath: "/var/syncthing/secret"},
+				{Name: oktetoSyncSecretVolume, MountPath: "/var/syncthing/secret/"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{Name: "dev", VolumeMounts: tt.mounts}
+			err := validateVolumeMounts(c)
+			if tt.expectErr && err == nil {
+				t.Error("expected error")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func Test_translateConflictingVolumeMounts(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+volumes:
+  - /var/okteto/secret
+secrets:
+  - /etc/hosts:/remote
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err == nil {
+		t.Fatal("expected error for conflicting mount paths")
+	}
+}
+
+func Test_translateOverlays(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app
+overlays:
+  - /app/build
+  - /app/node_modules`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMounts := map[string]string{
+		"okteto-overlay-0": "/app/build",
+		"okteto-overlay-1": "/app/node_modules",
+	}
+	for name, mountPath := range expectedMounts {
+		found := false
+		for _, vm := range d.Spec.Template.Spec.Containers[0].VolumeMounts {
+			if vm.Name == name {
+				found = true
+				if vm.MountPath != mountPath || vm.SubPath != "" {
+					t.Errorf("wrong overlay mount '%s': %+v", name, vm)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("overlay mount '%s' not found", name)
+		}
+
+		found = false
+		for _, v := range d.Spec.Template.Spec.Volumes {
+			if v.Name == name {
+				found = true
+				if v.EmptyDir == nil {
+					t.Errorf("overlay volume '%s' is not an emptyDir: %+v", name, v)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("overlay volume '%s' not found", name)
+		}
+	}
+}
+
+func Test_translateEphemeralVolumes(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app
+externalVolumes:
+  - scratch:/scratch
+  - inline:/inline`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	storageClass := "standard"
+	ephemeral := apiv1.Volume{
+		Name: "scratch",
+		VolumeSource: apiv1.VolumeSource{
+			Ephemeral: &apiv1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &apiv1.PersistentVolumeClaimTemplate{
+					Spec: apiv1.PersistentVolumeClaimSpec{
+						AccessModes:      []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce},
+						StorageClassName: &storageClass,
+						Resources: apiv1.ResourceRequirements{
+							Requests: apiv1.ResourceList{
+								apiv1.ResourceStorage: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	csi := apiv1.Volume{
+		Name: "inline",
+		VolumeSource: apiv1.VolumeSource{
+			CSI: &apiv1.CSIVolumeSource{
+				Driver:           "inline.storage.kubernetes.io",
+				VolumeAttributes: map[string]string{"foo": "bar"},
+			},
+		},
+	}
+	d.Spec.Template.Spec.Volumes = []apiv1.Volume{ephemeral, csi}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	volumes := map[string]apiv1.Volume{}
+	for _, v := range d.Spec.Template.Spec.Volumes {
+		if _, ok := volumes[v.Name]; ok {
+			t.Fatalf("duplicated volume '%s'", v.Name)
+		}
+		volumes[v.Name] = v
+	}
+	if !reflect.DeepEqual(volumes["scratch"], ephemeral) {
+		t.Errorf("ephemeral volume was modified: %+v", volumes["scratch"])
+	}
+	if !reflect.DeepEqual(volumes["inline"], csi) {
+		t.Errorf("csi volume was modified: %+v", volumes["inline"])
+	}
+
+	mounts := map[string]string{}
+	for _, vm := range d.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mounts[vm.Name] = vm.MountPath
+	}
+	if mounts["scratch"] != "/scratch" {
+		t.Errorf("wrong mount for ephemeral volume: '%s'", mounts["scratch"])
+	}
+	if mounts["inline"] != "/inline" {
+		t.Errorf("wrong mount for csi volume: '%s'", mounts["inline"])
+	}
+}
+
+func Test_translateImageDigest(t *testing.T) {
+	var tests = []struct {
+		name     string
+		image    string
+		digest   string
+		expected string
+	}{
+		{
+			name:     "no-digest",
+			image:    "okteto/app:dev",
+			expected: "okteto/app:dev",
+		},
+		{
+			name:     "tag",
+			image:    "okteto/app:dev",
+			digest:   "sha256:1234",
+			expected: "okteto/app@sha256:1234",
+		},
+		{
+			name:     "no-tag",
+			image:    "okteto/app",
+			digest:   "sha256:1234",
+			expected: "okteto/app@sha256:1234",
+		},
+		{
+			name:     "registry-with-port",
+			image:    "localhost:5000/app:dev",
+			digest:   "sha256:1234",
+			expected: "localhost:5000/app@sha256:1234",
+		},
+		{
+			name:     "registry-with-port-no-tag",
+			image:    "localhost:5000/app",
+			digest:   "sha256:1234",
+			expected: "localhost:5000/app@sha256:1234",
+		},
+		{
+			name:     "previous-digest",
+			image:    "okteto/app:dev@sha256:0000",
+			digest:   "sha256:1234",
+			expected: "okteto/app@sha256:1234",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{Image: "original"}
+			rule := &model.TranslationRule{
+				Image:           tt.image,
+				ImageDigest:     tt.digest,
+				ImagePullPolicy: apiv1.PullAlways,
+				Probes:          &model.Probes{},
+			}
+			TranslateDevContainer(c, rule)
+			if c.Image != tt.expected {
+				t.Errorf("wrong image: expected %s, got %s", tt.expected, c.Image)
+			}
+			if c.ImagePullPolicy != apiv1.PullAlways {
+				t.Errorf("wrong image pull policy: %s", c.ImagePullPolicy)
+			}
+		})
+	}
+}
+
+func Test_translateHostname(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+hostname: web-0
+subdomain: peers
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := d.Spec.Template.Spec
+	if spec.Hostname != "web-0" {
+		t.Errorf("wrong hostname: '%s'", spec.Hostname)
+	}
+	if spec.Subdomain != "peers" {
+		t.Errorf("wrong subdomain: '%s'", spec.Subdomain)
+	}
+
+	spec = apiv1.PodSpec{Hostname: "original", Subdomain: "original"}
+	TranslatePodHostname(&spec, "", "")
+	if spec.Hostname != "original" || spec.Subdomain != "original" {
+		t.Errorf("hostname and subdomain overridden: '%s' '%s'", spec.Hostname, spec.Subdomain)
+	}
+}
+
+func Test_translateLogFifo(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+logFifo: /var/log/app/fifo
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := d.Spec.Template.Spec
+	expectedMount := apiv1.VolumeMount{Name: oktetoLogFifoName, MountPath: "/var/log/app"}
+
+	found := false
+	for _, v := range spec.Volumes {
+		if v.Name == oktetoLogFifoName {
+			found = true
+			if v.EmptyDir == nil {
+				t.Errorf("log fifo volume is not an emptyDir: %+v", v)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("log fifo volume not found: %+v", spec.Volumes)
+	}
+
+	if len(spec.Containers) != 2 {
+		t.Fatalf("wrong containers: %+v", spec.Containers)
+	}
+	found = false
+	for _, vm := range spec.Containers[0].VolumeMounts {
+		if reflect.DeepEqual(vm, expectedMount) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("log fifo not mounted in dev container: %+v", spec.Containers[0].VolumeMounts)
+	}
+
+	sidecar := spec.Containers[1]
+	if sidecar.Name != model.OktetoLogTailContainer || sidecar.Image != model.OktetoBinImageTag {
+		t.Errorf("wrong log tail sidecar: %+v", sidecar)
+	}
+	if !reflect.DeepEqual(sidecar.Command, []string{"sh", "-c", "while true; do cat /var/log/app/fifo; done"}) {
+		t.Errorf("wrong log tail command: %v", sidecar.Command)
+	}
+	if !reflect.DeepEqual(sidecar.VolumeMounts, []apiv1.VolumeMount{expectedMount}) {
+		t.Errorf("wrong log tail volume mounts: %+v", sidecar.VolumeMounts)
+	}
+
+	if len(spec.InitContainers) != 2 {
+		t.Fatalf("wrong init containers: %+v", spec.InitContainers)
+	}
+	initContainer := spec.InitContainers[1]
+	if initContainer.Name != oktetoLogFifoName {
+		t.Errorf("wrong log fifo init container: %+v", initContainer)
+	}
+	if !reflect.DeepEqual(initContainer.Command, []string{"sh", "-c", "[ -p /var/log/app/fifo ] || mkfifo /var/log/app/fifo"}) {
+		t.Errorf("wrong log fifo init command: %v", initContainer.Command)
+	}
+	if !reflect.DeepEqual(initContainer.VolumeMounts, []apiv1.VolumeMount{expectedMount}) {
+		t.Errorf("wrong log fifo init volume mounts: %+v", initContainer.VolumeMounts)
+	}
+}
+
+func Test_translateWaitFor(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+waitFor:
+  - host: postgres
+    port: 5432
+    timeout: 60
+  - host: redis
+    port: 6379
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	initContainers := d.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 {
+		t.Fatalf("wrong init containers: %+v", initContainers)
+	}
+	if initContainers[0].Name != OktetoBinName {
+		t.Errorf("bin init container is not the first one: %+v", initContainers)
+	}
+
+	waitFor := initContainers[1]
+	if waitFor.Name != oktetoWaitForName || waitFor.Image != model.OktetoBinImageTag {
+		t.Errorf("wrong wait-for init container: %+v", waitFor)
+	}
+	expected := []string{
+		"sh",
+		"-c",
+		"end=$(($(date +%s)+60)); until nc -z postgres 5432; do if [ $(date +%s) -ge $end ]; then echo 'timeout waiting for postgres:5432'; exit 1; fi; echo 'waiting for postgres:5432'; sleep 1; done && " +
+			"until nc -z redis 6379; do echo 'waiting for redis:6379'; sleep 1; done",
+	}
+	if !reflect.DeepEqual(waitFor.Command, expected) {
+		t.Errorf("wrong wait-for command:\nexpected %v\ngot      %v", expected, waitFor.Command)
+	}
+}
+
+func Test_translateOpenTelemetry(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+openTelemetry:
+  args: ["--log-level=debug"]
+registryRewrites:
+  - from: docker.io
+    to: mirror.internal
+environment:
+  - OTEL_SERVICE_NAME=api
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := d.Spec.Template.Spec
+	if len(spec.Containers) != 2 {
+		t.Fatalf("wrong containers: %+v", spec.Containers)
+	}
+	sidecar := spec.Containers[1]
+	if sidecar.Name != model.OktetoOpenTelemetryContainer || sidecar.Image != "mirror.internal/"+model.DefaultOpenTelemetryImage {
+		t.Errorf("wrong collector sidecar: %+v", sidecar)
+	}
+	if len(sidecar.Ports) != 1 || sidecar.Ports[0].ContainerPort != 4317 {
+		t.Errorf("wrong collector ports: %+v", sidecar.Ports)
+	}
+
+	env := map[string]string{}
+	for _, e := range spec.Containers[0].Env {
+		if _, ok := env[e.Name]; ok {
+			t.Errorf("duplicated env var %s", e.Name)
+		}
+		env[e.Name] = e.Value
+	}
+	if env["OTEL_EXPORTER_OTLP_ENDPOINT"] != "http://localhost:4317" {
+		t.Errorf("wrong OTEL_EXPORTER_OTLP_ENDPOINT: '%s'", env["OTEL_EXPORTER_OTLP_ENDPOINT"])
+	}
+	if env["OTEL_SERVICE_NAME"] != "api" {
+		t.Errorf("wrong OTEL_SERVICE_NAME: '%s'", env["OTEL_SERVICE_NAME"])
+	}
+
+	dDown, err := TranslateDevModeOff(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dDown.Spec.Template.Spec.Containers) != 1 {
+		t.Errorf("collector sidecar not removed on down: %+v", dDown.Spec.Template.Spec.Containers)
+	}
+}
+
+func Test_translateInitContainerVolumes(t *testing.T) {
+	var tests = []struct {
+		name      string
+		manifest  []byte
+		expectErr bool
+	}{
+		{
+			name: "mount-volumes",
+			manifest: []byte(`name: web
+namespace: n
+volumes:
+  - /data
+initContainerVolumes:
+  - container: seed
+    mountPaths:
+      - /data
+      - /app
+  - container: okteto-bin
+    mountPaths:
+      - /data/
+sync:
+  - .:/app`),
+		},
+		{
+			name: "unknown-init-container",
+			manifest: []byte(`name: web
+namespace: n
+volumes:
+  - /data
+initContainerVolumes:
+  - container: migrate
+    mountPaths:
+      - /data
+sync:
+  - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "unknown-mount-path",
+			manifest: []byte(`name: web
+namespace: n
+initContainerVolumes:
+  - container: seed
+    mountPaths:
+      - /cache
+sync:
+  - .:/app`),
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.InitContainers = []apiv1.Container{{Name: "seed", Image: "busybox"}}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			err = translate(tr, nil, false)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec := tr.Deployment.Spec.Template.Spec
+			devMounts := map[string]apiv1.VolumeMount{}
+			for _, vm := range spec.Containers[0].VolumeMounts {
+				devMounts[vm.MountPath] = vm
+			}
+			expectedSeed := []apiv1.VolumeMount{devMounts["/data"], devMounts["/app"]}
+			if !reflect.DeepEqual(spec.InitContainers[0].VolumeMounts, expectedSeed) {
+				t.Errorf("wrong seed volume mounts: %+v", spec.InitContainers[0].VolumeMounts)
+			}
+			bin := spec.InitContainers[1]
+			if bin.Name != OktetoBinName {
+				t.Fatalf("wrong init containers: %+v", spec.InitContainers)
+			}
+			expectedBin := []apiv1.VolumeMount{{Name: OktetoBinName, MountPath: model.OktetoInitBinMountPath}, devMounts["/data"]}
+			if !reflect.DeepEqual(bin.VolumeMounts, expectedBin) {
+				t.Errorf("wrong bin volume mounts: %+v", bin.VolumeMounts)
+			}
+		})
+	}
+}
+
+func Test_translateRegistryRewrites(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+image: python:3
+registryRewrites:
+  - from: docker.io
+    to: mirror.internal
+  - from: gcr.io/project
+    to: mirror.internal/gcr
+services:
+  - name: worker
+    image: gcr.io/project/worker:1.0
+    sync:
+      - .:/worker
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	spec := tr.Deployment.Spec.Template.Spec
+	if spec.Containers[0].Image != "mirror.internal/library/python:3" {
+		t.Errorf("wrong dev image: %s", spec.Containers[0].Image)
+	}
+	if spec.InitContainers[0].Image != "mirror.internal/"+model.OktetoBinImageTag {
+		t.Errorf("wrong bin init image: %s", spec.InitContainers[0].Image)
+	}
+
+	svc := dev.Services[0]
+	dSvc := svc.GevSandbox()
+	trSvc := &model.Translation{
+		Name:       dev.Name,
+		Version:    model.TranslationVersion,
+		Deployment: dSvc,
+		Rules:      []*model.TranslationRule{svc.ToTranslationRule(dev)},
+	}
+	if err := translate(trSvc, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if image := trSvc.Deployment.Spec.Template.Spec.Containers[0].Image; image != "mirror.internal/gcr/worker:1.0" {
+		t.Errorf("wrong service image: %s", image)
+	}
+}
+
+func Test_translateInjectShell(t *testing.T) {
+	var tests = []struct {
+		name            string
+		manifest        []byte
+		expectedInit    string
+		expectedCommand []string
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expectedInit:    "cp /usr/local/bin/* /okteto/bin",
+			expectedCommand: []string{"/var/okteto/bin/start.sh"},
+		},
+		{
+			name: "inject-shell",
+			manifest: []byte(`name: web
+namespace: n
+initContainer:
+  injectShell: true
+sync:
+  - .:/app`),
+			expectedInit:    "cp /usr/local/bin/* /okteto/bin && cp /bin/busybox /okteto/bin/busybox && ln -sf busybox /okteto/bin/sh",
+			expectedCommand: []string{"/var/okteto/bin/sh", "/var/okteto/bin/start.sh"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			spec := tr.Deployment.Spec.Template.Spec
+			if len(spec.InitContainers) != 1 {
+				t.Fatalf("wrong init containers: %+v", spec.InitContainers)
+			}
+			expectedInit := []string{"sh", "-c", tt.expectedInit}
+			if !reflect.DeepEqual(spec.InitContainers[0].Command, expectedInit) {
+				t.Errorf("wrong init container command: %v", spec.InitContainers[0].Command)
+			}
+			if !reflect.DeepEqual(spec.Containers[0].Command, tt.expectedCommand) {
+				t.Errorf("wrong dev container command: %v", spec.Containers[0].Command)
+			}
+		})
+	}
+}
+
+func Test_translateDNSConfig(t *testing.T) {
+	ndots := "2"
+	timeout := "3"
+	original := &apiv1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.10"},
+		Searches:    []string{"svc.cluster.local", "corp.example.com"},
+		Options:     []apiv1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
+	}
+	var tests = []struct {
+		name      string
+		manifest  []byte
+		expected  *apiv1.PodDNSConfig
+		expectErr bool
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: original,
+		},
+		{
+			name: "replace",
+			manifest: []byte(`name: web
+namespace: n
+dnsConfig:
+  searches:
+    - dev.example.com
+sync:
+  - .:/app`),
+			expected: &apiv1.PodDNSConfig{Searches: []string{"dev.example.com"}},
+		},
+		{
+			name: "merge",
+			manifest: []byte(`name: web
+namespace: n
+dnsConfig:
+  merge: true
+  nameservers:
+    - 10.0.0.10
+    - 8.8.8.8
+  searches:
+    - corp.example.com
+    - dev.example.com
+  options:
+    - name: ndots
+      value: "5"
+    - name: timeout
+      value: "3"
+sync:
+  - .:/app`),
+			expected: &apiv1.PodDNSConfig{
+				Nameservers: []string{"10.0.0.10", "8.8.8.8"},
+				Searches:    []string{"svc.cluster.local", "corp.example.com", "dev.example.com"},
+				Options: []apiv1.PodDNSConfigOption{
+					{Name: "ndots", Value: pointer.StringPtr("5")},
+					{Name: "timeout", Value: &timeout},
+				},
+			},
+		},
+		{
+			name: "merge-too-many-nameservers",
+			manifest: []byte(`name: web
+namespace: n
+dnsConfig:
+  merge: true
+  nameservers:
+    - 8.8.8.8
+    - 8.8.4.4
+    - 1.1.1.1
+sync:
+  - .:/app`),
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.DNSConfig = original.DeepCopy()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			err = translate(tr, nil, false)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tr.Deployment.Spec.Template.Spec.DNSConfig, tt.expected) {
+				t.Errorf("wrong dns config: %+v", tr.Deployment.Spec.Template.Spec.DNSConfig)
+			}
+		})
+	}
+}
+
+func Test_translateReadinessGates(t *testing.T) {
+	targetHealth := apiv1.PodReadinessGate{ConditionType: "target-health.alb.ingress.k8s.aws/web"}
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected []apiv1.PodReadinessGate
+	}{
+		{
+			name: "preserve",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: []apiv1.PodReadinessGate{targetHealth},
+		},
+		{
+			name: "strip",
+			manifest: []byte(`name: web
+namespace: n
+readinessGates:
+  strip: true
+sync:
+  - .:/app`),
+			expected: nil,
+		},
+		{
+			name: "strip-and-customize",
+			manifest: []byte(`name: web
+namespace: n
+readinessGates:
+  strip: true
+  conditionTypes:
+    - example.com/dev-ready
+sync:
+  - .:/app`),
+			expected: []apiv1.PodReadinessGate{{ConditionType: "example.com/dev-ready"}},
+		},
+		{
+			name: "customize",
+			manifest: []byte(`name: web
+namespace: n
+readinessGates:
+  conditionTypes:
+    - target-health.alb.ingress.k8s.aws/web
+    - example.com/dev-ready
+sync:
+  - .:/app`),
+			expected: []apiv1.PodReadinessGate{targetHealth, {ConditionType: "example.com/dev-ready"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.ReadinessGates = []apiv1.PodReadinessGate{targetHealth}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tr.Deployment.Spec.Template.Spec.ReadinessGates, tt.expected) {
+				t.Errorf("wrong readiness gates: %+v", tr.Deployment.Spec.Template.Spec.ReadinessGates)
+			}
+		})
+	}
+}
+
+func Test_translateTopologySpread(t *testing.T) {
+	zoneSpread := apiv1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: apiv1.DoNotSchedule,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+	}
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected apiv1.UnsatisfiableConstraintAction
+	}{
+		{
+			name: "relax-by-default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: apiv1.ScheduleAnyway,
+		},
+		{
+			name: "keep-do-not-schedule",
+			manifest: []byte(`name: web
+namespace: n
+topologySpread:
+  whenUnsatisfiable: DoNotSchedule
+sync:
+  - .:/app`),
+			expected: apiv1.DoNotSchedule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.TopologySpreadConstraints = []apiv1.TopologySpreadConstraint{zoneSpread}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+			expected := zoneSpread
+			expected.WhenUnsatisfiable = tt.expected
+			if !reflect.DeepEqual(tr.Deployment.Spec.Template.Spec.TopologySpreadConstraints, []apiv1.TopologySpreadConstraint{expected}) {
+				t.Errorf("wrong topology spread constraints: %+v", tr.Deployment.Spec.Template.Spec.TopologySpreadConstraints)
+			}
+
+			restored, err := TranslateDevModeOff(tr.Deployment, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restored.Spec.Template.Spec.TopologySpreadConstraints, []apiv1.TopologySpreadConstraint{zoneSpread}) {
+				t.Errorf("topology spread constraints were not restored: %+v", restored.Spec.Template.Spec.TopologySpreadConstraints)
+			}
+		})
+	}
+}
+
+func Test_getAutoPullPolicy(t *testing.T) {
+	var tests = []struct {
+		image    string
+		expected apiv1.PullPolicy
+	}{
+		{image: "okteto/web", expected: apiv1.PullAlways},
+		{image: "okteto/web:latest", expected: apiv1.PullAlways},
+		{image: "okteto/web:main", expected: apiv1.PullAlways},
+		{image: "okteto/web:1", expected: apiv1.PullAlways},
+		{image: "okteto/web:1.2", expected: apiv1.PullAlways},
+		{image: "okteto/web:1.2.3", expected: apiv1.PullIfNotPresent},
+		{image: "okteto/web:v1.2.3", expected: apiv1.PullIfNotPresent},
+		{image: "okteto/web:1.2.3-alpine", expected: apiv1.PullIfNotPresent},
+		{image: "registry.example.com:5000/web", expected: apiv1.PullAlways},
+		{image: "registry.example.com:5000/web:1.2.3", expected: apiv1.PullIfNotPresent},
+		{image: "okteto/web@sha256:0b2a8ca3d6b7d0d1c5ce8cd6f3c4f3d2b0ab3c1c1d6e8b09e6593a0a2b2f9c01", expected: apiv1.PullIfNotPresent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := getAutoPullPolicy(tt.image); got != tt.expected {
+				t.Errorf("wrong pull policy for '%s': expected %s, got %s", tt.image, tt.expected, got)
+			}
+		})
+	}
+}
+
+func Test_translateAutoPullPolicy(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+image: okteto/web:1.2.3
+imagePullPolicy: Auto
+sync:
+  - .:/app`)
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := dev.ToTranslationRule(dev)
+
+	c := &apiv1.Container{}
+	TranslateDevContainer(c, rule)
+	if c.ImagePullPolicy != apiv1.PullIfNotPresent {
+		t.Errorf("wrong pull policy for a semver tag: %s", c.ImagePullPolicy)
+	}
+
+	rule.ImageDigest = "sha256:0b2a8ca3d6b7d0d1c5ce8cd6f3c4f3d2b0ab3c1c1d6e8b09e6593a0a2b2f9c01"
+	rule.Image = "okteto/web:main"
+	c = &apiv1.Container{}
+	TranslateDevContainer(c, rule)
+	if c.ImagePullPolicy != apiv1.PullIfNotPresent {
+		t.Errorf("wrong pull policy for a pinned digest: %s", c.ImagePullPolicy)
+	}
+
+	rule.ImageDigest = ""
+	c = &apiv1.Container{}
+	TranslateDevContainer(c, rule)
+	if c.ImagePullPolicy != apiv1.PullAlways {
+		t.Errorf("wrong pull policy for a floating tag: %s", c.ImagePullPolicy)
+	}
+}
+
+func Test_translateBuiltImagePullPolicy(t *testing.T) {
+	var tests = []struct {
+		name       string
+		pullPolicy string
+	}{
+		{
+			name:       "if-not-present",
+			pullPolicy: "IfNotPresent",
+		},
+		{
+			name:       "auto",
+			pullPolicy: "Auto",
+		},
+		{
+			name:       "never",
+			pullPolicy: "Never",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := []byte(fmt.Sprintf(`name: web
+namespace: n
+image: okteto/web:1.2.3
+imagePullPolicy: %s
+sync:
+  - .:/app`, tt.pullPolicy))
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dev.SetBuiltImage("registry.okteto.dev/n/web:1.2.3")
+
+			c := &apiv1.Container{}
+			TranslateDevContainer(c, dev.ToTranslationRule(dev))
+			if c.Image != "registry.okteto.dev/n/web:1.2.3" {
+				t.Errorf("wrong image for a built image: %s", c.Image)
+			}
+			if c.ImagePullPolicy != apiv1.PullAlways {
+				t.Errorf("wrong pull policy for a built image: %s", c.ImagePullPolicy)
+			}
+		})
+	}
+}
+
+func Test_translateHydrate(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+persistentVolume:
+  enabled: true
+  hydrate:
+    url: https://artifacts.example.com/web.tar.gz
+    path: /app
+sync:
+  - .:/app`)
+	var tests = []struct {
+		name      string
+		path      string
+		expectErr bool
+	}{
+		{
+			name: "hydrate",
+		},
+		{
+			name:      "unknown-path",
+			path:      "/data",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.path != "" {
+				dev.PersistentVolumeInfo.Hydrate.Path = tt.path
+			}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  dev.GevSandbox(),
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			err = translate(tr, nil, false)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var hydrate *apiv1.Container
+			for i := range tr.Deployment.Spec.Template.Spec.InitContainers {
+				if tr.Deployment.Spec.Template.Spec.InitContainers[i].Name == oktetoHydrateName {
+					hydrate = &tr.Deployment.Spec.Template.Spec.InitContainers[i]
+				}
+			}
+			if hydrate == nil {
+				t.Fatal("hydrate init container not found")
+			}
+			if hydrate.Image != model.OktetoBinImageTag {
+				t.Errorf("wrong hydrate image: %s", hydrate.Image)
+			}
+			expectedEnv := []apiv1.EnvVar{
+				{Name: "OKTETO_HYDRATE_URL", Value: "https://artifacts.example.com/web.tar.gz"},
+				{Name: "OKTETO_HYDRATE_PATH", Value: "/app"},
+			}
+			if !reflect.DeepEqual(hydrate.Env, expectedEnv) {
+				t.Errorf("wrong hydrate env: %+v", hydrate.Env)
+			}
+			expectedMounts := []apiv1.VolumeMount{
+				{Name: dev.GetVolumeName(), MountPath: "/app", SubPath: "src"},
+			}
+			if !reflect.DeepEqual(hydrate.VolumeMounts, expectedMounts) {
+				t.Errorf("wrong hydrate volume mounts: %+v", hydrate.VolumeMounts)
+			}
+			script := hydrate.Command[2]
+			if !strings.HasPrefix(script, `set -e; if [ -f "$OKTETO_HYDRATE_PATH/.okteto-hydrated" ]; then exit 0; fi;`) {
+				t.Errorf("hydration is not skipped when the marker file exists: %s", script)
+			}
+			if !strings.HasSuffix(script, `touch "$OKTETO_HYDRATE_PATH/.okteto-hydrated"`) {
+				t.Errorf("marker file not created after the hydration: %s", script)
+			}
+		})
+	}
+}
+
+func Test_translatePodLabels(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+podLabels:
+  version: dev
+  track: ""
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Template.Labels["version"] = "v1"
+	d.Spec.Template.Labels["track"] = "stable"
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := d.Spec.Template.Labels
+	if labels["version"] != "dev" {
+		t.Errorf("version label not overridden: %v", labels)
+	}
+	if _, ok := labels["track"]; ok {
+		t.Errorf("track label not removed: %v", labels)
+	}
+	if labels["app"] != "web" {
+		t.Errorf("selector label modified: %v", labels)
+	}
+	if labels[okLabels.InteractiveDevLabel] != "web" {
+		t.Errorf("okteto label missing: %v", labels)
+	}
+
+	if err := TranslatePodLabels(d, map[string]string{"app": "dev"}); err == nil {
+		t.Errorf("overriding a selector label didn't fail")
+	}
+}
+
+func Test_translateContainerName(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+containerSuffix: -dev
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Template.Annotations = map[string]string{
+		defaultContainerAnnotation:     "dev",
+		defaultLogsContainerAnnotation: "dev",
+	}
+	memoryRef := &apiv1.ResourceFieldSelector{ContainerName: "dev", Resource: "limits.memory"}
+	d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, apiv1.Container{
+		Name:  "sidecar",
+		Image: "busybox",
+		Env: []apiv1.EnvVar{
+			{Name: "DEV_MEMORY", ValueFrom: &apiv1.EnvVarSource{ResourceFieldRef: memoryRef.DeepCopy()}},
+		},
+	})
+	d.Spec.Template.Spec.Volumes = []apiv1.Volume{
+		{
+			Name: "downward",
+			VolumeSource: apiv1.VolumeSource{
+				DownwardAPI: &apiv1.DownwardAPIVolumeSource{
+					Items: []apiv1.DownwardAPIVolumeFile{{Path: "memory", ResourceFieldRef: memoryRef.DeepCopy()}},
+				},
+			},
+		},
+		{
+			Name: "projected",
+			VolumeSource: apiv1.VolumeSource{
+				Projected: &apiv1.ProjectedVolumeSource{
+					Sources: []apiv1.VolumeProjection{
+						{
+							DownwardAPI: &apiv1.DownwardAPIProjection{
+								Items: []apiv1.DownwardAPIVolumeFile{{Path: "memory", ResourceFieldRef: memoryRef.DeepCopy()}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := translate(tr, nil, false); err != nil {
+			t.Fatal(err)
+		}
+		spec := tr.Deployment.Spec.Template.Spec
+		if spec.Containers[0].Name != "dev-dev" {
+			t.Fatalf("run %d: wrong dev container name: %s", i, spec.Containers[0].Name)
+		}
+		if spec.Containers[1].Name != "sidecar" {
+			t.Errorf("run %d: sidecar container was renamed: %s", i, spec.Containers[1].Name)
+		}
+		for _, key := range []string{defaultContainerAnnotation, defaultLogsContainerAnnotation} {
+			if value := tr.Deployment.Spec.Template.Annotations[key]; value != "dev-dev" {
+				t.Errorf("run %d: wrong %s annotation: %s", i, key, value)
+			}
+		}
+		if name := spec.Containers[1].Env[0].ValueFrom.ResourceFieldRef.ContainerName; name != "dev-dev" {
+			t.Errorf("run %d: wrong env resource field ref: %s", i, name)
+		}
+		if name := spec.Volumes[0].DownwardAPI.Items[0].ResourceFieldRef.ContainerName; name != "dev-dev" {
+			t.Errorf("run %d: wrong downward api resource field ref: %s", i, name)
+		}
+		if name := spec.Volumes[1].Projected.Sources[0].DownwardAPI.Items[0].ResourceFieldRef.ContainerName; name != "dev-dev" {
+			t.Errorf("run %d: wrong projected resource field ref: %s", i, name)
+		}
+	}
+
+	restored, err := TranslateDevModeOff(tr.Deployment, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := restored.Spec.Template.Spec
+	if spec.Containers[0].Name != "dev" {
+		t.Errorf("dev container name was not restored: %s", spec.Containers[0].Name)
+	}
+	if restored.Spec.Template.Annotations[defaultContainerAnnotation] != "dev" {
+		t.Errorf("default container annotation was not restored: %s", restored.Spec.Template.Annotations[defaultContainerAnnotation])
+	}
+	if name := spec.Containers[1].Env[0].ValueFrom.ResourceFieldRef.ContainerName; name != "dev" {
+		t.Errorf("env resource field ref was not restored: %s", name)
+	}
+	if name := spec.Volumes[0].DownwardAPI.Items[0].ResourceFieldRef.ContainerName; name != "dev" {
+		t.Errorf("downward api resource field ref was not restored: %s", name)
+	}
+}
+
+func Test_translateProjectedVolumes(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+projectedVolumes:
+  - name: config
+    sources:
+      - configMap:
+          name: dev-config
+  - name: podinfo
+    mountPath: /etc/podinfo
+    sources:
+      - downwardAPI:
+          items:
+            - path: name
+              fieldPath: metadata.name
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	config := apiv1.Volume{
+		Name: "config",
+		VolumeSource: apiv1.VolumeSource{
+			Projected: &apiv1.ProjectedVolumeSource{
+				Sources: []apiv1.VolumeProjection{
+					{
+						Secret: &apiv1.SecretProjection{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "app-secret"},
+							Items:                []apiv1.KeyToPath{{Key: "token", Path: "token"}},
+						},
+					},
+					{
+						DownwardAPI: &apiv1.DownwardAPIProjection{
+							Items: []apiv1.DownwardAPIVolumeFile{{Path: "labels", FieldRef: &apiv1.ObjectFieldSelector{FieldPath: "metadata.labels"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	d.Spec.Template.Spec.Volumes = []apiv1.Volume{*config.DeepCopy()}
+	d.Spec.Template.Spec.Containers[0].VolumeMounts = []apiv1.VolumeMount{{Name: "config", MountPath: "/etc/config", ReadOnly: true}}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	var gotConfig, gotPodinfo *apiv1.Volume
+	for i := range spec.Volumes {
+		switch spec.Volumes[i].Name {
+		case "config":
+			gotConfig = &spec.Volumes[i]
+		case "podinfo":
+			gotPodinfo = &spec.Volumes[i]
+		}
+	}
+	if gotConfig == nil || gotPodinfo == nil {
+		t.Fatalf("projected volumes not found: %+v", spec.Volumes)
+	}
+
+	expectedConfig := append(config.Projected.Sources, apiv1.VolumeProjection{
+		ConfigMap: &apiv1.ConfigMapProjection{LocalObjectReference: apiv1.LocalObjectReference{Name: "dev-config"}},
+	})
+	if !reflect.DeepEqual(gotConfig.Projected.Sources, expectedConfig) {
+		t.Errorf("wrong sources for the existing projected volume: %+v", gotConfig.Projected.Sources)
+	}
+	expectedPodinfo := []apiv1.VolumeProjection{
+		{
+			DownwardAPI: &apiv1.DownwardAPIProjection{
+				Items: []apiv1.DownwardAPIVolumeFile{{Path: "name", FieldRef: &apiv1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(gotPodinfo.Projected.Sources, expectedPodinfo) {
+		t.Errorf("wrong sources for the new projected volume: %+v", gotPodinfo.Projected.Sources)
+	}
+
+	mounts := map[string]string{}
+	for _, vm := range spec.Containers[0].VolumeMounts {
+		mounts[vm.Name] = vm.MountPath
+	}
+	if mounts["config"] != "/etc/config" {
+		t.Errorf("existing projected volume mount was not preserved: %+v", spec.Containers[0].VolumeMounts)
+	}
+	if mounts["podinfo"] != "/etc/podinfo" {
+		t.Errorf("new projected volume was not mounted: %+v", spec.Containers[0].VolumeMounts)
+	}
+
+	restored, err := TranslateDevModeOff(tr.Deployment, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.Spec.Template.Spec.Volumes, []apiv1.Volume{config}) {
+		t.Errorf("projected volumes were not restored: %+v", restored.Spec.Template.Spec.Volumes)
+	}
+}
+
+func Test_translateProjectedVolumesNotProjected(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+projectedVolumes:
+  - name: data
+    sources:
+      - configMap:
+          name: dev-config
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Template.Spec.Volumes = []apiv1.Volume{{Name: "data", VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}}}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err == nil {
+		t.Fatal("expected error when the volume is not a projected volume")
+	}
+}
+
+func Test_translateSharedCache(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+persistentVolume:
+  enabled: false
+sharedCache:
+  paths:
+    - /root/go/pkg/mod
+    - /root/.npm
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  dev.GevSandbox(),
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := tr.Deployment.Spec.Template.Spec
+	var sharedCache *apiv1.Volume
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == model.OktetoSharedCacheVolumeName {
+			sharedCache = &spec.Volumes[i]
+		}
+	}
+	if sharedCache == nil {
+		t.Fatalf("shared cache volume not found: %+v", spec.Volumes)
+	}
+	expectedSource := &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: model.OktetoSharedCacheVolumeName}
+	if !reflect.DeepEqual(sharedCache.PersistentVolumeClaim, expectedSource) {
+		t.Errorf("wrong shared cache volume source: %+v", sharedCache.VolumeSource)
+	}
+
+	expectedMounts := map[string]string{
+		"/root/go/pkg/mod": "cache/root/go/pkg/mod",
+		"/root/.npm":       "cache/root/.npm",
+	}
+	for _, vm := range spec.Containers[0].VolumeMounts {
+		if vm.Name != model.OktetoSharedCacheVolumeName {
+			continue
+		}
+		if expectedMounts[vm.MountPath] != vm.SubPath {
+			t.Errorf("wrong shared cache subpath for '%s': %s", vm.MountPath, vm.SubPath)
+		}
+		delete(expectedMounts, vm.MountPath)
+	}
+	if len(expectedMounts) > 0 {
+		t.Errorf("shared cache paths not mounted: %v", expectedMounts)
+	}
+}
+
+func Test_translatePausedDeployment(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Paused = true
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if d.Spec.Paused {
+		t.Errorf("deployment not resumed in dev mode")
+	}
+	if !tr.Paused {
+		t.Errorf("paused state not recorded in the translation")
+	}
+
+	dDown, err := TranslateDevModeOff(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dDown.Spec.Paused {
+		t.Errorf("paused state not restored")
+	}
+}
+
+func Test_translateMinReadySeconds(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.MinReadySeconds = 30
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if d.Spec.MinReadySeconds != 0 {
+		t.Errorf("minReadySeconds not zeroed in dev mode: %d", d.Spec.MinReadySeconds)
+	}
+	if tr.MinReadySeconds != 30 {
+		t.Errorf("minReadySeconds not recorded in the translation: %d", tr.MinReadySeconds)
+	}
+
+	dDown, err := TranslateDevModeOff(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dDown.Spec.MinReadySeconds != 30 {
+		t.Errorf("minReadySeconds not restored: %d", dDown.Spec.MinReadySeconds)
+	}
+}
+
+func Test_translateRevisionHistoryLimit(t *testing.T) {
+	var one int32 = 1
+	var five int32 = 5
+	var tests = []struct {
+		name     string
+		limit    *int32
+		expected int32
+	}{
+		{
+			name:     "default",
+			limit:    nil,
+			expected: devRevisionHistoryLimit,
+		},
+		{
+			name:     "higher",
+			limit:    &five,
+			expected: devRevisionHistoryLimit,
+		},
+		{
+			name:     "lower",
+			limit:    &one,
+			expected: 1,
+		},
+	}
+
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.RevisionHistoryLimit = tt.limit
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+			if d.Spec.RevisionHistoryLimit == nil || *d.Spec.RevisionHistoryLimit != tt.expected {
+				t.Errorf("wrong revisionHistoryLimit in dev mode: expected %d, got %v", tt.expected, d.Spec.RevisionHistoryLimit)
+			}
+
+			dDown, err := TranslateDevModeOff(d, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dDown.Spec.RevisionHistoryLimit, tt.limit) {
+				t.Errorf("revisionHistoryLimit not restored: expected %v, got %v", tt.limit, dDown.Spec.RevisionHistoryLimit)
+			}
+		})
+	}
+}
+
+func Test_translatePausedDeploymentServerSide(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	d.Spec.Paused = true
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	commonTranslation(tr)
+	if err := setTranslationAsAnnotation(d.Spec.Template.GetObjectMeta(), tr); err != nil {
+		t.Fatal(err)
+	}
+	if d.Spec.Paused {
+		t.Errorf("deployment not resumed in dev mode")
+	}
+
+	dDown, err := TranslateDevModeOff(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dDown.Spec.Paused {
+		t.Errorf("paused state not restored")
+	}
+}
+
+func Test_getRuntimeMemoryFlags(t *testing.T) {
+	var tests = []struct {
+		name     string
+		limit    string
+		expected []string
+	}{
+		{
+			name:     "1Gi",
+			limit:    "1Gi",
+			expected: []string{"-Xmx768m", "--max-old-space-size=768"},
+		},
+		{
+			name:     "512Mi",
+			limit:    "512Mi",
+			expected: []string{"-Xmx384m", "--max-old-space-size=384"},
+		},
+		{
+			name:     "decimal",
+			limit:    "2G",
+			expected: []string{"-Xmx1430m", "--max-old-space-size=1430"},
+		},
+		{
+			name:     "tiny",
+			limit:    "1Ki",
+			expected: []string{"-Xmx1m", "--max-old-space-size=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := getRuntimeMemoryFlags(resource.MustParse(tt.limit))
+			result := []string{}
+			for _, f := range flags {
+				result = append(result, f.String())
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_translateRuntimeMemoryFlags(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+runtimeMemoryFlags: true
+environment:
+  - JAVA_OPTS=-Dfoo=bar
+  - NODE_OPTIONS=--max-old-space-size=100
+resources:
+  limits:
+    memory: 1Gi
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{}
+	for _, e := range d.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if env["JAVA_OPTS"] != "-Dfoo=bar -Xmx768m" {
+		t.Errorf("wrong JAVA_OPTS: '%s'", env["JAVA_OPTS"])
+	}
+	if env["NODE_OPTIONS"] != "--max-old-space-size=100" {
+		t.Errorf("NODE_OPTIONS overridden: '%s'", env["NODE_OPTIONS"])
+	}
+
+	c := &apiv1.Container{Name: "dev"}
+	TranslateRuntimeMemoryFlags(c)
+	if len(c.Env) > 0 {
+		t.Errorf("flags injected without a memory limit: %v", c.Env)
+	}
+}
+
+func Test_translateDevContainerTTY(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	var tests = []struct {
+		name        string
+		interactive bool
+	}{
+		{
+			name:        "interactive",
+			interactive: true,
+		},
+		{
+			name:        "detached",
+			interactive: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: tt.interactive,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			c := d.Spec.Template.Spec.Containers[0]
+			if c.Stdin != tt.interactive || c.TTY != tt.interactive {
+				t.Errorf("wrong stdin/tty: expected %t, got stdin=%t tty=%t", tt.interactive, c.Stdin, c.TTY)
+			}
+		})
+	}
+}
+
+func Test_translateStdinOnce(t *testing.T) {
+	var tests = []struct {
+		name        string
+		manifest    []byte
+		interactive bool
+		expected    bool
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			interactive: true,
+			expected:    false,
+		},
+		{
+			name: "stdin-once",
+			manifest: []byte(`name: web
+namespace: n
+stdinOnce: true
+sync:
+  - .:/app`),
+			interactive: true,
+			expected:    true,
+		},
+		{
+			name: "detached",
+			manifest: []byte(`name: web
+namespace: n
+stdinOnce: true
+sync:
+  - .:/app`),
+			interactive: false,
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: tt.interactive,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			c := d.Spec.Template.Spec.Containers[0]
+			if c.StdinOnce != tt.expected {
+				t.Errorf("wrong stdinOnce: expected %t, got %t", tt.expected, c.StdinOnce)
+			}
+		})
+	}
+}
+
+func Test_translateBackupExclusion(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+
+	var tests = []struct {
+		name            string
+		backupExclusion bool
+	}{
+		{
+			name:            "enabled",
+			backupExclusion: true,
+		},
+		{
+			name:            "disabled",
+			backupExclusion: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Annotations = map[string]string{backupVolumesExcludesAnnotation: "tmp"}
+			d.Spec.Template.Spec.Volumes = []apiv1.Volume{{Name: "data"}}
+			tr := &model.Translation{
+				Interactive:     true,
+				Name:            dev.Name,
+				Version:         model.TranslationVersion,
+				Deployment:      d,
+				BackupExclusion: tt.backupExclusion,
+				Rules:           []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := "tmp"
+			if tt.backupExclusion {
+				for _, v := range d.Spec.Template.Spec.Volumes {
+					if v.Name != "data" {
+						expected = fmt.Sprintf("%s,%s", expected, v.Name)
+					}
+				}
+			}
+			if expected == "tmp" && tt.backupExclusion {
+				t.Fatalf("no volumes added by the translation")
+			}
+			if result := d.Spec.Template.Annotations[backupVolumesExcludesAnnotation]; result != expected {
+				t.Errorf("wrong backup exclusion annotation: expected '%s', got '%s'", expected, result)
+			}
+		})
+	}
+}
+
+func Test_translateCommandAndArgs(t *testing.T) {
+	var tests = []struct {
+		name            string
+		service         string
+		expectedCommand []string
+		expectedArgs    []string
+	}{
+		{
+			name: "args-only",
+			service: `
+    args: ["--debug"]`,
+			expectedCommand: []string{"./run.sh"},
+			expectedArgs:    []string{"--debug"},
+		},
+		{
+			name: "command-only",
+			service: `
+    command: ["./run_dev.sh"]`,
+			expectedCommand: []string{"./run_dev.sh"},
+			expectedArgs:    []string{},
+		},
+		{
+			name: "command-and-args",
+			service: `
+    command: ["./run_dev.sh"]
+    args: ["--debug", "--port=8080"]`,
+			expectedCommand: []string{"./run_dev.sh"},
+			expectedArgs:    []string{"--debug", "--port=8080"},
+		},
+		{
+			name:            "none",
+			service:         "",
+			expectedCommand: []string{"./run.sh"},
+			expectedArgs:    []string{"--port=80"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := []byte(fmt.Sprintf(`name: web
+namespace: n
+sync:
+  - .:/app
+services:
+  - name: worker
+    sync:
+      - .:/app%s`, tt.service))
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := dev.Services[0].ToTranslationRule(dev)
+			c := &apiv1.Container{
+				Name:    "worker",
+				Image:   "worker",
+				Command: []string{"./run.sh"},
+				Args:    []string{"--port=80"},
+			}
+			TranslateDevContainer(c, rule)
+			if !reflect.DeepEqual(c.Command, tt.expectedCommand) {
+				t.Errorf("wrong command: expected %v, got %v", tt.expectedCommand, c.Command)
+			}
+			if !reflect.DeepEqual(c.Args, tt.expectedArgs) {
+				t.Errorf("wrong args: expected %v, got %v", tt.expectedArgs, c.Args)
+			}
+		})
+	}
+}
+
+func Test_translateDevContainerCommandRule(t *testing.T) {
+	var tests = []struct {
+		name            string
+		rule            *model.TranslationRule
+		expectedCommand []string
+		expectedArgs    []string
+	}{
+		{
+			name:            "args-preserve-command",
+			rule:            &model.TranslationRule{Args: []string{"--debug"}},
+			expectedCommand: []string{"/entrypoint.sh"},
+			expectedArgs:    []string{"--debug"},
+		},
+		{
+			name:            "command-clears-args",
+			rule:            &model.TranslationRule{Command: []string{"sh"}},
+			expectedCommand: []string{"sh"},
+			expectedArgs:    nil,
+		},
+		{
+			name:            "none",
+			rule:            &model.TranslationRule{},
+			expectedCommand: []string{"/entrypoint.sh"},
+			expectedArgs:    []string{"serve"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Probes = &model.Probes{}
+			c := &apiv1.Container{
+				Name:    "dev",
+				Image:   "dev",
+				Command: []string{"/entrypoint.sh"},
+				Args:    []string{"serve"},
+			}
+			TranslateDevContainer(c, tt.rule)
+			if !reflect.DeepEqual(c.Command, tt.expectedCommand) {
+				t.Errorf("wrong command: expected %v, got %v", tt.expectedCommand, c.Command)
+			}
+			if !reflect.DeepEqual(c.Args, tt.expectedArgs) {
+				t.Errorf("wrong args: expected %v, got %v", tt.expectedArgs, c.Args)
+			}
+		})
+	}
+}
+
+func Test_translateInotifyInitContainer(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected []string
+	}{
+		{
+			name: "disabled",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: nil,
+		},
+		{
+			name: "default-watches",
+			manifest: []byte(`name: web
+namespace: n
+inotifyTuning: {}
+sync:
+  - .:/app`),
+			expected: []string{
+				"sh",
+				"-c",
+				"{ [ $(cat /proc/sys/fs/inotify/max_user_watches) -ge 524288 ] || echo 524288 > /proc/sys/fs/inotify/max_user_watches; }",
+			},
+		},
+		{
+			name: "custom-limits",
+			manifest: []byte(`name: web
+namespace: n
+inotifyTuning:
+  maxUserWatches: 1048576
+  maxUserInstances: 1024
+sync:
+  - .:/app`),
+			expected: []string{
+				"sh",
+				"-c",
+				"{ [ $(cat /proc/sys/fs/inotify/max_user_watches) -ge 1048576 ] || echo 1048576 > /proc/sys/fs/inotify/max_user_watches; } && " +
+					"{ [ $(cat /proc/sys/fs/inotify/max_user_instances) -ge 1024 ] || echo 1024 > /proc/sys/fs/inotify/max_user_instances; }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			var inotify *apiv1.Container
+			for i := range d.Spec.Template.Spec.InitContainers {
+				if d.Spec.Template.Spec.InitContainers[i].Name == oktetoInotifyName {
+					inotify = &d.Spec.Template.Spec.InitContainers[i]
+				}
+			}
+			if tt.expected == nil {
+				if inotify != nil {
+					t.Fatalf("unexpected inotify init container: %+v", inotify)
+				}
+				return
+			}
+			if inotify == nil {
+				t.Fatalf("inotify init container not found: %+v", d.Spec.Template.Spec.InitContainers)
+			}
+			if !reflect.DeepEqual(inotify.Command, tt.expected) {
+				t.Errorf("wrong inotify command:\nexpected %v\ngot      %v", tt.expected, inotify.Command)
+			}
+			sc := inotify.SecurityContext
+			if sc == nil || sc.Privileged == nil || !*sc.Privileged || sc.RunAsUser == nil || *sc.RunAsUser != 0 {
+				t.Errorf("inotify init container is not privileged: %+v", sc)
+			}
+		})
+	}
+}
+
+func Test_translateGPUFallback(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+gpuFallback: true
+resources:
+  limits:
+    cpu: 2
+    nvidia.com/gpu: 1
+sync:
+  - .:/app`)
+
+	var tests = []struct {
+		name        string
+		gpuDisabled bool
+	}{
+		{
+			name:        "gpu",
+			gpuDisabled: false,
+		},
+		{
+			name:        "fallback",
+			gpuDisabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dev.GPUDisabled = tt.gpuDisabled
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.Containers[0].Resources = apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{model.ResourceAMDGPU: resource.MustParse("1")},
+			}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			resources := d.Spec.Template.Spec.Containers[0].Resources
+			_, nvidia := resources.Limits[model.ResourceNVIDIAGPU]
+			_, amd := resources.Requests[model.ResourceAMDGPU]
+			if tt.gpuDisabled && (nvidia || amd) {
+				t.Errorf("gpu resources not removed: %+v", resources)
+			}
+			if !tt.gpuDisabled && (!nvidia || !amd) {
+				t.Errorf("gpu resources removed: %+v", resources)
+			}
+			if _, ok := resources.Limits[apiv1.ResourceCPU]; !ok {
+				t.Errorf("cpu limit removed: %+v", resources)
+			}
+		})
+	}
+}
+
+func Test_translateSidecarResources(t *testing.T) {
+	manifest := []byte(`name: web
+namespace: n
+container: dev
+sidecarResources:
+  proxy:
+    requests:
+      cpu: 50m
+      memory: 64Mi
+    limits:
+      cpu: 100m
+      memory: 128Mi
+sync:
+  - .:/app`)
+
+	dev, err := model.Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := dev.GevSandbox()
+	original := apiv1.ResourceRequirements{
+		Requests: apiv1.ResourceList{
+			apiv1.ResourceCPU:    resource.MustParse("1"),
+			apiv1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+		Limits: apiv1.ResourceList{
+			apiv1.ResourceCPU:    resource.MustParse("2"),
+			apiv1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, apiv1.Container{
+		Name:      "proxy",
+		Image:     "envoy",
+		Resources: *original.DeepCopy(),
+	})
+	tr := &model.Translation{
+		Interactive: true,
+		Name:        dev.Name,
+		Version:     model.TranslationVersion,
+		Deployment:  d,
+		Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+	}
+	if err := translate(tr, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := GetDevContainer(&d.Spec.Template.Spec, "proxy")
+	expected := map[apiv1.ResourceName]string{apiv1.ResourceCPU: "100m", apiv1.ResourceMemory: "128Mi"}
+	for name, value := range expected {
+		if limit := proxy.Resources.Limits[name]; limit.String() != value {
+			t.Errorf("wrong proxy limit for %s: %s", name, limit.String())
+		}
+	}
+	expected = map[apiv1.ResourceName]string{apiv1.ResourceCPU: "50m", apiv1.ResourceMemory: "64Mi"}
+	for name, value := range expected {
+		if request := proxy.Resources.Requests[name]; request.String() != value {
+			t.Errorf("wrong proxy request for %s: %s", name, request.String())
+		}
+	}
+
+	dDown, err := TranslateDevModeOff(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy = GetDevContainer(&dDown.Spec.Template.Spec, "proxy")
+	if !reflect.DeepEqual(proxy.Resources, original) {
+		t.Errorf("proxy resources not restored: %+v", proxy.Resources)
+	}
+
+	rule := dev.ToTranslationRule(dev)
+	rule.SidecarResources = model.ContainerResources{"missing": {}}
+	if err := TranslateSidecarResources(&d.Spec.Template.Spec, rule); err == nil {
+		t.Errorf("missing sidecar container didn't fail")
+	}
+}
+
+func Test_translateTerminationGracePeriod(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected int64
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: devTerminationGracePeriodSeconds,
+		},
+		{
+			name: "dev",
+			manifest: []byte(`name: web
+namespace: n
+gracePeriod:
+  dev: 30
+  recovery: 5
+sync:
+  - .:/app`),
+			expected: 30,
+		},
+		{
+			name: "recovery",
+			manifest: []byte(`name: web
+namespace: n
+gracePeriod:
+  recovery: 5
+sync:
+  - .:/app`),
+			expected: devTerminationGracePeriodSeconds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			grace := d.Spec.Template.Spec.TerminationGracePeriodSeconds
+			if grace == nil || *grace != tt.expected {
+				t.Errorf("wrong termination grace period: expected %d, got %v", tt.expected, grace)
+			}
+		})
+	}
+}
+
+func Test_translateHostPathVolumes(t *testing.T) {
+	var tests = []struct {
+		name          string
+		allowHostPath bool
+		expectErr     bool
+	}{
+		{
+			name:      "rejected",
+			expectErr: true,
+		},
+		{
+			name:          "allowed",
+			allowHostPath: true,
+			expectErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			d.Spec.Template.Spec.Volumes = []apiv1.Volume{
+				{
+					Name: dev.GetVolumeName(),
+					VolumeSource: apiv1.VolumeSource{
+						HostPath: &apiv1.HostPathVolumeSource{Path: "/data"},
+					},
+				},
+			}
+			tr := &model.Translation{
+				Interactive:   true,
+				Name:          dev.Name,
+				Version:       model.TranslationVersion,
+				Deployment:    d,
+				AllowHostPath: tt.allowHostPath,
+				Rules:         []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			err = translate(tr, nil, false)
+			if tt.expectErr && err == nil {
+				t.Fatal("expected error for a hostPath volume")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_translateReadOnlyRootFilesystemPolicy(t *testing.T) {
+	var tests = []struct {
+		name                   string
+		readOnlyRootFilesystem bool
+		podSecurity            string
+		expectErr              bool
+	}{
+		{
+			name:                   "restricted-read-only",
+			readOnlyRootFilesystem: true,
+			podSecurity:            namespaces.PodSecurityRestricted,
+			expectErr:              true,
+		},
+		{
+			name:        "restricted-writable",
+			podSecurity: namespaces.PodSecurityRestricted,
+			expectErr:   false,
+		},
+		{
+			name:                   "baseline-read-only",
+			readOnlyRootFilesystem: true,
+			podSecurity:            "baseline",
+			expectErr:              false,
+		},
+		{
+			name:                   "no-policy-read-only",
+			readOnlyRootFilesystem: true,
+			expectErr:              false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := []byte(`name: web
+namespace: n
+sync:
+  - .:/app`)
+			dev, err := model.Read(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			readOnlyRootFilesystem := tt.readOnlyRootFilesystem
+			d.Spec.Template.Spec.Containers[0].SecurityContext = &apiv1.SecurityContext{
+				ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+			}
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				PodSecurity: tt.podSecurity,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			err = translate(tr, nil, false)
+			if tt.expectErr && err == nil {
+				t.Fatal("expected error for a read-only root filesystem in a restricted namespace")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_getDevReplicas(t *testing.T) {
+	var three int32 = 3
+	var tests = []struct {
+		name        string
+		interactive bool
+		devReplicas *int32
+		expected    int32
+	}{
+		{
+			name:        "interactive-default",
+			interactive: true,
+			expected:    1,
+		},
+		{
+			name:        "interactive-clamped",
+			interactive: true,
+			devReplicas: &three,
+			expected:    1,
+		},
+		{
+			name:        "detached-default",
+			interactive: false,
+			expected:    1,
+		},
+		{
+			name:        "detached-replicas",
+			interactive: false,
+			devReplicas: &three,
+			expected:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &model.Translation{
+				Name:        "web",
+				Interactive: tt.interactive,
+				DevReplicas: tt.devReplicas,
+			}
+			result := getDevReplicas(tr)
+			if *result != tt.expected {
+				t.Errorf("wrong replicas: expected %d, got %d", tt.expected, *result)
+			}
+		})
+	}
+}
+
+func Test_translateContainerProbes(t *testing.T) {
+	var tests = []struct {
+		name              string
+		manifest          []byte
+		expectedLiveness  bool
+		expectedReadiness bool
+	}{
+		{
+			name: "global",
+			manifest: []byte(`name: web
+namespace: n
+container: dev
+probes:
+  readiness: true
+sync:
+  - .:/app`),
+			expectedLiveness:  false,
+			expectedReadiness: true,
+		},
+		{
+			name: "override",
+			manifest: []byte(`name: web
+namespace: n
+container: dev
+probes:
+  readiness: true
+containerProbes:
+  dev:
+    liveness: true
+sync:
+  - .:/app`),
+			expectedLiveness:  true,
+			expectedReadiness: false,
+		},
+		{
+			name: "override-other-container",
+			manifest: []byte(`name: web
+namespace: n
+container: dev
+probes:
+  readiness: true
+containerProbes:
+  api: true
+sync:
+  - .:/app`),
+			expectedLiveness:  false,
+			expectedReadiness: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			probe := &apiv1.Probe{
+				Handler: apiv1.Handler{
+					HTTPGet: &apiv1.HTTPGetAction{Path: "/healthz"},
+				},
+			}
+			d.Spec.Template.Spec.Containers[0].LivenessProbe = probe
+			d.Spec.Template.Spec.Containers[0].ReadinessProbe = probe
+			tr := &model.Translation{
+				Interactive: true,
+				Name:        dev.Name,
+				Version:     model.TranslationVersion,
+				Deployment:  d,
+				Rules:       []*model.TranslationRule{dev.ToTranslationRule(dev)},
+			}
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			c := d.Spec.Template.Spec.Containers[0]
+			if (c.LivenessProbe != nil) != tt.expectedLiveness {
+				t.Errorf("wrong liveness probe: expected %t, got %+v", tt.expectedLiveness, c.LivenessProbe)
+			}
+			if (c.ReadinessProbe != nil) != tt.expectedReadiness {
+				t.Errorf("wrong readiness probe: expected %t, got %+v", tt.expectedReadiness, c.ReadinessProbe)
+			}
+		})
+	}
+}
+
+func Test_translateEnvVars(t *testing.T) {
+	var tests = []struct {
+		name     string
+		env      []apiv1.EnvVar
+		rule     []model.EnvVar
+		expected []apiv1.EnvVar
+	}{
+		{
+			name:     "empty",
+			env:      nil,
+			rule:     nil,
+			expected: nil,
+		},
+		{
+			name: "append-in-rule-order",
+			env:  []apiv1.EnvVar{{Name: "A", Value: "a"}},
+			rule: []model.EnvVar{{Name: "C", Value: "c"}, {Name: "B", Value: "b"}},
+			expected: []apiv1.EnvVar{
+				{Name: "A", Value: "a"},
+				{Name: "C", Value: "c"},
+				{Name: "B", Value: "b"},
+			},
+		},
+		{
+			name: "duplicated-in-container",
+			env: []apiv1.EnvVar{
+				{Name: "FOO", Value: "one"},
+				{Name: "BAR", Value: "bar"},
+				{Name: "FOO", Value: "two"},
+			},
+			rule: []model.EnvVar{{Name: "FOO", Value: "dev"}},
+			expected: []apiv1.EnvVar{
+				{Name: "FOO", Value: "dev"},
+				{Name: "BAR", Value: "bar"},
+			},
+		},
+		{
+			name: "duplicated-in-rule",
+			env:  []apiv1.EnvVar{{Name: "BAR", Value: "bar"}},
+			rule: []model.EnvVar{{Name: "FOO", Value: "one"}, {Name: "BAR", Value: "dev"}, {Name: "FOO", Value: "two"}},
+			expected: []apiv1.EnvVar{
+				{Name: "BAR", Value: "dev"},
+				{Name: "FOO", Value: "two"},
+			},
+		},
+		{
+			name: "value-from",
+			env:  []apiv1.EnvVar{{Name: "DB_PASSWORD", Value: "local"}},
+			rule: []model.EnvVar{
+				{Name: "DB_PASSWORD", ValueFrom: &model.EnvVarSource{SecretKeyRef: &model.KeySelector{Name: "db", Key: "password"}}},
+				{Name: "LOG_LEVEL", ValueFrom: &model.EnvVarSource{ConfigMapKeyRef: &model.KeySelector{Name: "settings", Key: "log-level"}}},
+			},
+			expected: []apiv1.EnvVar{
+				{
+					Name: "DB_PASSWORD",
+					ValueFrom: &apiv1.EnvVarSource{
+						SecretKeyRef: &apiv1.SecretKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "db"}, Key: "password"},
+					},
+				},
+				{
+					Name: "LOG_LEVEL",
+					ValueFrom: &apiv1.EnvVarSource{
+						ConfigMapKeyRef: &apiv1.ConfigMapKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: "settings"}, Key: "log-level"},
+					},
+				},
+			},
+		},
+		{
+			name: "override-value-from",
+			env: []apiv1.EnvVar{
+				{Name: "FOO", ValueFrom: &apiv1.EnvVarSource{FieldRef: &apiv1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			},
+			rule:     []model.EnvVar{{Name: "FOO", Value: "dev"}},
+			expected: []apiv1.EnvVar{{Name: "FOO", Value: "dev"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &apiv1.Container{Env: tt.env}
+			TranslateEnvVars(c, &model.TranslationRule{Environment: tt.rule})
+			if !reflect.DeepEqual(c.Env, tt.expected) {
+				t.Errorf("wrong env vars: expected %+v, got %+v", tt.expected, c.Env)
+			}
+		})
+	}
+}
+
+func Test_translatePreventEviction(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected string
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: web
+namespace: n
+sync:
+  - .:/app`),
+			expected: "false",
+		},
+		{
+			name: "safe-to-evict",
+			manifest: []byte(`name: web
+namespace: n
+safeToEvict: true
+sync:
+  - .:/app`),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := model.Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := dev.GevSandbox()
+			trList, err := GetTranslations(context.Background(), dev, d, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr := trList[d.Name]
+			if err := translate(tr, nil, false); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := d.Spec.Template.Annotations[safeToEvictAnnotation]; got != tt.expected {
+				t.Errorf("wrong '%s' annotation: expected '%s', got '%s'", safeToEvictAnnotation, tt.expected, got)
+			}
+		})
+	}
+}
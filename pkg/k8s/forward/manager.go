@@ -123,6 +123,11 @@ func (p *PortForwardManager) AddReverse(_ model.Reverse) error {
 	return fmt.Errorf("not implemented")
 }
 
+// AddSocket is not implemented
+func (p *PortForwardManager) AddSocket(_ model.SocketForward) error {
+	return fmt.Errorf("not implemented")
+}
+
 // Start starts all the port forwarders to the development container
 func (p *PortForwardManager) Start(devPod, namespace string) error {
 	p.stopped = false
@@ -34,6 +34,9 @@ const (
 	// DetachedDevLabel indicates the detached dev pods
 	DetachedDevLabel = "detached.dev.okteto.com"
 
+	// SharedCacheLabel indicates the cache volume shared by the dev pods of a namespace
+	SharedCacheLabel = "shared-cache.dev.okteto.com"
+
 	// RevisionAnnotation indicates the revision when the development container was activated
 	RevisionAnnotation = "dev.okteto.com/revision"
 
@@ -55,6 +58,12 @@ const (
 	//OktetoPathAnnotation indicates the okteto manifest path of this component
 	OktetoPathAnnotation = "dev.okteto.com/path"
 
+	//GitCommitAnnotation indicates the local git commit when the development container was activated
+	GitCommitAnnotation = "dev.okteto.com/git-commit"
+
+	//GitBranchAnnotation indicates the local git branch when the development container was activated
+	GitBranchAnnotation = "dev.okteto.com/git-branch"
+
 	//FluxAnnotation indicates if the deployment ha been deployed by Flux
 	FluxAnnotation = "helm.fluxcd.io/antecedent"
 
@@ -75,6 +84,12 @@ const (
 
 	// OktetoAutoIngressAnnotation indicates an ingress must be crreated for a service
 	OktetoAutoIngressAnnotation = "dev.okteto.com/auto-ingress"
+
+	// AllowHostPathLabel allows the volumes of development containers to resolve to hostPath volumes in a namespace
+	AllowHostPathLabel = "dev.okteto.com/allow-host-path"
+
+	// MaxVolumeSizeAnnotation sets the maximum size of the persistent volumes of development containers in a namespace
+	MaxVolumeSizeAnnotation = "dev.okteto.com/max-volume-size"
 )
 
 //TransformLabelsToSelector transforms a map of labels into a string k8s selector
@@ -15,10 +15,14 @@ package namespaces
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/okteto/okteto/pkg/errors"
 	okLabels "github.com/okteto/okteto/pkg/k8s/labels"
 	"k8s.io/client-go/kubernetes"
 )
@@ -26,6 +30,15 @@ import (
 const (
 	// OktetoNotAllowedLabel tells Okteto to not allow operations on the namespace
 	OktetoNotAllowedLabel = "dev.okteto.com/not-allowed"
+
+	//podSecurityEnforceLabel is the label that enforces a pod security standard on the namespace
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	//PodSecurityRestricted is the most restrictive pod security standard
+	PodSecurityRestricted = "restricted"
+
+	//uidRangeAnnotation is the annotation with the range of uids allowed in the namespace, as "<start>/<size>"
+	uidRangeAnnotation = "openshift.io/sa.scc.uid-range"
 )
 
 //IsOktetoNamespace checks if this is a namespace created by okteto
@@ -42,8 +55,59 @@ func IsOktetoAllowed(ns *apiv1.Namespace) bool {
 	return true
 }
 
+//IsHostPathAllowed checks if the volumes of development containers can resolve to hostPath volumes in this namespace
+func IsHostPathAllowed(ns *apiv1.Namespace) bool {
+	return ns.Labels[okLabels.AllowHostPathLabel] == "true"
+}
+
+//GetMaxVolumeSize returns the maximum size of the persistent volumes of development containers in this namespace, if any
+func GetMaxVolumeSize(ns *apiv1.Namespace) string {
+	return ns.Annotations[okLabels.MaxVolumeSizeAnnotation]
+}
+
+//GetPodSecurityLevel returns the pod security standard enforced on the namespace, if any
+func GetPodSecurityLevel(ns *apiv1.Namespace) string {
+	return ns.Labels[podSecurityEnforceLabel]
+}
+
+//GetUserPolicyWarning returns a warning if the policies of the namespace forbid running containers as the given uid
+func GetUserPolicyWarning(ns *apiv1.Namespace, uid int64) string {
+	if uid == 0 && GetPodSecurityLevel(ns) == PodSecurityRestricted {
+		return fmt.Sprintf("The namespace '%s' enforces the '%s' pod security standard: your development container will be rejected if it runs as your local root user", ns.Name, PodSecurityRestricted)
+	}
+
+	uidRange, ok := ns.Annotations[uidRangeAnnotation]
+	if !ok {
+		return ""
+	}
+	start, size, err := parseUIDRange(uidRange)
+	if err != nil {
+		return fmt.Sprintf("The namespace '%s' has an invalid uid range '%s': your development container might be rejected if it runs as your local user %d", ns.Name, uidRange, uid)
+	}
+	if uid < start || uid >= start+size {
+		return fmt.Sprintf("The namespace '%s' only allows uids from %d to %d: your development container will be rejected if it runs as your local user %d", ns.Name, start, start+size-1, uid)
+	}
+	return ""
+}
+
+func parseUIDRange(uidRange string) (int64, int64, error) {
+	parts := strings.SplitN(uidRange, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed uid range '%s'", uidRange)
+	}
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, size, nil
+}
+
 // Get returns the namespace object of ns
-func Get(ctx context.Context, ns string, c *kubernetes.Clientset) (*apiv1.Namespace, error) {
+func Get(ctx context.Context, ns string, c kubernetes.Interface) (*apiv1.Namespace, error) {
 	n, err := c.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -51,3 +115,20 @@ func Get(ctx context.Context, ns string, c *kubernetes.Clientset) (*apiv1.Namesp
 
 	return n, nil
 }
+
+//CheckNotTerminating fails if the namespace ns is being deleted
+func CheckNotTerminating(ctx context.Context, ns string, c kubernetes.Interface) error {
+	n, err := Get(ctx, ns, c)
+	if err != nil {
+		return err
+	}
+
+	if n.Status.Phase == apiv1.NamespaceTerminating {
+		return errors.UserError{
+			E:    fmt.Errorf("namespace '%s' is being deleted", ns),
+			Hint: "Wait until the namespace is deleted and recreate it, or run 'okteto up' in a different namespace",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetUserPolicyWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		uid         int64
+		expectWarn  bool
+	}{
+		{
+			name: "no-policy",
+			uid:  0,
+		},
+		{
+			name:       "restricted-root",
+			labels:     map[string]string{podSecurityEnforceLabel: PodSecurityRestricted},
+			uid:        0,
+			expectWarn: true,
+		},
+		{
+			name:   "restricted-non-root",
+			labels: map[string]string{podSecurityEnforceLabel: PodSecurityRestricted},
+			uid:    1000,
+		},
+		{
+			name:   "baseline-root",
+			labels: map[string]string{podSecurityEnforceLabel: "baseline"},
+			uid:    0,
+		},
+		{
+			name:        "uid-in-range",
+			annotations: map[string]string{uidRangeAnnotation: "1000680000/10000"},
+			uid:         1000680000,
+		},
+		{
+			name:        "uid-out-of-range",
+			annotations: map[string]string{uidRangeAnnotation: "1000680000/10000"},
+			uid:         501,
+			expectWarn:  true,
+		},
+		{
+			name:        "uid-range-upper-bound",
+			annotations: map[string]string{uidRangeAnnotation: "1000680000/10000"},
+			uid:         1000690000,
+			expectWarn:  true,
+		},
+		{
+			name:        "invalid-uid-range",
+			annotations: map[string]string{uidRangeAnnotation: "invalid"},
+			uid:         501,
+			expectWarn:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &apiv1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ns",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+			}
+			warning := GetUserPolicyWarning(ns, tt.uid)
+			if tt.expectWarn && warning == "" {
+				t.Error("expected a warning")
+			}
+			if !tt.expectWarn && warning != "" {
+				t.Errorf("unexpected warning: %s", warning)
+			}
+		})
+	}
+}
+
+func TestCheckNotTerminating(t *testing.T) {
+	tests := []struct {
+		name      string
+		phase     apiv1.NamespacePhase
+		expectErr bool
+	}{
+		{
+			name:  "active",
+			phase: apiv1.NamespaceActive,
+		},
+		{
+			name:      "terminating",
+			phase:     apiv1.NamespaceTerminating,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset(&apiv1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status:     apiv1.NamespaceStatus{Phase: tt.phase},
+			})
+			err := CheckNotTerminating(context.Background(), "test", c)
+			if tt.expectErr && err == nil {
+				t.Fatal("expected error for a terminating namespace")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	c := fake.NewSimpleClientset()
+	if err := CheckNotTerminating(context.Background(), "missing", c); err == nil {
+		t.Error("expected error for a missing namespace")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"context"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetArchitectures returns the sorted architectures of the nodes that match the node selector
+func GetArchitectures(ctx context.Context, nodeSelector map[string]string, c kubernetes.Interface) ([]string, error) {
+	nodes, err := c.CoreV1().Nodes().List(
+		ctx,
+		metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(nodeSelector).String(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	result := []string{}
+	for _, n := range nodes.Items {
+		arch := n.Labels[apiv1.LabelArchStable]
+		if arch == "" {
+			arch = n.Status.NodeInfo.Architecture
+		}
+		if arch == "" || seen[arch] {
+			continue
+		}
+		seen[arch] = true
+		result = append(result, arch)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetArchitectures(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		&apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: "amd-1", Labels: map[string]string{apiv1.LabelArchStable: "amd64", "pool": "default"}}},
+		&apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: "amd-2", Labels: map[string]string{apiv1.LabelArchStable: "amd64", "pool": "default"}}},
+		&apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: "arm-1", Labels: map[string]string{apiv1.LabelArchStable: "arm64", "pool": "arm"}}},
+		&apiv1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Labels: map[string]string{"pool": "legacy"}},
+			Status:     apiv1.NodeStatus{NodeInfo: apiv1.NodeSystemInfo{Architecture: "ppc64le"}},
+		},
+	)
+	var tests = []struct {
+		name         string
+		nodeSelector map[string]string
+		expected     []string
+	}{
+		{name: "all-nodes", expected: []string{"amd64", "arm64", "ppc64le"}},
+		{name: "default-pool", nodeSelector: map[string]string{"pool": "default"}, expected: []string{"amd64"}},
+		{name: "arch-selector", nodeSelector: map[string]string{apiv1.LabelArchStable: "arm64"}, expected: []string{"arm64"}},
+		{name: "status-architecture", nodeSelector: map[string]string{"pool": "legacy"}, expected: []string{"ppc64le"}},
+		{name: "no-nodes", nodeSelector: map[string]string{"pool": "gpu"}, expected: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetArchitectures(context.Background(), tt.nodeSelector, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@
 package pods
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/okteto/okteto/pkg/config"
@@ -39,13 +41,17 @@ import (
 )
 
 const (
+	oomKilledReason              = "OOMKilled"
 	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
 	maxRetriesPodRunning         = 300 //1min pod is created
+	defaultCrashLoopThreshold    = 3
+	crashLoopThresholdEnvVar     = "OKTETO_CRASHLOOP_THRESHOLD"
 )
 
 var (
 	devTerminationGracePeriodSeconds int64
 	limitBytes                       int64 = 5 * 1024 * 1024 // 5Mb
+	replaceCheckInterval                   = 1 * time.Second
 )
 
 // GetBySelector returns the first pod that matches the selector or error if not found
@@ -229,11 +235,16 @@ func Exists(ctx context.Context, podName, namespace string, c kubernetes.Interfa
 
 //Destroy destroys a pod by name
 func Destroy(ctx context.Context, podName, namespace string, c kubernetes.Interface) error {
+	return DestroyWithGracePeriod(ctx, podName, namespace, devTerminationGracePeriodSeconds, c)
+}
+
+//DestroyWithGracePeriod destroys a pod giving its containers the given seconds to terminate
+func DestroyWithGracePeriod(ctx context.Context, podName, namespace string, grace int64, c kubernetes.Interface) error {
 	err := c.CoreV1().Pods(namespace).Delete(
 		ctx,
 		podName,
 		metav1.DeleteOptions{
-			GracePeriodSeconds: &devTerminationGracePeriodSeconds,
+			GracePeriodSeconds: &grace,
 		},
 	)
 	if err != nil && !errors.IsNotFound(err) {
@@ -242,6 +253,221 @@ func Destroy(ctx context.Context, podName, namespace string, c kubernetes.Interf
 	return nil
 }
 
+//WaitUntilReplaced waits until the pods matching the selector of the dev pod deployment, other than the dev pod, are removed.
+//This way, services stop routing traffic to the original pods before the dev pod is used
+func WaitUntilReplaced(ctx context.Context, pod *apiv1.Pod, selector map[string]string, c kubernetes.Interface) error {
+	to := config.GetTimeout()
+	timeout := time.Now().Add(to)
+	ticker := time.NewTicker(replaceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		ps, err := ListBySelector(ctx, pod.Namespace, selector, c)
+		if err != nil {
+			return fmt.Errorf("failed to get the pods replaced by the development container: %s", err)
+		}
+		old := getReplacedPod(ps, pod.Name)
+		if old == "" {
+			return nil
+		}
+
+		log.Infof("waiting for pod/%s to be removed", old)
+		if time.Now().After(timeout) {
+			return errors.UserError{
+				E:    fmt.Errorf("pod '%s' wasn't removed after %s", old, to.String()),
+				Hint: fmt.Sprintf("Delete it with 'kubectl delete pod %s' and try again", old),
+			}
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Info("call to pods.WaitUntilReplaced cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+func getReplacedPod(ps []apiv1.Pod, podName string) string {
+	for i := range ps {
+		if ps[i].Name == podName {
+			continue
+		}
+		if ps[i].Status.Phase == apiv1.PodSucceeded || ps[i].Status.Phase == apiv1.PodFailed {
+			continue
+		}
+		return ps[i].Name
+	}
+	return ""
+}
+
+//MustBeRecreated returns true if the running dev pod doesn't match the image, resources or volumes of the translated pod spec
+func MustBeRecreated(pod *apiv1.Pod, spec *apiv1.PodSpec, container string) bool {
+	running := getContainer(pod.Spec.Containers, container)
+	expected := getContainer(spec.Containers, container)
+	if running == nil || expected == nil {
+		return false
+	}
+	if running.Image != expected.Image {
+		log.Infof("dev pod image changed from '%s' to '%s'", running.Image, expected.Image)
+		return true
+	}
+	if !containsResources(running.Resources.Requests, expected.Resources.Requests) || !containsResources(running.Resources.Limits, expected.Resources.Limits) {
+		log.Infof("dev pod resources changed")
+		return true
+	}
+	volumes := map[string]bool{}
+	for _, v := range pod.Spec.Volumes {
+		volumes[v.Name] = true
+	}
+	for _, v := range spec.Volumes {
+		if !volumes[v.Name] {
+			log.Infof("dev pod volume '%s' is missing", v.Name)
+			return true
+		}
+	}
+	return false
+}
+
+func getContainer(containers []apiv1.Container, name string) *apiv1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+//containsResources ignores resources not defined in expected, since they might be set by admission controllers
+func containsResources(running, expected apiv1.ResourceList) bool {
+	for name, quantity := range expected {
+		value, ok := running[name]
+		if !ok || value.Cmp(quantity) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//Recreate destroys the dev pod and returns the pod created to replace it
+func Recreate(ctx context.Context, pod *apiv1.Pod, dev *model.Dev, c *kubernetes.Clientset) (*apiv1.Pod, error) {
+	if err := Destroy(ctx, pod.Name, pod.Namespace, c); err != nil {
+		return nil, fmt.Errorf("error recreating development container: %s", err.Error())
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	timeout := time.Now().Add(4 * config.GetTimeout())
+	for {
+		newPod, err := GetDevPodInLoop(ctx, dev, c, false)
+		if err != nil {
+			return nil, err
+		}
+		if newPod.Name != pod.Name {
+			return newPod, nil
+		}
+		if time.Now().After(timeout) {
+			return nil, fmt.Errorf("kubernetes is taking too long to recreate your development container. Please check for errors and try again")
+		}
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Debug("call to pod.Recreate cancelled")
+			return nil, ctx.Err()
+		}
+	}
+}
+
+//GetRestarts returns the number of restarts of a container of the pod
+func GetRestarts(pod *apiv1.Pod, container string) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
+
+//GetOOMKilledError returns an error if the container of the pod is terminated for running out of memory,
+//or if it was killed for running out of memory after having restarted more than restarts times
+func GetOOMKilledError(pod *apiv1.Pod, container string, restarts int32) error {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != container {
+			continue
+		}
+		lastOOMKilled := isOOMKilled(status.LastTerminationState) && status.RestartCount > restarts
+		if !isOOMKilled(status.State) && !lastOOMKilled {
+			return nil
+		}
+		hint := "Set 'resources.limits.memory' in your okteto manifest to increase the memory available to your development container"
+		if c := getContainer(pod.Spec.Containers, container); c != nil {
+			if limit, ok := c.Resources.Limits[apiv1.ResourceMemory]; ok {
+				hint = fmt.Sprintf("Increase the value of 'resources.limits.memory' in your okteto manifest (current limit: %s)", limit.String())
+			}
+		}
+		return errors.UserError{
+			E:    errors.ErrDevContainerOOMKilled,
+			Hint: hint,
+		}
+	}
+	return nil
+}
+
+//GetCrashLoopThreshold returns the number of restarts of the dev container that is reported as a crash loop
+func GetCrashLoopThreshold() int32 {
+	v, ok := os.LookupEnv(crashLoopThresholdEnvVar)
+	if !ok {
+		return defaultCrashLoopThreshold
+	}
+	threshold, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || threshold <= 0 {
+		log.Infof("'%s' is not a valid value for %s, ignoring", v, crashLoopThresholdEnvVar)
+		return defaultCrashLoopThreshold
+	}
+	return int32(threshold)
+}
+
+//GetCrashLoopError returns an error if the container of the pod is not running and has restarted at least threshold times
+//since it had restarted restarts times
+func GetCrashLoopError(pod *apiv1.Pod, container string, restarts, threshold int32) error {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != container {
+			continue
+		}
+		sessionRestarts := status.RestartCount - restarts
+		if status.State.Running != nil || sessionRestarts < threshold {
+			return nil
+		}
+		reason := "unknown"
+		if t := status.LastTerminationState.Terminated; t != nil {
+			reason = fmt.Sprintf("%s (exit code %d)", t.Reason, t.ExitCode)
+			if t.Message != "" {
+				reason = fmt.Sprintf("%s: %s", reason, t.Message)
+			}
+		}
+		return errors.UserError{
+			E:    errors.ErrDevContainerCrashLoop,
+			Hint: fmt.Sprintf("Container '%s' restarted %d times. Last termination reason: %s", container, sessionRestarts, reason),
+		}
+	}
+	return nil
+}
+
+//IsContainerRunning returns if a container of the pod has been started and is running
+func IsContainerRunning(pod *apiv1.Pod, container string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+func isOOMKilled(state apiv1.ContainerState) bool {
+	return state.Terminated != nil && state.Terminated.Reason == oomKilledReason
+}
+
 //GetDevPodUserID returns the user id running the dev pod
 func GetDevPodUserID(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) int64 {
 	devPodLogs, err := GetDevPodLogs(ctx, dev, false, c)
@@ -294,6 +520,14 @@ func parseUserID(output string) int64 {
 	return result
 }
 
+//GetContainerName returns the name of the development container in the dev pod
+func GetContainerName(pod *apiv1.Pod, dev *model.Dev) string {
+	if dev.Container == "" {
+		return pod.Spec.Containers[0].Name
+	}
+	return dev.GetDevContainerName()
+}
+
 //GetDevPodLogs returns the logs of the dev pod
 func GetDevPodLogs(ctx context.Context, dev *model.Dev, timestamps bool, c *kubernetes.Clientset) (string, error) {
 	p, err := GetDevPod(ctx, dev, c, false)
@@ -303,10 +537,7 @@ func GetDevPodLogs(ctx context.Context, dev *model.Dev, timestamps bool, c *kube
 	if p == nil {
 		return "", errors.ErrNotFound
 	}
-	if dev.Container == "" {
-		dev.Container = p.Spec.Containers[0].Name
-	}
-	return containerLogs(ctx, dev.Container, p, dev.Namespace, timestamps, c)
+	return containerLogs(ctx, GetContainerName(p, dev), p, dev.Namespace, timestamps, c)
 }
 
 func containerLogs(ctx context.Context, container string, pod *apiv1.Pod, namespace string, timestamps bool, c kubernetes.Interface) (string, error) {
@@ -331,6 +562,69 @@ func containerLogs(ctx context.Context, container string, pod *apiv1.Pod, namesp
 	return buf.String(), nil
 }
 
+//StreamLogs copies the logs of a container to w until the context is cancelled
+func StreamLogs(ctx context.Context, pod *apiv1.Pod, container string, w io.Writer, c kubernetes.Interface) error {
+	podLogOpts := apiv1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	}
+	req := c.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
+	logsStream, err := req.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer logsStream.Close()
+
+	_, err = io.Copy(w, logsStream)
+	return err
+}
+
+//TailLogs copies the logs of the containers to w, prefixing each line with the container name, until the context is cancelled.
+//If since is not nil, only the logs written after it are copied
+func TailLogs(ctx context.Context, pod *apiv1.Pod, containers []string, since *metav1.Time, w io.Writer, c kubernetes.Interface) error {
+	var mu sync.Mutex
+	errs := make(chan error, len(containers))
+	for _, container := range containers {
+		go func(container string) {
+			errs <- tailContainerLogs(ctx, pod, container, since, &mu, w, c)
+		}(container)
+	}
+
+	var result error
+	for range containers {
+		if err := <-errs; err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func tailContainerLogs(ctx context.Context, pod *apiv1.Pod, container string, since *metav1.Time, mu *sync.Mutex, w io.Writer, c kubernetes.Interface) error {
+	podLogOpts := apiv1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+		SinceTime: since,
+	}
+	req := c.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
+	logsStream, err := req.Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream the logs of container '%s': %s", container, err)
+	}
+	defer logsStream.Close()
+
+	scanner := bufio.NewScanner(logsStream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		mu.Lock()
+		_, err := fmt.Fprintf(w, "[%s] %s\n", container, scanner.Text())
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
 // Restart restarts the pods of a deployment
 func Restart(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, sn string) error {
 	pods, err := c.CoreV1().Pods(dev.Namespace).List(
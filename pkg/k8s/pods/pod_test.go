@@ -14,11 +14,20 @@
 package pods
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/okteto/okteto/pkg/errors"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -179,3 +188,410 @@ func Test_parseUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestMustBeRecreated(t *testing.T) {
+	running := &apiv1.Pod{
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{
+				{
+					Name:  "dev",
+					Image: "okteto/dev:1",
+					Resources: apiv1.ResourceRequirements{
+						Requests: apiv1.ResourceList{
+							apiv1.ResourceCPU:    resource.MustParse("100m"),
+							apiv1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+						Limits: apiv1.ResourceList{
+							apiv1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+			Volumes: []apiv1.Volume{
+				{Name: "okteto-bin"},
+				{Name: "default-token-abcde"},
+			},
+		},
+	}
+	var tests = []struct {
+		name     string
+		spec     apiv1.PodSpec
+		expected bool
+	}{
+		{
+			name: "unchanged",
+			spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{
+					{
+						Name:  "dev",
+						Image: "okteto/dev:1",
+						Resources: apiv1.ResourceRequirements{
+							Requests: apiv1.ResourceList{
+								apiv1.ResourceCPU: resource.MustParse("0.1"),
+							},
+						},
+					},
+				},
+				Volumes: []apiv1.Volume{{Name: "okteto-bin"}},
+			},
+			expected: false,
+		},
+		{
+			name: "image-changed",
+			spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{{Name: "dev", Image: "okteto/dev:2"}},
+			},
+			expected: true,
+		},
+		{
+			name: "resources-changed",
+			spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{
+					{
+						Name:  "dev",
+						Image: "okteto/dev:1",
+						Resources: apiv1.ResourceRequirements{
+							Limits: apiv1.ResourceList{
+								apiv1.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "volume-added",
+			spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{{Name: "dev", Image: "okteto/dev:1"}},
+				Volumes:    []apiv1.Volume{{Name: "okteto-bin"}, {Name: "okteto-overlay-0"}},
+			},
+			expected: true,
+		},
+		{
+			name: "other-container",
+			spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{{Name: "sidecar", Image: "busybox"}},
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MustBeRecreated(running, &tt.spec, "dev"); result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetOOMKilledError(t *testing.T) {
+	oomKilled := apiv1.ContainerState{
+		Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+	}
+	running := apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+	limits := apiv1.ResourceRequirements{
+		Limits: apiv1.ResourceList{apiv1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	var tests = []struct {
+		name         string
+		status       apiv1.ContainerStatus
+		resources    apiv1.ResourceRequirements
+		restarts     int32
+		expectErr    bool
+		expectedHint string
+	}{
+		{
+			name:      "running",
+			status:    apiv1.ContainerStatus{Name: "dev", State: running},
+			resources: limits,
+			expectErr: false,
+		},
+		{
+			name:         "last-state-oomkilled",
+			status:       apiv1.ContainerStatus{Name: "dev", State: running, LastTerminationState: oomKilled, RestartCount: 1},
+			resources:    limits,
+			expectErr:    true,
+			expectedHint: "Increase the value of 'resources.limits.memory' in your okteto manifest (current limit: 512Mi)",
+		},
+		{
+			name:      "last-state-oomkilled-before-session",
+			status:    apiv1.ContainerStatus{Name: "dev", State: running, LastTerminationState: oomKilled, RestartCount: 1},
+			resources: limits,
+			restarts:  1,
+			expectErr: false,
+		},
+		{
+			name:         "oomkilled-before-session-and-terminated",
+			status:       apiv1.ContainerStatus{Name: "dev", State: oomKilled, LastTerminationState: oomKilled, RestartCount: 1},
+			resources:    limits,
+			restarts:     1,
+			expectErr:    true,
+			expectedHint: "Increase the value of 'resources.limits.memory' in your okteto manifest (current limit: 512Mi)",
+		},
+		{
+			name:         "oomkilled-without-limits",
+			status:       apiv1.ContainerStatus{Name: "dev", State: oomKilled},
+			expectErr:    true,
+			expectedHint: "Set 'resources.limits.memory' in your okteto manifest to increase the memory available to your development container",
+		},
+		{
+			name:      "other-container-oomkilled",
+			status:    apiv1.ContainerStatus{Name: "sidecar", LastTerminationState: oomKilled},
+			resources: limits,
+			expectErr: false,
+		},
+		{
+			name: "error-terminated",
+			status: apiv1.ContainerStatus{
+				Name:                 "dev",
+				LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			},
+			resources: limits,
+			expectErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &apiv1.Pod{
+				Spec: apiv1.PodSpec{
+					Containers: []apiv1.Container{{Name: "dev", Resources: tt.resources}},
+				},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{tt.status},
+				},
+			}
+			err := GetOOMKilledError(pod, "dev", tt.restarts)
+			if !tt.expectErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			uErr, ok := err.(errors.UserError)
+			if !ok {
+				t.Fatalf("expected user error, got: %v", err)
+			}
+			if uErr.E != errors.ErrDevContainerOOMKilled {
+				t.Errorf("wrong error: %s", uErr.E)
+			}
+			if uErr.Hint != tt.expectedHint {
+				t.Errorf("wrong hint: %s", uErr.Hint)
+			}
+		})
+	}
+}
+
+func TestGetCrashLoopError(t *testing.T) {
+	pod := &apiv1.Pod{
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "sidecar", RestartCount: 10},
+				{Name: "dev"},
+			},
+		},
+	}
+	for restarts := int32(0); restarts <= 4; restarts++ {
+		pod.Status.ContainerStatuses[1].RestartCount = restarts
+		pod.Status.ContainerStatuses[1].State = apiv1.ContainerState{
+			Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}
+		pod.Status.ContainerStatuses[1].LastTerminationState = apiv1.ContainerState{
+			Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+		}
+		err := GetCrashLoopError(pod, "dev", 0, 3)
+		if restarts < 3 {
+			if err != nil {
+				t.Fatalf("restarts %d: unexpected error: %s", restarts, err)
+			}
+			continue
+		}
+		uErr, ok := err.(errors.UserError)
+		if !ok {
+			t.Fatalf("restarts %d: expected user error, got: %v", restarts, err)
+		}
+		if uErr.E != errors.ErrDevContainerCrashLoop {
+			t.Errorf("restarts %d: wrong error: %s", restarts, uErr.E)
+		}
+		expectedHint := fmt.Sprintf("Container 'dev' restarted %d times. Last termination reason: Error (exit code 1)", restarts)
+		if uErr.Hint != expectedHint {
+			t.Errorf("restarts %d: wrong hint: %s", restarts, uErr.Hint)
+		}
+	}
+
+	if err := GetCrashLoopError(pod, "dev", 2, 3); err != nil {
+		t.Errorf("unexpected error for restarts of a previous session: %s", err)
+	}
+
+	pod.Status.ContainerStatuses[1].State = apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+	if err := GetCrashLoopError(pod, "dev", 0, 3); err != nil {
+		t.Errorf("unexpected error for a running container: %s", err)
+	}
+}
+
+func TestGetCrashLoopThreshold(t *testing.T) {
+	defer os.Unsetenv(crashLoopThresholdEnvVar)
+	var tests = []struct {
+		value    string
+		expected int32
+	}{
+		{value: "", expected: defaultCrashLoopThreshold},
+		{value: "5", expected: 5},
+		{value: "0", expected: defaultCrashLoopThreshold},
+		{value: "many", expected: defaultCrashLoopThreshold},
+	}
+	for _, tt := range tests {
+		if tt.value == "" {
+			os.Unsetenv(crashLoopThresholdEnvVar)
+		} else {
+			os.Setenv(crashLoopThresholdEnvVar, tt.value)
+		}
+		if got := GetCrashLoopThreshold(); got != tt.expected {
+			t.Errorf("value '%s': expected %d, got %d", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestIsContainerRunning(t *testing.T) {
+	var tests = []struct {
+		name     string
+		statuses []apiv1.ContainerStatus
+		expected bool
+	}{
+		{
+			name:     "running",
+			statuses: []apiv1.ContainerStatus{{Name: "dev", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			expected: true,
+		},
+		{
+			name:     "waiting",
+			statuses: []apiv1.ContainerStatus{{Name: "dev", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{}}}},
+			expected: false,
+		},
+		{
+			name:     "other-container-running",
+			statuses: []apiv1.ContainerStatus{{Name: "sidecar", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			expected: false,
+		},
+		{
+			name:     "no-statuses",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: tt.statuses}}
+			if result := IsContainerRunning(pod, "dev"); result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-123", Namespace: "test"},
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{{Name: "dev"}, {Name: "proxy"}, {Name: "sidecar"}},
+		},
+	}
+	c := fake.NewSimpleClientset(pod)
+
+	var out bytes.Buffer
+	if err := TailLogs(context.Background(), pod, []string{"proxy", "sidecar"}, nil, &out, c); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	expected := []string{"[proxy] fake logs", "[sidecar] fake logs"}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong logs: expected %q, got %q", expected, lines)
+	}
+
+	out.Reset()
+	if err := TailLogs(context.Background(), pod, nil, nil, &out, c); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected logs: %q", out.String())
+	}
+}
+
+func TestDestroyWithGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset(&apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}})
+
+	if err := DestroyWithGracePeriod(ctx, "pod", "ns", 30, c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CoreV1().Pods("ns").Get(ctx, "pod", metav1.GetOptions{}); err == nil {
+		t.Errorf("pod was not destroyed")
+	}
+	if err := DestroyWithGracePeriod(ctx, "pod", "ns", 30, c); err != nil {
+		t.Errorf("destroying a missing pod failed: %s", err)
+	}
+}
+
+func TestWaitUntilReplaced(t *testing.T) {
+	replaceCheckInterval = 10 * time.Millisecond
+	selector := map[string]string{"app": "web"}
+	pod := func(name string, phase apiv1.PodPhase) *apiv1.Pod {
+		return &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: selector},
+			Status:     apiv1.PodStatus{Phase: phase},
+		}
+	}
+
+	var tests = []struct {
+		name      string
+		objects   []runtime.Object
+		remove    string
+		cancel    bool
+		expectErr bool
+	}{
+		{
+			name:    "no-old-pods",
+			objects: []runtime.Object{pod("web-dev", apiv1.PodRunning)},
+		},
+		{
+			name:    "completed-old-pod",
+			objects: []runtime.Object{pod("web-dev", apiv1.PodRunning), pod("web-old", apiv1.PodSucceeded)},
+		},
+		{
+			name:    "old-pod-removed",
+			objects: []runtime.Object{pod("web-dev", apiv1.PodRunning), pod("web-old", apiv1.PodRunning)},
+			remove:  "web-old",
+		},
+		{
+			name:      "cancelled",
+			objects:   []runtime.Object{pod("web-dev", apiv1.PodRunning), pod("web-old", apiv1.PodRunning)},
+			cancel:    true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := fake.NewSimpleClientset(tt.objects...)
+			if tt.remove != "" {
+				time.AfterFunc(50*time.Millisecond, func() {
+					c.CoreV1().Pods("ns").Delete(ctx, tt.remove, metav1.DeleteOptions{})
+				})
+			}
+			if tt.cancel {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+
+			err := WaitUntilReplaced(ctx, pod("web-dev", apiv1.PodRunning), selector, c)
+			if tt.expectErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/syncthing"
+)
+
+func Test_getConfigXMLSyncModes(t *testing.T) {
+	s := &syncthing.Syncthing{
+		Folders: []*syncthing.Folder{
+			{Name: "1", RemotePath: "/app", Mode: model.SyncModeSendReceive},
+			{Name: "2", RemotePath: "/config", Mode: model.SyncModeSendOnly},
+		},
+	}
+
+	config, err := getConfigXML(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`<folder id="okteto-1" label="1" path="/app" type="sendreceive"`,
+		`<folder id="okteto-2" label="2" path="/config" type="receiveonly"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(config), e) {
+			t.Errorf("'%s' not found in config.xml", e)
+		}
+	}
+}
+
+func Test_getConfigXMLPermissions(t *testing.T) {
+	var tests = []struct {
+		name          string
+		copyOwnership bool
+		expected      string
+	}{
+		{
+			name:          "root",
+			copyOwnership: true,
+			expected:      `ignorePerms="false" copyOwnershipFromParent="true"`,
+		},
+		{
+			name:          "non-root",
+			copyOwnership: false,
+			expected:      `ignorePerms="false" copyOwnershipFromParent="false"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &syncthing.Syncthing{
+				Folders:       []*syncthing.Folder{{Name: "1", RemotePath: "/app"}},
+				CopyOwnership: tt.copyOwnership,
+			}
+			config, err := getConfigXML(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(config), tt.expected) {
+				t.Errorf("'%s' not found in config.xml", tt.expected)
+			}
+		})
+	}
+}
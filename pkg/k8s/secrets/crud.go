@@ -95,8 +95,8 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, s *syn
 	return nil
 }
 
-//Destroy deletes the syncthing config secret
-func Destroy(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error {
+//Destroy deletes the syncthing config secret. It doesn't fail if the secret doesn't exist
+func Destroy(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
 	secretName := GetSecretName(dev)
 	err := c.CoreV1().Secrets(dev.Namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
 	if err != nil {
@@ -105,6 +105,7 @@ func Destroy(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error
 		}
 		return fmt.Errorf("error deleting kubernetes okteto secret: %s", err)
 	}
+	log.Infof("deleted okteto secret '%s'", secretName)
 	return nil
 }
 
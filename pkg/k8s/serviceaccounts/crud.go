@@ -0,0 +1,143 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceaccounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	serviceAccountLabel = "dev.okteto.com/service-account"
+)
+
+//CreateDev creates the service account of a development container and its role bindings
+func CreateDev(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	if dev.CreateServiceAccount == nil {
+		return nil
+	}
+
+	name := dev.GetServiceAccountName()
+	sa := &apiv1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dev.Namespace,
+			Labels:    translateLabels(name),
+		},
+	}
+	_, err := c.CoreV1().ServiceAccounts(dev.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("error getting kubernetes service account: %s", err)
+		}
+		if _, err := c.CoreV1().ServiceAccounts(dev.Namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating kubernetes service account: %s", err)
+		}
+		log.Infof("created service account '%s'", name)
+	}
+
+	if err := destroyRoleBindings(ctx, name, dev.Namespace, c); err != nil {
+		return err
+	}
+	for _, rb := range translateRoleBindings(dev) {
+		if _, err := c.RbacV1().RoleBindings(dev.Namespace).Create(ctx, rb, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating kubernetes role binding: %s", err)
+		}
+		log.Infof("created role binding '%s'", rb.Name)
+	}
+	return nil
+}
+
+//DestroyDev destroys the service account of a development container and its role bindings
+func DestroyDev(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	if dev.CreateServiceAccount == nil {
+		return nil
+	}
+
+	name := dev.GetServiceAccountName()
+	if err := destroyRoleBindings(ctx, name, dev.Namespace, c); err != nil {
+		return err
+	}
+
+	log.Infof("deleting service account '%s'", name)
+	err := c.CoreV1().ServiceAccounts(dev.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Infof("service account '%s' was already deleted.", name)
+			return nil
+		}
+		return fmt.Errorf("error deleting kubernetes service account: %s", err)
+	}
+	log.Infof("service account '%s' deleted", name)
+	return nil
+}
+
+func destroyRoleBindings(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
+	selector := fmt.Sprintf("%s=%s", serviceAccountLabel, name)
+	rbList, err := c.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("error listing kubernetes role bindings: %s", err)
+	}
+	for i := range rbList.Items {
+		err := c.RbacV1().RoleBindings(namespace).Delete(ctx, rbList.Items[i].Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error deleting kubernetes role binding: %s", err)
+		}
+	}
+	return nil
+}
+
+func translateRoleBindings(dev *model.Dev) []*rbacv1.RoleBinding {
+	name := dev.GetServiceAccountName()
+	result := []*rbacv1.RoleBinding{}
+	for _, rb := range dev.CreateServiceAccount.RoleBindings {
+		result = append(result, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s-%s", name, strings.ToLower(rb.Kind), rb.Name),
+				Namespace: dev.Namespace,
+				Labels:    translateLabels(name),
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      name,
+					Namespace: dev.Namespace,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rb.Kind,
+				Name:     rb.Name,
+			},
+		})
+	}
+	return result
+}
+
+func translateLabels(name string) map[string]string {
+	return map[string]string{
+		labels.DevLabel:     "true",
+		serviceAccountLabel: name,
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceaccounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{
+		Name:      "api",
+		Namespace: "test",
+		CreateServiceAccount: &model.CreateServiceAccount{
+			RoleBindings: []model.RoleBinding{
+				{Kind: model.ClusterRoleKind, Name: "view"},
+				{Kind: model.RoleKind, Name: "configmap-reader"},
+			},
+		},
+	}
+	other := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"},
+	}
+	c := fake.NewSimpleClientset(other)
+
+	if err := CreateDev(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateDev(ctx, dev, c); err != nil {
+		t.Fatalf("second create failed: %s", err)
+	}
+
+	if _, err := c.CoreV1().ServiceAccounts("test").Get(ctx, "okteto-api", metav1.GetOptions{}); err != nil {
+		t.Fatalf("service account not created: %s", err)
+	}
+
+	rb, err := c.RbacV1().RoleBindings("test").Get(ctx, "okteto-api-clusterrole-view", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("role binding not created: %s", err)
+	}
+	if rb.RoleRef.Kind != model.ClusterRoleKind || rb.RoleRef.Name != "view" {
+		t.Errorf("wrong role ref: %+v", rb.RoleRef)
+	}
+	if len(rb.Subjects) != 1 || rb.Subjects[0].Kind != rbacv1.ServiceAccountKind || rb.Subjects[0].Name != "okteto-api" || rb.Subjects[0].Namespace != "test" {
+		t.Errorf("wrong subjects: %+v", rb.Subjects)
+	}
+	if _, err := c.RbacV1().RoleBindings("test").Get(ctx, "okteto-api-role-configmap-reader", metav1.GetOptions{}); err != nil {
+		t.Fatalf("role binding not created: %s", err)
+	}
+
+	if err := DestroyDev(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CoreV1().ServiceAccounts("test").Get(ctx, "okteto-api", metav1.GetOptions{}); err == nil {
+		t.Errorf("service account not deleted")
+	}
+	rbList, err := c.RbacV1().RoleBindings("test").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rbList.Items) != 1 || rbList.Items[0].Name != "other" {
+		t.Errorf("wrong role bindings after destroy: %+v", rbList.Items)
+	}
+
+	if err := DestroyDev(ctx, dev, c); err != nil {
+		t.Fatalf("second destroy failed: %s", err)
+	}
+}
+
+func TestServiceAccountDisabled(t *testing.T) {
+	ctx := context.Background()
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	c := fake.NewSimpleClientset()
+	if err := CreateDev(ctx, dev, c); err != nil {
+		t.Fatal(err)
+	}
+	saList, err := c.CoreV1().ServiceAccounts("test").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saList.Items) != 0 {
+		t.Errorf("service account created without 'createServiceAccount'")
+	}
+}
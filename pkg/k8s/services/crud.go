@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
@@ -40,9 +41,10 @@ func List(ctx context.Context, namespace, labels string, c kubernetes.Interface)
 	return svcList.Items, nil
 }
 
-//CreateDev deploys a default k8s service for a development container
-func CreateDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error {
-	s := translate(dev)
+//CreateDev deploys a default k8s service for a development container.
+//interactive must be true when the service targets the interactive dev pod of okteto up
+func CreateDev(ctx context.Context, dev *model.Dev, interactive bool, c *kubernetes.Clientset) error {
+	s := translate(dev, interactive)
 	return Create(ctx, s, c)
 }
 
@@ -65,6 +67,7 @@ func Create(ctx context.Context, s *apiv1.Service, c *kubernetes.Clientset) erro
 	} else {
 		log.Infof("updating service '%s'", s.Name)
 		old.Spec.Ports = s.Spec.Ports
+		old.Spec.Selector = s.Spec.Selector
 		old.Annotations = s.Annotations
 		_, err = sClient.Update(ctx, old, metav1.UpdateOptions{})
 		if err != nil {
@@ -81,7 +84,7 @@ func DestroyDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) er
 }
 
 //Destroy destroys a k8s service
-func Destroy(ctx context.Context, name, namespace string, c *kubernetes.Clientset) error {
+func Destroy(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
 	log.Infof("deleting service '%s'", name)
 	err := c.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
@@ -95,6 +98,52 @@ func Destroy(ctx context.Context, name, namespace string, c *kubernetes.Clientse
 	return nil
 }
 
+//WaitForEndpoints waits until a service has at least one ready endpoint
+func WaitForEndpoints(ctx context.Context, namespace, name string, timeout time.Duration, c kubernetes.Interface) error {
+	ticker := time.NewTicker(300 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.Now().Add(timeout)
+
+	for i := 0; ; i++ {
+		e, err := c.CoreV1().Endpoints(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error getting endpoints of service '%s': %s", name, err)
+		}
+		if err == nil && hasReadyAddresses(e) {
+			log.Infof("service '%s' has ready endpoints", name)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.UserError{
+				E:    fmt.Errorf("service '%s' has no ready endpoints after %s", name, timeout.String()),
+				Hint: "Check that your development container is running and ready, or increase the value of 'waitForService'",
+			}
+		}
+
+		if i%10 == 5 {
+			log.Infof("waiting for service '%s' to have ready endpoints", name)
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Info("call to services.WaitForEndpoints cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+func hasReadyAddresses(e *apiv1.Endpoints) bool {
+	for _, s := range e.Subsets {
+		if len(s.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Get returns a kubernetes service by the name, or an error if it doesn't exist
 func Get(ctx context.Context, namespace, name string, c kubernetes.Interface) (*apiv1.Service, error) {
 	return c.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
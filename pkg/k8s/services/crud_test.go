@@ -16,6 +16,7 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/labels"
@@ -137,3 +138,64 @@ func TestGetNameBySelector(t *testing.T) {
 	}
 
 }
+
+func TestWaitForEndpoints(t *testing.T) {
+	ctx := context.Background()
+	endpoints := &apiv1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "test",
+		},
+		Subsets: []apiv1.EndpointSubset{
+			{
+				NotReadyAddresses: []apiv1.EndpointAddress{{IP: "10.0.0.1"}},
+			},
+		},
+	}
+	clientset := fake.NewSimpleClientset(endpoints)
+
+	if err := WaitForEndpoints(ctx, "test", "web", 500*time.Millisecond, clientset); err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		ready := endpoints.DeepCopy()
+		ready.Subsets[0].Addresses = ready.Subsets[0].NotReadyAddresses
+		ready.Subsets[0].NotReadyAddresses = nil
+		if _, err := clientset.CoreV1().Endpoints("test").Update(ctx, ready, metav1.UpdateOptions{}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if err := WaitForEndpoints(ctx, "test", "web", 5*time.Second, clientset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForEndpointsMissing(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		e := &apiv1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "test",
+			},
+			Subsets: []apiv1.EndpointSubset{
+				{
+					Addresses: []apiv1.EndpointAddress{{IP: "10.0.0.1"}},
+				},
+			},
+		}
+		if _, err := clientset.CoreV1().Endpoints("test").Create(ctx, e, metav1.CreateOptions{}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if err := WaitForEndpoints(ctx, "test", "web", 5*time.Second, clientset); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -25,7 +25,7 @@ const (
 	oktetoAutoIngressAnnotation = "dev.okteto.com/auto-ingress"
 )
 
-func translate(dev *model.Dev) *apiv1.Service {
+func translate(dev *model.Dev, interactive bool) *apiv1.Service {
 	annotations := map[string]string{}
 	if len(dev.Services) == 0 {
 		annotations[oktetoAutoIngressAnnotation] = "true"
@@ -43,7 +43,7 @@ func translate(dev *model.Dev) *apiv1.Service {
 			Annotations: annotations,
 		},
 		Spec: apiv1.ServiceSpec{
-			Selector: map[string]string{"app": dev.Name},
+			Selector: translateDevSelector(dev, interactive),
 			Type:     apiv1.ServiceTypeClusterIP,
 			Ports: []apiv1.ServicePort{
 				{
@@ -55,3 +55,13 @@ func translate(dev *model.Dev) *apiv1.Service {
 		},
 	}
 }
+
+//translateDevSelector selects the pods of the dev deployment.
+//For interactive dev containers, it selects only the interactive dev pod, even if other pods share the app label
+func translateDevSelector(dev *model.Dev, interactive bool) map[string]string {
+	selector := map[string]string{"app": dev.Name}
+	if interactive {
+		selector[labels.InteractiveDevLabel] = dev.Name
+	}
+	return selector
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"testing"
+
+	"github.com/okteto/okteto/pkg/k8s/labels"
+	"github.com/okteto/okteto/pkg/model"
+	k8sLabels "k8s.io/apimachinery/pkg/labels"
+)
+
+func TestTranslateDevSelector(t *testing.T) {
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	s := translate(dev, true)
+	selector := k8sLabels.SelectorFromSet(s.Spec.Selector)
+
+	var tests = []struct {
+		name      string
+		podLabels map[string]string
+		expected  bool
+	}{
+		{
+			name:      "dev-pod",
+			podLabels: map[string]string{"app": "api", labels.InteractiveDevLabel: "api", "webhook/injected": "true"},
+			expected:  true,
+		},
+		{
+			name:      "production-pod",
+			podLabels: map[string]string{"app": "api"},
+			expected:  false,
+		},
+		{
+			name:      "other-dev-pod",
+			podLabels: map[string]string{"app": "api", labels.InteractiveDevLabel: "worker"},
+			expected:  false,
+		},
+		{
+			name:      "detached-pod",
+			podLabels: map[string]string{"app": "api", labels.DetachedDevLabel: "api"},
+			expected:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := selector.Matches(k8sLabels.Set(tt.podLabels)); result != tt.expected {
+				t.Errorf("expected %t, got %t for labels %v", tt.expected, result, tt.podLabels)
+			}
+		})
+	}
+}
+
+func TestTranslateDevSelectorPush(t *testing.T) {
+	dev := &model.Dev{Name: "api", Namespace: "test"}
+	s := translate(dev, false)
+	selector := k8sLabels.SelectorFromSet(s.Spec.Selector)
+
+	podLabels := map[string]string{"app": "api"}
+	if !selector.Matches(k8sLabels.Set(podLabels)) {
+		t.Errorf("pushed pod not selected by %v", s.Spec.Selector)
+	}
+}
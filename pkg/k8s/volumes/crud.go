@@ -25,6 +25,7 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 
 	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -45,23 +46,188 @@ func List(ctx context.Context, namespace, labels string, c kubernetes.Interface)
 	return vList.Items, nil
 }
 
-//Create deploys the volume claim for a given development container
-func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error {
+const (
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	provisioningFailedReason      = "ProvisioningFailed"
+)
+
+var (
+	bindingCheckInterval = 1 * time.Second
+	releaseCheckInterval = 1 * time.Second
+)
+
+//Create deploys the volume claim for a given development container.
+//If maxSize is not empty, volume claims bigger than maxSize are rejected
+func Create(ctx context.Context, dev *model.Dev, maxSize string, c kubernetes.Interface) error {
+	if err := checkMaxVolumeSize(dev, maxSize); err != nil {
+		return err
+	}
 	vClient := c.CoreV1().PersistentVolumeClaims(dev.Namespace)
 	pvc := translate(dev)
 	k8Volume, err := vClient.Get(ctx, pvc.Name, metav1.GetOptions{})
 	if err != nil && !strings.Contains(err.Error(), "not found") {
 		return fmt.Errorf("error getting kubernetes volume claim: %s", err)
 	}
-	if k8Volume.Name != "" {
-		return checkPVCValues(k8Volume, dev)
+	if k8Volume != nil && k8Volume.Name != "" {
+		if err := checkPVCValues(k8Volume, dev); err != nil {
+			return err
+		}
+		return checkPVCBinding(ctx, k8Volume, dev, c)
 	}
 	log.Infof("creating volume claim '%s'", pvc.Name)
-	_, err = vClient.Create(ctx, pvc, metav1.CreateOptions{})
+	k8Volume, err = vClient.Create(ctx, pvc, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("error creating kubernetes volume claim: %s", err)
 	}
-	return nil
+	return checkPVCBinding(ctx, k8Volume, dev, c)
+}
+
+//CreateSharedCache deploys the cache volume claim shared by the development containers of the namespace.
+//The volume claim is created once and reused by the rest of development containers
+func CreateSharedCache(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
+	vClient := c.CoreV1().PersistentVolumeClaims(dev.Namespace)
+	pvc := translateSharedCache(dev)
+	k8Volume, err := vClient.Get(ctx, pvc.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("error getting kubernetes volume claim: %s", err)
+	}
+	if err == nil {
+		return checkSharedCacheAccessMode(k8Volume)
+	}
+
+	log.Infof("creating shared cache volume claim '%s'", pvc.Name)
+	k8Volume, err = vClient.Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		log.Infof("shared cache volume claim '%s' was created by another development container", pvc.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes volume claim: %s", err)
+	}
+	return checkPVCBinding(ctx, k8Volume, dev, c)
+}
+
+func checkSharedCacheAccessMode(pvc *apiv1.PersistentVolumeClaim) error {
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == apiv1.ReadWriteMany {
+			return nil
+		}
+	}
+	return errors.UserError{
+		E:    fmt.Errorf("volume claim '%s' already exists and it is not 'ReadWriteMany'", pvc.Name),
+		Hint: fmt.Sprintf("Delete the volume claim '%s' to let okteto create the shared cache", pvc.Name),
+	}
+}
+
+//checkPVCBinding waits for the volume claim to be bound when its storage class binds volumes immediately.
+//Unbound volume claims are expected for 'WaitForFirstConsumer' storage classes until the pod is scheduled
+func checkPVCBinding(ctx context.Context, pvc *apiv1.PersistentVolumeClaim, dev *model.Dev, c kubernetes.Interface) error {
+	if pvc.Status.Phase == apiv1.ClaimBound {
+		return nil
+	}
+
+	sc, err := getStorageClass(ctx, pvc, c)
+	if err != nil {
+		log.Infof("failed to get the storage class of volume claim '%s': %s", pvc.Name, err)
+		return nil
+	}
+	if sc == nil {
+		return nil
+	}
+	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode != storagev1.VolumeBindingImmediate {
+		log.Infof("volume claim '%s' will be bound when the development container is scheduled", pvc.Name)
+		return nil
+	}
+
+	to := dev.PersistentVolumeBindingTimeout()
+	if to == 0 {
+		to = config.GetTimeout()
+	}
+	timeout := time.Now().Add(to)
+	ticker := time.NewTicker(bindingCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := c.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting kubernetes volume claim: %s", err)
+		}
+		if current.Status.Phase == apiv1.ClaimBound {
+			return nil
+		}
+
+		if msg := getProvisioningError(ctx, current, c); msg != "" {
+			return errors.UserError{
+				E:    fmt.Errorf("failed to provision the volume claim '%s': %s", pvc.Name, msg),
+				Hint: fmt.Sprintf("Check the provisioner of the storage class '%s' or run 'okteto down -v' and try again", sc.Name),
+			}
+		}
+
+		if time.Now().After(timeout) {
+			return errors.UserError{
+				E:    fmt.Errorf("volume claim '%s' wasn't bound after %s", pvc.Name, to.String()),
+				Hint: fmt.Sprintf("Check the provisioner of the storage class '%s' or increase 'persistentVolume.bindingTimeout'", sc.Name),
+			}
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Info("call to volumes.checkPVCBinding cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+//getStorageClass returns the storage class of a volume claim, or the default storage class if the volume claim doesn't set one
+func getStorageClass(ctx context.Context, pvc *apiv1.PersistentVolumeClaim, c kubernetes.Interface) (*storagev1.StorageClass, error) {
+	if pvc.Spec.StorageClassName != nil {
+		if *pvc.Spec.StorageClassName == "" {
+			return nil, nil
+		}
+		return c.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	}
+
+	scList, err := c.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range scList.Items {
+		if scList.Items[i].Annotations[defaultStorageClassAnnotation] == "true" {
+			return &scList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+//getProvisioningError returns the message of the last provisioning failure of a volume claim
+func getProvisioningError(ctx context.Context, pvc *apiv1.PersistentVolumeClaim, c kubernetes.Interface) string {
+	events, err := c.CoreV1().Events(pvc.Namespace).List(
+		ctx,
+		metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.kind=PersistentVolumeClaim,involvedObject.name=%s", pvc.Name),
+		},
+	)
+	if err != nil {
+		log.Infof("failed to get events of volume claim '%s': %s", pvc.Name, err)
+		return ""
+	}
+
+	var last *apiv1.Event
+	for i := range events.Items {
+		e := &events.Items[i]
+		if e.InvolvedObject.Name != pvc.Name || e.Reason != provisioningFailedReason {
+			continue
+		}
+		if last == nil || last.LastTimestamp.Before(&e.LastTimestamp) {
+			last = e
+		}
+	}
+	if last == nil {
+		return ""
+	}
+	return last.Message
 }
 
 func checkPVCValues(pvc *apiv1.PersistentVolumeClaim, dev *model.Dev) error {
@@ -96,13 +262,32 @@ func checkPVCValues(pvc *apiv1.PersistentVolumeClaim, dev *model.Dev) error {
 
 }
 
+//checkMaxVolumeSize rejects okteto volumes bigger than the maximum volume size of the namespace
+func checkMaxVolumeSize(dev *model.Dev, maxSize string) error {
+	if maxSize == "" {
+		return nil
+	}
+	maxQuantity, err := resource.ParseQuantity(maxSize)
+	if err != nil {
+		return fmt.Errorf("the maximum volume size '%s' of namespace '%s' is not a valid quantity: %s", maxSize, dev.Namespace, err)
+	}
+	size := resource.MustParse(dev.PersistentVolumeSize())
+	if size.Cmp(maxQuantity) > 0 {
+		return errors.UserError{
+			E:    fmt.Errorf("okteto volume size '%s' exceeds the maximum volume size '%s' of namespace '%s'", dev.PersistentVolumeSize(), maxSize, dev.Namespace),
+			Hint: "Reduce the value of 'persistentVolume.size' in your okteto manifest",
+		}
+	}
+	return nil
+}
+
 //DestroyDev destroys the persistent volume claim for a given development container
 func DestroyDev(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset) error {
 	return Destroy(ctx, dev.GetVolumeName(), dev.Namespace, c)
 }
 
 //Destroy destroys a persistent volume claim
-func Destroy(ctx context.Context, name, namespace string, c *kubernetes.Clientset) error {
+func Destroy(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
 	vClient := c.CoreV1().PersistentVolumeClaims(namespace)
 	log.Infof("destroying volume '%s'", name)
 
@@ -144,23 +329,113 @@ func Destroy(ctx context.Context, name, namespace string, c *kubernetes.Clientse
 
 }
 
-func checkIfAttached(ctx context.Context, name, namespace string, c *kubernetes.Clientset) error {
-	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+func checkIfAttached(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
+	pods, err := getAttachedPods(ctx, name, namespace, c)
 	if err != nil {
 		log.Infof("failed to get available pods: %s", err)
 		return nil
 	}
 
+	if len(pods) > 0 {
+		log.Infof("pvc/%s is still attached to pod/%s", name, pods[0].Name)
+		return fmt.Errorf("can't delete the volume '%s' since it's still attached to 'pod/%s'", name, pods[0].Name)
+	}
+
+	return nil
+}
+
+//WaitUntilReleased waits until the terminating pods attached to the ReadWriteOnce volume claim of a development container, other than podName, release it
+func WaitUntilReleased(ctx context.Context, dev *model.Dev, podName string, c kubernetes.Interface, progress func(holder string)) error {
+	name := dev.GetVolumeName()
+	pvc, err := c.CoreV1().PersistentVolumeClaims(dev.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting kubernetes volume claim: %s", err)
+	}
+	if !isReadWriteOnce(pvc) {
+		return nil
+	}
+
+	to := config.GetTimeout()
+	timeout := time.Now().Add(to)
+	ticker := time.NewTicker(releaseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		holder, err := getTerminatingHolder(ctx, name, dev.Namespace, podName, c)
+		if err != nil {
+			log.Infof("failed to get the pods attached to volume claim '%s': %s", name, err)
+			return nil
+		}
+		if holder == "" {
+			return nil
+		}
+
+		log.Infof("pvc/%s is still attached to the terminating pod/%s", name, holder)
+		progress(holder)
+
+		if time.Now().After(timeout) {
+			return errors.UserError{
+				E:    fmt.Errorf("persistent volume '%s' wasn't released by 'pod/%s' after %s", name, holder, to.String()),
+				Hint: fmt.Sprintf("Wait for 'pod/%s' to terminate or delete it with 'kubectl delete pod %s --force' and try again", holder, holder),
+			}
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			log.Info("call to volumes.WaitUntilReleased cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+func isReadWriteOnce(pvc *apiv1.PersistentVolumeClaim) bool {
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == apiv1.ReadWriteOnce {
+			return true
+		}
+	}
+	return false
+}
+
+//getTerminatingHolder returns the name of a terminating pod attached to the volume claim, other than podName
+func getTerminatingHolder(ctx context.Context, name, namespace, podName string, c kubernetes.Interface) (string, error) {
+	pods, err := getAttachedPods(ctx, name, namespace, c)
+	if err != nil {
+		return "", err
+	}
+	for i := range pods {
+		if pods[i].Name == podName || pods[i].DeletionTimestamp == nil {
+			continue
+		}
+		if pods[i].Status.Phase == apiv1.PodSucceeded || pods[i].Status.Phase == apiv1.PodFailed {
+			continue
+		}
+		return pods[i].Name, nil
+	}
+	return "", nil
+}
+
+func getAttachedPods(ctx context.Context, name, namespace string, c kubernetes.Interface) ([]apiv1.Pod, error) {
+	pods, err := c.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []apiv1.Pod{}
 	for i := range pods.Items {
 		for j := range pods.Items[i].Spec.Volumes {
 			if pods.Items[i].Spec.Volumes[j].PersistentVolumeClaim != nil {
 				if pods.Items[i].Spec.Volumes[j].PersistentVolumeClaim.ClaimName == name {
-					log.Infof("pvc/%s is still attached to pod/%s", name, pods.Items[i].Name)
-					return fmt.Errorf("can't delete the volume '%s' since it's still attached to 'pod/%s'", name, pods.Items[i].Name)
+					result = append(result, pods.Items[i])
+					break
 				}
 			}
 		}
 	}
-
-	return nil
+	return result, nil
 }
@@ -14,11 +14,19 @@
 package volumes
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/model"
 	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func Test_checkPVCValues(t *testing.T) {
@@ -140,3 +148,356 @@ func Test_checkPVCValues(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkMaxVolumeSize(t *testing.T) {
+	var tests = []struct {
+		name      string
+		size      string
+		maxSize   string
+		wantError bool
+	}{
+		{
+			name:      "no-limit",
+			size:      "100Gi",
+			maxSize:   "",
+			wantError: false,
+		},
+		{
+			name:      "default-size-below-limit",
+			size:      "",
+			maxSize:   "20Gi",
+			wantError: false,
+		},
+		{
+			name:      "equal-to-limit",
+			size:      "20Gi",
+			maxSize:   "20Gi",
+			wantError: false,
+		},
+		{
+			name:      "exceeds-limit",
+			size:      "30Gi",
+			maxSize:   "20Gi",
+			wantError: true,
+		},
+		{
+			name:      "invalid-limit",
+			size:      "10Gi",
+			maxSize:   "twenty",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := &model.Dev{
+				Namespace:            "n",
+				PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: true, Size: tt.size},
+			}
+			err := checkMaxVolumeSize(dev, tt.maxSize)
+			if err == nil && tt.wantError {
+				t.Errorf("checkMaxVolumeSize in test '%s' did not fail", tt.name)
+			}
+			if err != nil && !tt.wantError {
+				t.Errorf("checkMaxVolumeSize in test '%s' failed: %s", tt.name, err)
+			}
+		})
+	}
+}
+
+func Test_checkPVCBinding(t *testing.T) {
+	bindingCheckInterval = 10 * time.Millisecond
+	immediate := storagev1.VolumeBindingImmediate
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	className := "class"
+	emptyClassName := ""
+
+	newPVC := func(storageClass *string, phase apiv1.PersistentVolumeClaimPhase) *apiv1.PersistentVolumeClaim {
+		return &apiv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "okteto-web", Namespace: "n"},
+			Spec:       apiv1.PersistentVolumeClaimSpec{StorageClassName: storageClass},
+			Status:     apiv1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	newClass := func(mode *storagev1.VolumeBindingMode, isDefault bool) *storagev1.StorageClass {
+		sc := &storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: className},
+			VolumeBindingMode: mode,
+		}
+		if isDefault {
+			sc.Annotations = map[string]string{defaultStorageClassAnnotation: "true"}
+		}
+		return sc
+	}
+	provisioningFailed := &apiv1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "okteto-web.1", Namespace: "n"},
+		InvolvedObject: apiv1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "okteto-web"},
+		Reason:         provisioningFailedReason,
+		Message:        "no capacity",
+	}
+
+	var tests = []struct {
+		name      string
+		pvc       *apiv1.PersistentVolumeClaim
+		objects   []runtime.Object
+		wantError bool
+	}{
+		{
+			name:      "bound",
+			pvc:       newPVC(&className, apiv1.ClaimBound),
+			objects:   []runtime.Object{newClass(&immediate, false)},
+			wantError: false,
+		},
+		{
+			name:      "wait-for-first-consumer-pending",
+			pvc:       newPVC(&className, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(&waitForFirstConsumer, false), provisioningFailed},
+			wantError: false,
+		},
+		{
+			name:      "immediate-pending",
+			pvc:       newPVC(&className, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(&immediate, false)},
+			wantError: true,
+		},
+		{
+			name:      "immediate-provisioning-failed",
+			pvc:       newPVC(&className, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(&immediate, false), provisioningFailed},
+			wantError: true,
+		},
+		{
+			name:      "default-class-immediate-provisioning-failed",
+			pvc:       newPVC(nil, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(nil, true), provisioningFailed},
+			wantError: true,
+		},
+		{
+			name:      "default-class-wait-for-first-consumer",
+			pvc:       newPVC(nil, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(&waitForFirstConsumer, true)},
+			wantError: false,
+		},
+		{
+			name:      "no-dynamic-provisioning",
+			pvc:       newPVC(&emptyClassName, apiv1.ClaimPending),
+			objects:   []runtime.Object{newClass(&immediate, true)},
+			wantError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset(append(tt.objects, tt.pvc)...)
+			dev := &model.Dev{
+				PersistentVolumeInfo: &model.PersistentVolumeInfo{
+					Enabled:        true,
+					BindingTimeout: 1,
+				},
+			}
+			err := checkPVCBinding(context.Background(), tt.pvc, dev, c)
+			if err == nil && tt.wantError {
+				t.Errorf("checkPVCBinding in test '%s' did not fail", tt.name)
+			}
+			if err != nil && !tt.wantError {
+				t.Errorf("checkPVCBinding in test '%s' failed: %s", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestCreateWaitForFirstConsumer(t *testing.T) {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	c := fake.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: "class"},
+		VolumeBindingMode: &waitForFirstConsumer,
+	})
+	dev := &model.Dev{
+		Name:      "web",
+		Namespace: "n",
+		PersistentVolumeInfo: &model.PersistentVolumeInfo{
+			Enabled:      true,
+			StorageClass: "class",
+		},
+	}
+
+	if err := Create(context.Background(), dev, "", c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CoreV1().PersistentVolumeClaims("n").Get(context.Background(), dev.GetVolumeName(), metav1.GetOptions{}); err != nil {
+		t.Fatalf("volume claim not created: %s", err)
+	}
+}
+
+func TestCreateExceedsMaxVolumeSize(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	dev := &model.Dev{
+		Name:      "web",
+		Namespace: "n",
+		PersistentVolumeInfo: &model.PersistentVolumeInfo{
+			Enabled: true,
+			Size:    "50Gi",
+		},
+	}
+
+	if err := Create(context.Background(), dev, "20Gi", c); err == nil {
+		t.Fatal("volume claim bigger than the maximum volume size was not rejected")
+	}
+	if _, err := c.CoreV1().PersistentVolumeClaims("n").Get(context.Background(), dev.GetVolumeName(), metav1.GetOptions{}); err == nil {
+		t.Fatal("volume claim bigger than the maximum volume size was created")
+	}
+}
+
+func TestCreateSharedCache(t *testing.T) {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	c := fake.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: "nfs"},
+		VolumeBindingMode: &waitForFirstConsumer,
+	})
+	sharedCache := &model.SharedCache{Paths: []string{"/root/go/pkg/mod"}, StorageClass: "nfs"}
+	web := &model.Dev{Name: "web", Namespace: "n", SharedCache: sharedCache}
+	api := &model.Dev{Name: "api", Namespace: "n", SharedCache: sharedCache}
+
+	for _, dev := range []*model.Dev{web, api, web} {
+		if err := CreateSharedCache(context.Background(), dev, c); err != nil {
+			t.Fatalf("dev '%s': %s", dev.Name, err)
+		}
+	}
+
+	pvcs, err := c.CoreV1().PersistentVolumeClaims("n").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pvcs.Items) != 1 {
+		t.Fatalf("expected 1 volume claim, got %d", len(pvcs.Items))
+	}
+	pvc := pvcs.Items[0]
+	if pvc.Name != model.OktetoSharedCacheVolumeName {
+		t.Errorf("wrong volume claim name: %s", pvc.Name)
+	}
+	if len(pvc.Spec.AccessModes) != 1 || pvc.Spec.AccessModes[0] != apiv1.ReadWriteMany {
+		t.Errorf("wrong access modes: %v", pvc.Spec.AccessModes)
+	}
+	if size := pvc.Spec.Resources.Requests["storage"]; size.String() != model.OktetoDefaultSharedCacheSize {
+		t.Errorf("wrong size: %s", size.String())
+	}
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != "nfs" {
+		t.Errorf("wrong storage class: %v", pvc.Spec.StorageClassName)
+	}
+}
+
+func TestCreateSharedCacheAlreadyCreated(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	c.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewAlreadyExists(apiv1.Resource("persistentvolumeclaims"), model.OktetoSharedCacheVolumeName)
+	})
+	dev := &model.Dev{Name: "web", Namespace: "n", SharedCache: &model.SharedCache{Paths: []string{"/root/.npm"}}}
+	if err := CreateSharedCache(context.Background(), dev, c); err != nil {
+		t.Fatalf("concurrent creation of the shared cache failed: %s", err)
+	}
+}
+
+func TestCreateSharedCacheNotReadWriteMany(t *testing.T) {
+	c := fake.NewSimpleClientset(&apiv1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: model.OktetoSharedCacheVolumeName, Namespace: "n"},
+		Spec: apiv1.PersistentVolumeClaimSpec{
+			AccessModes: []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce},
+		},
+	})
+	dev := &model.Dev{Name: "web", Namespace: "n", SharedCache: &model.SharedCache{Paths: []string{"/root/.npm"}}}
+	if err := CreateSharedCache(context.Background(), dev, c); err == nil {
+		t.Fatal("expected error for a shared cache that is not 'ReadWriteMany'")
+	}
+}
+
+func TestWaitUntilReleased(t *testing.T) {
+	releaseCheckInterval = 10 * time.Millisecond
+	now := metav1.Now()
+	dev := &model.Dev{Name: "web", Namespace: "n"}
+	claim := func(mode apiv1.PersistentVolumeAccessMode) *apiv1.PersistentVolumeClaim {
+		return &apiv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: dev.GetVolumeName(), Namespace: "n"},
+			Spec:       apiv1.PersistentVolumeClaimSpec{AccessModes: []apiv1.PersistentVolumeAccessMode{mode}},
+		}
+	}
+	pod := func(name string, deletionTimestamp *metav1.Time) *apiv1.Pod {
+		return &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "n", DeletionTimestamp: deletionTimestamp},
+			Spec: apiv1.PodSpec{
+				Volumes: []apiv1.Volume{
+					{
+						Name: "data",
+						VolumeSource: apiv1.VolumeSource{
+							PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: dev.GetVolumeName()},
+						},
+					},
+				},
+			},
+			Status: apiv1.PodStatus{Phase: apiv1.PodRunning},
+		}
+	}
+
+	var tests = []struct {
+		name           string
+		objects        []runtime.Object
+		release        bool
+		expectedHolder string
+	}{
+		{
+			name:    "no-volume-claim",
+			objects: []runtime.Object{pod("web-old", &now)},
+		},
+		{
+			name:    "read-write-many",
+			objects: []runtime.Object{claim(apiv1.ReadWriteMany), pod("web-old", &now), pod("web-new", nil)},
+		},
+		{
+			name:    "not-terminating",
+			objects: []runtime.Object{claim(apiv1.ReadWriteOnce), pod("web-old", nil), pod("web-new", nil)},
+		},
+		{
+			name:    "terminating-dev-pod",
+			objects: []runtime.Object{claim(apiv1.ReadWriteOnce), pod("web-new", &now)},
+		},
+		{
+			name:           "held-then-released",
+			objects:        []runtime.Object{claim(apiv1.ReadWriteOnce), pod("web-old", &now), pod("web-new", nil)},
+			release:        true,
+			expectedHolder: "web-old",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fake.NewSimpleClientset(tt.objects...)
+			holders := []string{}
+			progress := func(holder string) {
+				holders = append(holders, holder)
+				if tt.release && len(holders) == 3 {
+					if err := c.CoreV1().Pods("n").Delete(ctx, holder, metav1.DeleteOptions{}); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			if err := WaitUntilReleased(ctx, dev, "web-new", c, progress); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.expectedHolder == "" {
+				if len(holders) > 0 {
+					t.Errorf("unexpected wait for %v", holders)
+				}
+				return
+			}
+			if len(holders) != 3 {
+				t.Errorf("expected 3 checks before the release, got %d", len(holders))
+			}
+			for _, h := range holders {
+				if h != tt.expectedHolder {
+					t.Errorf("wrong holder: %s", h)
+				}
+			}
+		})
+	}
+}
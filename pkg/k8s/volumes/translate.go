@@ -44,3 +44,27 @@ func translate(dev *model.Dev) *apiv1.PersistentVolumeClaim {
 	}
 	return pvc
 }
+
+func translateSharedCache(dev *model.Dev) *apiv1.PersistentVolumeClaim {
+	pvc := &apiv1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: model.OktetoSharedCacheVolumeName,
+			Labels: map[string]string{
+				labels.SharedCacheLabel: "true",
+			},
+		},
+		Spec: apiv1.PersistentVolumeClaimSpec{
+			AccessModes: []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteMany},
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					"storage": resource.MustParse(dev.SharedCacheSize()),
+				},
+			},
+		},
+	}
+	if dev.SharedCache.StorageClass != "" {
+		storageClass := dev.SharedCache.StorageClass
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	return pvc
+}
@@ -45,8 +45,9 @@ var (
 )
 
 type logger struct {
-	out  *logrus.Logger
-	file *logrus.Entry
+	out   *logrus.Logger
+	file  *logrus.Entry
+	quiet bool
 }
 
 var log = &logger{
@@ -99,6 +100,16 @@ func SetLevel(level string) {
 	}
 }
 
+// SetQuiet restricts the terminal output to phase transitions, warnings and errors
+func SetQuiet(quiet bool) {
+	log.quiet = quiet
+}
+
+// IsQuiet returns true if the terminal output is restricted to phase transitions, warnings and errors
+func IsQuiet() bool {
+	return log.quiet
+}
+
 // Debug writes a debug-level log
 func Debug(args ...interface{}) {
 	log.out.Debug(args...)
@@ -182,6 +193,9 @@ func Success(format string, args ...interface{}) {
 // Information prints a message with the information symbol first, and the text in blue
 func Information(format string, args ...interface{}) {
 	log.out.Infof(format, args...)
+	if log.quiet {
+		return
+	}
 	fmt.Fprintf(color.Output, "%s %s\n", informationSymbol, blueString(format, args...))
 }
 
@@ -194,6 +208,9 @@ func Warning(format string, args ...interface{}) {
 // Hint prints a message with the text in blue
 func Hint(format string, args ...interface{}) {
 	log.out.Infof(format, args...)
+	if log.quiet {
+		return
+	}
 	fmt.Fprintf(color.Output, "%s\n", blueString(format, args...))
 }
 
@@ -0,0 +1,111 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	//ActivatePhase is the phase that creates the development container
+	ActivatePhase = "activate"
+
+	//StartPhase is the phase that waits for the development container to be running
+	StartPhase = "start"
+
+	//SyncPhase is the phase that runs the initial file synchronization
+	SyncPhase = "sync"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	reconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "okteto_up_reconnects_total",
+		Help: "Number of times okteto up reconnected to the development container.",
+	})
+
+	syncedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "okteto_up_synced_bytes_total",
+		Help: "Number of bytes transferred by the initial file synchronization.",
+	})
+
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "okteto_up_phase_duration_seconds",
+		Help:    "Duration of the okteto up phases.",
+		Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"phase"})
+)
+
+func init() {
+	registry.MustRegister(reconnects, syncedBytes, phaseDuration)
+}
+
+//TrackReconnect increments the reconnect counter
+func TrackReconnect() {
+	reconnects.Inc()
+}
+
+//TrackSyncedBytes adds the bytes synchronized to the synced bytes counter
+func TrackSyncedBytes(bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	syncedBytes.Add(float64(bytes))
+}
+
+//TrackPhase records the duration of an okteto up phase
+func TrackPhase(phase string, start time.Time) {
+	phaseDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+}
+
+//Handler returns the http handler that exposes the metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+//Serve exposes the metrics on the given address until ctx is done
+func Serve(ctx context.Context, address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	s := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		if err := s.Close(); err != nil {
+			log.Infof("failed to stop the metrics server: %s", err)
+		}
+	}()
+
+	go func() {
+		log.Infof("serving metrics on http://%s/metrics", l.Addr().String())
+		if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Infof("metrics server failed: %s", err)
+		}
+	}()
+
+	return nil
+}
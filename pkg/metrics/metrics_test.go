@@ -0,0 +1,89 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestMetrics(t *testing.T) {
+	s := httptest.NewServer(Handler())
+	defer s.Close()
+
+	TrackReconnect()
+	TrackSyncedBytes(100)
+	TrackPhase(SyncPhase, time.Now())
+
+	body := scrape(t, s.URL)
+	for _, expected := range []string{
+		"okteto_up_reconnects_total 1",
+		"okteto_up_synced_bytes_total 100",
+		`okteto_up_phase_duration_seconds_count{phase="sync"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in:\n%s", expected, body)
+		}
+	}
+
+	TrackReconnect()
+	TrackSyncedBytes(50)
+	TrackSyncedBytes(-1)
+	TrackPhase(SyncPhase, time.Now())
+	TrackPhase(ActivatePhase, time.Now())
+
+	body = scrape(t, s.URL)
+	for _, expected := range []string{
+		"okteto_up_reconnects_total 2",
+		"okteto_up_synced_bytes_total 150",
+		`okteto_up_phase_duration_seconds_count{phase="sync"} 2`,
+		`okteto_up_phase_duration_seconds_count{phase="activate"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in:\n%s", expected, body)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := Serve(ctx, "invalid-address"); err == nil {
+		t.Fatal("expected error for an invalid address")
+	}
+
+	if err := Serve(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+}
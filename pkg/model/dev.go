@@ -17,7 +17,10 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -35,6 +38,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -42,8 +46,14 @@ const (
 	Localhost                   = "localhost"
 	oktetoSSHServerPortVariable = "OKTETO_REMOTE_PORT"
 	oktetoDefaultSSHServerPort  = 2222
+	//DefaultSSHKeepAliveInterval default seconds between keepalive requests on the SSH connection
+	DefaultSSHKeepAliveInterval = 30
 	//OktetoDefaultPVSize default volume size
 	OktetoDefaultPVSize = "2Gi"
+	//OktetoDefaultSharedCacheSize default shared cache volume size
+	OktetoDefaultSharedCacheSize = "10Gi"
+	//OktetoDefaultInotifyMaxUserWatches default value of fs.inotify.max_user_watches set by 'inotifyTuning'
+	OktetoDefaultInotifyMaxUserWatches = 524288
 	//OktetoUpCmd up command
 	OktetoUpCmd = "up"
 	//OktetoPushCmd push command
@@ -53,6 +63,10 @@ const (
 	DeprecatedOktetoVolumeName = "okteto"
 	//OktetoVolumeNameTemplate name template of the development container persistent volume
 	OktetoVolumeNameTemplate = "okteto-%s"
+	//OktetoSharedCacheVolumeName name of the cache volume shared by the development containers of a namespace
+	OktetoSharedCacheVolumeName = "okteto-shared-cache"
+	//SharedCacheSubPath subpath in the shared cache volume for the cache folders
+	SharedCacheSubPath = "cache"
 	//DataSubPath subpath in the development container persistent volume for the data volumes
 	DataSubPath = "data"
 	//SourceCodeSubPath subpath in the development container persistent volume for the source code
@@ -89,6 +103,54 @@ const (
 	// this path is expected by remote
 	authorizedKeysPath = "/var/okteto/remote/authorized_keys"
 
+	//OktetoBinMountPath default path where the okteto binaries are mounted in the development container
+	OktetoBinMountPath = "/var/okteto/bin"
+
+	//OktetoShellName name of the static shell copied to the okteto binaries when 'initContainer.injectShell' is set
+	OktetoShellName = "sh"
+
+	//OktetoInitBinMountPath default path where the okteto init container copies the okteto binaries
+	OktetoInitBinMountPath = "/okteto/bin"
+
+	//DeployStrategyReplace replaces the deployment when activating a development container
+	DeployStrategyReplace = "replace"
+
+	//DeployStrategyPatch patches only the changes made by okteto when activating a development container
+	DeployStrategyPatch = "patch"
+
+	//LivenessFailureActionRestart lets kubernetes restart the development container when its liveness probe fails
+	LivenessFailureActionRestart = "restart"
+
+	//LivenessFailureActionIgnore removes the liveness probe of the development container
+	LivenessFailureActionIgnore = "ignore"
+
+	//PullAuto picks the image pull policy of the development container from its image reference
+	PullAuto apiv1.PullPolicy = "Auto"
+
+	//OktetoLogTailContainer is the name of the sidecar that tails the log fifo of the development container
+	OktetoLogTailContainer = "okteto-log-tail"
+
+	//OktetoOpenTelemetryContainer is the name of the OpenTelemetry collector sidecar of the development container
+	OktetoOpenTelemetryContainer = "okteto-otel-collector"
+
+	//DefaultOpenTelemetryImage is the default image of the OpenTelemetry collector sidecar
+	DefaultOpenTelemetryImage = "otel/opentelemetry-collector:0.19.0"
+
+	//OpenTelemetryPort is the OTLP port the OpenTelemetry collector sidecar listens on with its default config
+	OpenTelemetryPort = 4317
+
+	//RoleKind binds a namespaced role to the development container service account
+	RoleKind = "Role"
+
+	//ClusterRoleKind binds a cluster role to the development container service account
+	ClusterRoleKind = "ClusterRole"
+
+	//SyncModeSendReceive syncs a folder in both directions
+	SyncModeSendReceive = "sendreceive"
+
+	//SyncModeSendOnly syncs a folder only from the local machine to the development container
+	SyncModeSendOnly = "sendonly"
+
 	syncFieldDocsURL = "https://okteto.com/docs/reference/manifest#sync-string-required"
 )
 
@@ -113,41 +175,89 @@ var (
 
 //Dev represents a development container
 type Dev struct {
-	Name                 string                `json:"name" yaml:"name"`
-	Autocreate           bool                  `json:"autocreate,omitempty" yaml:"autocreate,omitempty"`
-	Labels               map[string]string     `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Annotations          map[string]string     `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Tolerations          []apiv1.Toleration    `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
-	Context              string                `json:"context,omitempty" yaml:"context,omitempty"`
-	Namespace            string                `json:"namespace,omitempty" yaml:"namespace,omitempty"`
-	Container            string                `json:"container,omitempty" yaml:"container,omitempty"`
-	EmptyImage           bool                  `json:"-" yaml:"-"`
-	Image                *BuildInfo            `json:"image,omitempty" yaml:"image,omitempty"`
-	Push                 *BuildInfo            `json:"-" yaml:"push,omitempty"`
-	ImagePullPolicy      apiv1.PullPolicy      `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
-	Environment          []EnvVar              `json:"environment,omitempty" yaml:"environment,omitempty"`
-	Secrets              []Secret              `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	Command              Command               `json:"command,omitempty" yaml:"command,omitempty"`
-	Healthchecks         bool                  `json:"healthchecks,omitempty" yaml:"healthchecks,omitempty"`
-	Probes               *Probes               `json:"probes,omitempty" yaml:"probes,omitempty"`
-	WorkDir              string                `json:"workdir,omitempty" yaml:"workdir,omitempty"`
-	MountPath            string                `json:"mountpath,omitempty" yaml:"mountpath,omitempty"`
-	SubPath              string                `json:"subpath,omitempty" yaml:"subpath,omitempty"`
-	SecurityContext      *SecurityContext      `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
-	ServiceAccount       string                `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
-	RemotePort           int                   `json:"remote,omitempty" yaml:"remote,omitempty"`
-	SSHServerPort        int                   `json:"sshServerPort,omitempty" yaml:"sshServerPort,omitempty"`
-	Volumes              []Volume              `json:"volumes,omitempty" yaml:"volumes,omitempty"`
-	ExternalVolumes      []ExternalVolume      `json:"externalVolumes,omitempty" yaml:"externalVolumes,omitempty"`
-	Sync                 Sync                  `json:"sync,omitempty" yaml:"sync,omitempty"`
-	parentSyncFolder     string                `json:"-" yaml:"-"`
-	Forward              []Forward             `json:"forward,omitempty" yaml:"forward,omitempty"`
-	Reverse              []Reverse             `json:"reverse,omitempty" yaml:"reverse,omitempty"`
-	Interface            string                `json:"interface,omitempty" yaml:"interface,omitempty"`
-	Resources            ResourceRequirements  `json:"resources,omitempty" yaml:"resources,omitempty"`
-	Services             []*Dev                `json:"services,omitempty" yaml:"services,omitempty"`
-	PersistentVolumeInfo *PersistentVolumeInfo `json:"persistentVolume,omitempty" yaml:"persistentVolume,omitempty"`
-	InitContainer        InitContainer         `json:"initContainer,omitempty" yaml:"initContainer,omitempty"`
+	Name                  string                `json:"name" yaml:"name"`
+	Autocreate            bool                  `json:"autocreate,omitempty" yaml:"autocreate,omitempty"`
+	Labels                map[string]string     `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations           map[string]string     `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	PodLabels             map[string]string     `json:"podLabels,omitempty" yaml:"podLabels,omitempty"`
+	ReadinessGates        *ReadinessGates       `json:"readinessGates,omitempty" yaml:"readinessGates,omitempty"`
+	TopologySpread        *TopologySpread       `json:"topologySpread,omitempty" yaml:"topologySpread,omitempty"`
+	PreserveAnnotations   []string              `json:"preserveAnnotations,omitempty" yaml:"preserveAnnotations,omitempty"`
+	GitAnnotations        bool                  `json:"gitAnnotations,omitempty" yaml:"gitAnnotations,omitempty"`
+	DeployStrategy        string                `json:"deployStrategy,omitempty" yaml:"deployStrategy,omitempty"`
+	Tolerations           []Toleration          `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	ContainerPorts        []ContainerPort       `json:"containerPorts,omitempty" yaml:"containerPorts,omitempty"`
+	Context               string                `json:"context,omitempty" yaml:"context,omitempty"`
+	Namespace             string                `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Container             string                `json:"container,omitempty" yaml:"container,omitempty"`
+	ContainerSuffix       string                `json:"containerSuffix,omitempty" yaml:"containerSuffix,omitempty"`
+	EmptyImage            bool                  `json:"-" yaml:"-"`
+	Image                 *BuildInfo            `json:"image,omitempty" yaml:"image,omitempty"`
+	Push                  *BuildInfo            `json:"-" yaml:"push,omitempty"`
+	ImagePullPolicy       apiv1.PullPolicy      `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	PinDigest             bool                  `json:"pinDigest,omitempty" yaml:"pinDigest,omitempty"`
+	RegistryRewrites      []RegistryRewrite     `json:"registryRewrites,omitempty" yaml:"registryRewrites,omitempty"`
+	ImageDigest           string                `json:"-" yaml:"-"`
+	Environment           []EnvVar              `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Secrets               []Secret              `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Command               Command               `json:"command,omitempty" yaml:"command,omitempty"`
+	Args                  Args                  `json:"args,omitempty" yaml:"args,omitempty"`
+	PostSync              Command               `json:"postSync,omitempty" yaml:"postSync,omitempty"`
+	Healthchecks          bool                  `json:"healthchecks,omitempty" yaml:"healthchecks,omitempty"`
+	Probes                *Probes               `json:"probes,omitempty" yaml:"probes,omitempty"`
+	ContainerProbes       map[string]Probes     `json:"containerProbes,omitempty" yaml:"containerProbes,omitempty"`
+	LivenessGracePeriod   int32                 `json:"livenessGracePeriod,omitempty" yaml:"livenessGracePeriod,omitempty"`
+	LivenessFailureAction string                `json:"livenessFailureAction,omitempty" yaml:"livenessFailureAction,omitempty"`
+	WorkDir               string                `json:"workdir,omitempty" yaml:"workdir,omitempty"`
+	MountPath             string                `json:"mountpath,omitempty" yaml:"mountpath,omitempty"`
+	SubPath               string                `json:"subpath,omitempty" yaml:"subpath,omitempty"`
+	SecurityContext       *SecurityContext      `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
+	ServiceAccount        string                `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	CreateServiceAccount  *CreateServiceAccount `json:"createServiceAccount,omitempty" yaml:"createServiceAccount,omitempty"`
+	ShareProcessNamespace bool                  `json:"shareProcessNamespace,omitempty" yaml:"shareProcessNamespace,omitempty"`
+	RuntimeMemoryFlags    bool                  `json:"runtimeMemoryFlags,omitempty" yaml:"runtimeMemoryFlags,omitempty"`
+	SkipPodAffinity       bool                  `json:"skipPodAffinity,omitempty" yaml:"skipPodAffinity,omitempty"`
+	Hostname              string                `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Subdomain             string                `json:"subdomain,omitempty" yaml:"subdomain,omitempty"`
+	DNSConfig             *DNSConfig            `json:"dnsConfig,omitempty" yaml:"dnsConfig,omitempty"`
+	RemotePort            int                   `json:"remote,omitempty" yaml:"remote,omitempty"`
+	SSHServerPort         int                   `json:"sshServerPort,omitempty" yaml:"sshServerPort,omitempty"`
+	SSHKeepAliveInterval  int                   `json:"sshKeepAliveInterval,omitempty" yaml:"sshKeepAliveInterval,omitempty"`
+	ForwardConcurrency    int                   `json:"forwardConcurrency,omitempty" yaml:"forwardConcurrency,omitempty"`
+	WaitForService        int                   `json:"waitForService,omitempty" yaml:"waitForService,omitempty"`
+	Volumes               []Volume              `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	ExternalVolumes       []ExternalVolume      `json:"externalVolumes,omitempty" yaml:"externalVolumes,omitempty"`
+	InitContainerVolumes  []InitContainerVolume `json:"initContainerVolumes,omitempty" yaml:"initContainerVolumes,omitempty"`
+	ProjectedVolumes      []ProjectedVolume     `json:"projectedVolumes,omitempty" yaml:"projectedVolumes,omitempty"`
+	Sync                  Sync                  `json:"sync,omitempty" yaml:"sync,omitempty"`
+	Overlays              []string              `json:"overlays,omitempty" yaml:"overlays,omitempty"`
+	LogFifo               string                `json:"logFifo,omitempty" yaml:"logFifo,omitempty"`
+	TailLogs              []string              `json:"tailLogs,omitempty" yaml:"tailLogs,omitempty"`
+	OpenTelemetry         *OpenTelemetry        `json:"openTelemetry,omitempty" yaml:"openTelemetry,omitempty"`
+	WaitFor               []WaitFor             `json:"waitFor,omitempty" yaml:"waitFor,omitempty"`
+	ActiveDeadlineSeconds *int64                `json:"activeDeadlineSeconds,omitempty" yaml:"activeDeadlineSeconds,omitempty"`
+	GracePeriod           *GracePeriod          `json:"gracePeriod,omitempty" yaml:"gracePeriod,omitempty"`
+	CleanCutover          bool                  `json:"cleanCutover,omitempty" yaml:"cleanCutover,omitempty"`
+	VerifyDown            bool                  `json:"verifyDown,omitempty" yaml:"verifyDown,omitempty"`
+	parentSyncFolder      string                `json:"-" yaml:"-"`
+	Forward               []Forward             `json:"forward,omitempty" yaml:"forward,omitempty"`
+	Reverse               []Reverse             `json:"reverse,omitempty" yaml:"reverse,omitempty"`
+	Sockets               []SocketForward       `json:"sockets,omitempty" yaml:"sockets,omitempty"`
+	Interface             string                `json:"interface,omitempty" yaml:"interface,omitempty"`
+	Resources             ResourceRequirements  `json:"resources,omitempty" yaml:"resources,omitempty"`
+	SidecarResources      ContainerResources    `json:"sidecarResources,omitempty" yaml:"sidecarResources,omitempty"`
+	GPUFallback           bool                  `json:"gpuFallback,omitempty" yaml:"gpuFallback,omitempty"`
+	GPUDisabled           bool                  `json:"-" yaml:"-"`
+	StdinOnce             bool                  `json:"stdinOnce,omitempty" yaml:"stdinOnce,omitempty"`
+	Replicas              *int32                `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	Services              []*Dev                `json:"services,omitempty" yaml:"services,omitempty"`
+	PersistentVolumeInfo  *PersistentVolumeInfo `json:"persistentVolume,omitempty" yaml:"persistentVolume,omitempty"`
+	SharedCache           *SharedCache          `json:"sharedCache,omitempty" yaml:"sharedCache,omitempty"`
+	InotifyTuning         *InotifyTuning        `json:"inotifyTuning,omitempty" yaml:"inotifyTuning,omitempty"`
+	BackupExclusion       *bool                 `json:"backupExclusion,omitempty" yaml:"backupExclusion,omitempty"`
+	SafeToEvict           *bool                 `json:"safeToEvict,omitempty" yaml:"safeToEvict,omitempty"`
+	InitContainer         InitContainer         `json:"initContainer,omitempty" yaml:"initContainer,omitempty"`
+	BinPath               string                `json:"binPath,omitempty" yaml:"binPath,omitempty"`
 }
 
 //Command represents the start command of a development contaianer
@@ -162,12 +272,13 @@ type Args struct {
 
 // BuildInfo represents the build info to generate an image
 type BuildInfo struct {
-	Name       string   `yaml:"name,omitempty"`
-	Context    string   `yaml:"context,omitempty"`
-	Dockerfile string   `yaml:"dockerfile,omitempty"`
-	CacheFrom  []string `yaml:"cache_from,omitempty"`
-	Target     string   `yaml:"target,omitempty"`
-	Args       []EnvVar `yaml:"args,omitempty"`
+	Name       string            `yaml:"name,omitempty"`
+	Context    string            `yaml:"context,omitempty"`
+	Dockerfile string            `yaml:"dockerfile,omitempty"`
+	CacheFrom  []string          `yaml:"cache_from,omitempty"`
+	Target     string            `yaml:"target,omitempty"`
+	Args       []EnvVar          `yaml:"args,omitempty"`
+	Secrets    map[string]string `yaml:"secrets,omitempty"`
 }
 
 // Volume represents a volume in the development container
@@ -189,6 +300,7 @@ type Sync struct {
 type SyncFolder struct {
 	LocalPath  string
 	RemotePath string
+	Mode       string
 }
 
 // ExternalVolume represents a external volume in the development container
@@ -198,25 +310,104 @@ type ExternalVolume struct {
 	MountPath string
 }
 
+// ProjectedVolume represents a projected volume mounted in the development container
+type ProjectedVolume struct {
+	Name      string                  `json:"name,omitempty" yaml:"name,omitempty"`
+	MountPath string                  `json:"mountPath,omitempty" yaml:"mountPath,omitempty"`
+	Sources   []ProjectedVolumeSource `json:"sources,omitempty" yaml:"sources,omitempty"`
+}
+
+// ProjectedVolumeSource represents a source of a projected volume
+type ProjectedVolumeSource struct {
+	Secret      *ProjectedObject      `json:"secret,omitempty" yaml:"secret,omitempty"`
+	ConfigMap   *ProjectedObject      `json:"configMap,omitempty" yaml:"configMap,omitempty"`
+	DownwardAPI *ProjectedDownwardAPI `json:"downwardAPI,omitempty" yaml:"downwardAPI,omitempty"`
+}
+
+// ProjectedObject represents a secret or a configmap projected into a volume
+type ProjectedObject struct {
+	Name  string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Items []ProjectedItem `json:"items,omitempty" yaml:"items,omitempty"`
+}
+
+// ProjectedItem represents a key of a secret or a configmap projected into a path
+type ProjectedItem struct {
+	Key  string `json:"key,omitempty" yaml:"key,omitempty"`
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// ProjectedDownwardAPI represents the downward API fields projected into a volume
+type ProjectedDownwardAPI struct {
+	Items []ProjectedDownwardAPIItem `json:"items,omitempty" yaml:"items,omitempty"`
+}
+
+// ProjectedDownwardAPIItem represents a pod field projected into a path
+type ProjectedDownwardAPIItem struct {
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"`
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+}
+
 // PersistentVolumeInfo info about the persistent volume
 type PersistentVolumeInfo struct {
-	Enabled      bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	StorageClass string `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
-	Size         string `json:"size,omitempty" yaml:"size,omitempty"`
+	Enabled        bool                     `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	StorageClass   string                   `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
+	Size           string                   `json:"size,omitempty" yaml:"size,omitempty"`
+	BindingTimeout int                      `json:"bindingTimeout,omitempty" yaml:"bindingTimeout,omitempty"`
+	Hydrate        *PersistentVolumeHydrate `json:"hydrate,omitempty" yaml:"hydrate,omitempty"`
+}
+
+// PersistentVolumeHydrate defines the artifact extracted into the persistent volume when it is created
+type PersistentVolumeHydrate struct {
+	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
+	Path  string `json:"path,omitempty" yaml:"path,omitempty"`
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// SharedCache represents a cache volume shared by the development containers of a namespace
+type SharedCache struct {
+	Paths        []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Size         string   `json:"size,omitempty" yaml:"size,omitempty"`
+	StorageClass string   `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
+}
+
+// GracePeriod represents the termination grace periods of the development container by shutdown path
+type GracePeriod struct {
+	Dev      *int64 `json:"dev,omitempty" yaml:"dev,omitempty"`
+	Recovery int64  `json:"recovery,omitempty" yaml:"recovery,omitempty"`
+}
+
+// InotifyTuning represents the inotify limits raised on the cluster node by a privileged init container
+type InotifyTuning struct {
+	MaxUserWatches   int `json:"maxUserWatches,omitempty" yaml:"maxUserWatches,omitempty"`
+	MaxUserInstances int `json:"maxUserInstances,omitempty" yaml:"maxUserInstances,omitempty"`
+}
+
+// InitContainerVolume represents the volumes of the development container mounted in an init container
+type InitContainerVolume struct {
+	Container  string   `json:"container,omitempty" yaml:"container,omitempty"`
+	MountPaths []string `json:"mountPaths,omitempty" yaml:"mountPaths,omitempty"`
 }
 
 // InitContainer represents the initial container
 type InitContainer struct {
-	Image     string               `json:"image,omitempty" yaml:"image,omitempty"`
-	Resources ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Image       string               `json:"image,omitempty" yaml:"image,omitempty"`
+	Resources   ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	MountPath   string               `json:"mountPath,omitempty" yaml:"mountPath,omitempty"`
+	InjectShell bool                 `json:"injectShell,omitempty" yaml:"injectShell,omitempty"`
 }
 
 // SecurityContext represents a pod security context
 type SecurityContext struct {
-	RunAsUser    *int64        `json:"runAsUser,omitempty" yaml:"runAsUser,omitempty"`
-	RunAsGroup   *int64        `json:"runAsGroup,omitempty" yaml:"runAsGroup,omitempty"`
-	FSGroup      *int64        `json:"fsGroup,omitempty" yaml:"fsGroup,omitempty"`
-	Capabilities *Capabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	RunAsUser                *int64                        `json:"runAsUser,omitempty" yaml:"runAsUser,omitempty"`
+	RunAsGroup               *int64                        `json:"runAsGroup,omitempty" yaml:"runAsGroup,omitempty"`
+	FSGroup                  *int64                        `json:"fsGroup,omitempty" yaml:"fsGroup,omitempty"`
+	FSGroupChangePolicy      *apiv1.PodFSGroupChangePolicy `json:"fsGroupChangePolicy,omitempty" yaml:"fsGroupChangePolicy,omitempty"`
+	SupplementalGroups       []int64                       `json:"supplementalGroups,omitempty" yaml:"supplementalGroups,omitempty"`
+	Capabilities             *Capabilities                 `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	SELinuxOptions           *apiv1.SELinuxOptions         `json:"seLinuxOptions,omitempty" yaml:"seLinuxOptions,omitempty"`
+	Privileged               *bool                         `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+	AllowPrivilegeEscalation *bool                         `json:"allowPrivilegeEscalation,omitempty" yaml:"allowPrivilegeEscalation,omitempty"`
+	LocalUser                bool                          `json:"localUser,omitempty" yaml:"localUser,omitempty"`
 }
 
 // Capabilities sets the linux capabilities of a container
@@ -225,10 +416,94 @@ type Capabilities struct {
 	Drop []apiv1.Capability `json:"drop,omitempty" yaml:"drop,omitempty"`
 }
 
+// WaitFor defines a dependency the development container waits for before running the dev command
+type WaitFor struct {
+	Host    string `json:"host,omitempty" yaml:"host,omitempty"`
+	Port    int    `json:"port,omitempty" yaml:"port,omitempty"`
+	Timeout int    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// RegistryRewrite replaces the registry prefix of the images of the development container
+type RegistryRewrite struct {
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	To   string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// DNSConfig defines the DNS parameters of the development container pod
+type DNSConfig struct {
+	Nameservers []string          `json:"nameservers,omitempty" yaml:"nameservers,omitempty"`
+	Searches    []string          `json:"searches,omitempty" yaml:"searches,omitempty"`
+	Options     []DNSConfigOption `json:"options,omitempty" yaml:"options,omitempty"`
+	Merge       bool              `json:"merge,omitempty" yaml:"merge,omitempty"`
+}
+
+// DNSConfigOption defines a resolver option of the development container pod
+type DNSConfigOption struct {
+	Name  string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Value *string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// ReadinessGates defines how the readiness gates of the pod are translated for the development container
+type ReadinessGates struct {
+	Strip          bool                     `json:"strip,omitempty" yaml:"strip,omitempty"`
+	ConditionTypes []apiv1.PodConditionType `json:"conditionTypes,omitempty" yaml:"conditionTypes,omitempty"`
+}
+
+// TopologySpread defines how the topology spread constraints of the pod are translated for the development container
+type TopologySpread struct {
+	WhenUnsatisfiable apiv1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty" yaml:"whenUnsatisfiable,omitempty"`
+}
+
+// OpenTelemetry defines an OpenTelemetry collector injected as a sidecar of the development container
+type OpenTelemetry struct {
+	Image string   `json:"image,omitempty" yaml:"image,omitempty"`
+	Args  []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// CreateServiceAccount defines a dedicated service account created for the development container
+type CreateServiceAccount struct {
+	RoleBindings []RoleBinding `json:"roleBindings,omitempty" yaml:"roleBindings,omitempty"`
+}
+
+// RoleBinding represents a role granted to the dedicated service account of the development container
+type RoleBinding struct {
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Toleration represents a toleration of the development container pod
+type Toleration struct {
+	Key               string                   `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator          apiv1.TolerationOperator `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value             string                   `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect            apiv1.TaintEffect        `json:"effect,omitempty" yaml:"effect,omitempty"`
+	TolerationSeconds *int64                   `json:"tolerationSeconds,omitempty" yaml:"tolerationSeconds,omitempty"`
+}
+
+// ContainerPort represents a port exposed by the development container
+type ContainerPort struct {
+	Name          string         `json:"name,omitempty" yaml:"name,omitempty"`
+	ContainerPort int32          `json:"containerPort,omitempty" yaml:"containerPort,omitempty"`
+	Protocol      apiv1.Protocol `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+}
+
 // EnvVar represents an environment value. When loaded, it will expand from the current env
 type EnvVar struct {
-	Name  string `yaml:"name,omitempty"`
-	Value string `yaml:"value,omitempty"`
+	Name      string        `yaml:"name,omitempty"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a secret or configmap key the value of an environment variable is read from
+type EnvVarSource struct {
+	SecretKeyRef    *KeySelector `json:"secretKeyRef,omitempty" yaml:"secretKeyRef,omitempty"`
+	ConfigMapKeyRef *KeySelector `json:"configMapKeyRef,omitempty" yaml:"configMapKeyRef,omitempty"`
+}
+
+// KeySelector selects a key of a secret or configmap
+type KeySelector struct {
+	Name string `json:"name" yaml:"name"`
+	Key  string `json:"key" yaml:"key"`
 }
 
 // Secret represents a development secret
@@ -250,6 +525,9 @@ type ResourceRequirements struct {
 	Requests ResourceList `json:"requests,omitempty" yaml:"requests,omitempty"`
 }
 
+// ContainerResources represents the resource requirements of the containers of a pod by container name
+type ContainerResources map[string]ResourceRequirements
+
 // Probes defines probes for containers
 type Probes struct {
 	Liveness  bool `json:"liveness,omitempty" yaml:"liveness,omitempty"`
@@ -361,7 +639,13 @@ func (dev *Dev) loadAbsPaths(devPath string) error {
 	dev.Image.Dockerfile = loadAbsPath(devDir, dev.Image.Dockerfile)
 	dev.Push.Context = loadAbsPath(devDir, dev.Push.Context)
 	dev.Push.Dockerfile = loadAbsPath(devDir, dev.Push.Dockerfile)
+	for id, src := range dev.Image.Secrets {
+		dev.Image.Secrets[id] = loadAbsPath(devDir, src)
+	}
 	dev.loadVolumeAbsPaths(devDir)
+	for i := range dev.Sockets {
+		dev.Sockets[i].Local = loadAbsPath(devDir, dev.Sockets[i].Local)
+	}
 	for _, s := range dev.Services {
 		s.loadVolumeAbsPaths(devDir)
 	}
@@ -496,6 +780,9 @@ func (dev *Dev) setDefaults() error {
 	if dev.SSHServerPort == 0 {
 		dev.SSHServerPort = oktetoDefaultSSHServerPort
 	}
+	if dev.SSHKeepAliveInterval == 0 {
+		dev.SSHKeepAliveInterval = DefaultSSHKeepAliveInterval
+	}
 	dev.setRunAsUserDefaults(dev)
 
 	if os.Getenv("OKTETO_RESCAN_INTERVAL") != "" {
@@ -510,6 +797,24 @@ func (dev *Dev) setDefaults() error {
 	if dev.InitContainer.Image == "" {
 		dev.InitContainer.Image = OktetoBinImageTag
 	}
+	if dev.InitContainer.MountPath == "" {
+		dev.InitContainer.MountPath = OktetoInitBinMountPath
+	}
+	if dev.BinPath == "" {
+		dev.BinPath = OktetoBinMountPath
+	}
+	if dev.OpenTelemetry != nil {
+		if dev.OpenTelemetry.Image == "" {
+			dev.OpenTelemetry.Image = DefaultOpenTelemetryImage
+		}
+	}
+	if dev.CreateServiceAccount != nil {
+		for i := range dev.CreateServiceAccount.RoleBindings {
+			if dev.CreateServiceAccount.RoleBindings[i].Kind == "" {
+				dev.CreateServiceAccount.RoleBindings[i].Kind = ClusterRoleKind
+			}
+		}
+	}
 
 	for _, s := range dev.Services {
 		if s.ImagePullPolicy == "" {
@@ -529,6 +834,7 @@ func (dev *Dev) setDefaults() error {
 		s.setRunAsUserDefaults(dev)
 		s.Forward = make([]Forward, 0)
 		s.Reverse = make([]Reverse, 0)
+		s.Sockets = make([]SocketForward, 0)
 		s.Secrets = make([]Secret, 0)
 		s.Services = make([]*Dev, 0)
 		s.Sync.Compression = false
@@ -556,6 +862,9 @@ func (dev *Dev) setRunAsUserDefaults(main *Dev) {
 	if dev.SecurityContext == nil {
 		dev.SecurityContext = &SecurityContext{}
 	}
+	if dev.SecurityContext.LocalUser {
+		return
+	}
 	if dev.SecurityContext.RunAsUser == nil {
 		dev.SecurityContext.RunAsUser = &rootUser
 	}
@@ -567,6 +876,25 @@ func (dev *Dev) setRunAsUserDefaults(main *Dev) {
 	}
 }
 
+//GetDevContainerName returns the name of the development container once translated
+func (dev *Dev) GetDevContainerName() string {
+	return dev.Container + dev.ContainerSuffix
+}
+
+//RunsAsRoot returns if the development container is explicitly configured to run as the root user
+func (dev *Dev) RunsAsRoot() bool {
+	return dev.SecurityContext != nil && dev.SecurityContext.RunAsUser != nil && *dev.SecurityContext.RunAsUser == 0
+}
+
+//SyncOwnershipWarning returns a warning if the user of the development container might not be able to own the synchronized files
+func (dev *Dev) SyncOwnershipWarning() string {
+	s := dev.SecurityContext
+	if s == nil || s.RunAsUser == nil || *s.RunAsUser == 0 || s.FSGroup != nil {
+		return ""
+	}
+	return fmt.Sprintf("Your development container runs as user %d without 'securityContext.fsGroup': synchronized files might not be owned by this user and their permissions might not be preserved", *s.RunAsUser)
+}
+
 func (dev *Dev) validate() error {
 	if dev.Name == "" {
 		return fmt.Errorf("Name cannot be empty")
@@ -584,6 +912,10 @@ func (dev *Dev) validate() error {
 		return fmt.Errorf("'subpath' is not supported in the main dev container")
 	}
 
+	if len(dev.Args.Values) > 0 {
+		return fmt.Errorf("'args' is not supported in the main dev container")
+	}
+
 	if err := validatePullPolicy(dev.ImagePullPolicy); err != nil {
 		return err
 	}
@@ -608,14 +940,279 @@ func (dev *Dev) validate() error {
 		return fmt.Errorf("'persistentVolume.size' is not valid. A sample value would be '10Gi'")
 	}
 
+	if dev.PersistentVolumeInfo != nil && dev.PersistentVolumeInfo.BindingTimeout < 0 {
+		return fmt.Errorf("'persistentVolume.bindingTimeout' must be >= 0")
+	}
+
+	if err := dev.validatePersistentVolumeHydrate(); err != nil {
+		return err
+	}
+
+	if err := dev.validateSharedCache(); err != nil {
+		return err
+	}
+
+	if err := dev.validateInotifyTuning(); err != nil {
+		return err
+	}
+
+	if dev.GracePeriod != nil && ((dev.GracePeriod.Dev != nil && *dev.GracePeriod.Dev < 0) || dev.GracePeriod.Recovery < 0) {
+		return fmt.Errorf("'gracePeriod' values must be >= 0")
+	}
+
 	if dev.SSHServerPort <= 0 {
 		return fmt.Errorf("'sshServerPort' must be > 0")
 	}
 
+	if dev.SSHKeepAliveInterval < 0 {
+		return fmt.Errorf("'sshKeepAliveInterval' must be >= 0")
+	}
+
+	if dev.ForwardConcurrency < 0 {
+		return fmt.Errorf("'forwardConcurrency' must be >= 0")
+	}
+
+	if err := validateSockets(dev.Sockets); err != nil {
+		return err
+	}
+
+	if dev.LivenessGracePeriod < 0 {
+		return fmt.Errorf("'livenessGracePeriod' must be >= 0")
+	}
+
+	if err := validateLivenessFailureAction(dev.LivenessFailureAction); err != nil {
+		return err
+	}
+
+	if dev.WaitForService < 0 {
+		return fmt.Errorf("'waitForService' must be >= 0")
+	}
+
+	switch dev.DeployStrategy {
+	case "", DeployStrategyReplace, DeployStrategyPatch:
+	default:
+		return fmt.Errorf("supported values for 'deployStrategy' are: '%s' or '%s'", DeployStrategyReplace, DeployStrategyPatch)
+	}
+
+	if err := validateBinPaths(dev.BinPath, dev.InitContainer.MountPath); err != nil {
+		return err
+	}
+
+	if err := dev.validateOverlays(); err != nil {
+		return err
+	}
+
+	if err := dev.validateCreateServiceAccount(); err != nil {
+		return err
+	}
+
+	if err := validateHostname(dev.Hostname, dev.Subdomain); err != nil {
+		return err
+	}
+
+	if err := validatePodLabels(dev.PodLabels); err != nil {
+		return err
+	}
+
+	if err := validateReadinessGates(dev.ReadinessGates); err != nil {
+		return err
+	}
+
+	if err := validateTopologySpread(dev.TopologySpread); err != nil {
+		return err
+	}
+
+	if err := validateDNSConfig(dev.DNSConfig); err != nil {
+		return err
+	}
+
+	if err := validateRegistryRewrites(dev.RegistryRewrites); err != nil {
+		return err
+	}
+
+	if err := validateBuildSecrets(dev.Image.Secrets); err != nil {
+		return err
+	}
+
+	if err := validateInitContainerVolumes(dev.InitContainerVolumes); err != nil {
+		return err
+	}
+
+	if err := validateProjectedVolumes(dev.ProjectedVolumes); err != nil {
+		return err
+	}
+
+	if err := validateSecurityContext(dev.SecurityContext); err != nil {
+		return err
+	}
+
+	if err := validateContainerPorts(dev.ContainerPorts); err != nil {
+		return err
+	}
+
+	if err := validateWaitFor(dev.WaitFor); err != nil {
+		return err
+	}
+
+	if dev.ActiveDeadlineSeconds != nil {
+		return fmt.Errorf("'activeDeadlineSeconds' is not supported: the development container runs in a deployment and deployments don't allow an active deadline")
+	}
+
+	if err := dev.validateLogFifo(); err != nil {
+		return err
+	}
+
+	if err := validateTailLogs(dev.TailLogs); err != nil {
+		return err
+	}
+
+	if err := validateContainerSuffix(dev.ContainerSuffix); err != nil {
+		return err
+	}
+
+	if err := validateSidecarResources(dev.Container, dev.SidecarResources); err != nil {
+		return err
+	}
+
+	if err := validateEnvironment(dev.Environment); err != nil {
+		return err
+	}
+
+	if err := validateBuildArgs(dev.Image, dev.Push); err != nil {
+		return err
+	}
+
+	for name := range dev.ContainerProbes {
+		if name == "" {
+			return fmt.Errorf("'containerProbes' container names cannot be empty")
+		}
+	}
+
+	if dev.Replicas != nil && *dev.Replicas < 1 {
+		return fmt.Errorf("'replicas' must be > 0")
+	}
+
 	for _, s := range dev.Services {
 		if err := validatePullPolicy(s.ImagePullPolicy); err != nil {
 			return err
 		}
+		if s.LivenessGracePeriod < 0 {
+			return fmt.Errorf("'livenessGracePeriod' must be >= 0")
+		}
+		if s.Replicas != nil && *s.Replicas < 1 {
+			return fmt.Errorf("'replicas' must be > 0")
+		}
+		if err := validateSidecarResources(s.Container, s.SidecarResources); err != nil {
+			return err
+		}
+		if err := validateEnvironment(s.Environment); err != nil {
+			return err
+		}
+		if err := validateBuildArgs(s.Image, s.Push); err != nil {
+			return err
+		}
+		if err := validateLivenessFailureAction(s.LivenessFailureAction); err != nil {
+			return err
+		}
+		if len(s.Overlays) > 0 {
+			return fmt.Errorf("'overlays' is not supported in 'services'")
+		}
+		if len(s.PostSync.Values) > 0 {
+			return fmt.Errorf("'postSync' is not supported in 'services'")
+		}
+		if s.CreateServiceAccount != nil {
+			return fmt.Errorf("'createServiceAccount' is not supported in 'services'")
+		}
+		if s.LogFifo != "" {
+			return fmt.Errorf("'logFifo' is not supported in 'services'")
+		}
+		if s.GitAnnotations {
+			return fmt.Errorf("'gitAnnotations' is not supported in 'services'")
+		}
+		if s.ContainerSuffix != "" {
+			return fmt.Errorf("'containerSuffix' is not supported in 'services'")
+		}
+		if s.SafeToEvict != nil {
+			return fmt.Errorf("'safeToEvict' is not supported in 'services'")
+		}
+		if s.BackupExclusion != nil {
+			return fmt.Errorf("'backupExclusion' is not supported in 'services'")
+		}
+		if s.SharedCache != nil {
+			return fmt.Errorf("'sharedCache' is not supported in 'services'")
+		}
+		if s.InotifyTuning != nil {
+			return fmt.Errorf("'inotifyTuning' is not supported in 'services'")
+		}
+		if s.GPUFallback {
+			return fmt.Errorf("'gpuFallback' is not supported in 'services'")
+		}
+		if len(s.ContainerProbes) > 0 {
+			return fmt.Errorf("'containerProbes' is not supported in 'services'")
+		}
+		if s.StdinOnce {
+			return fmt.Errorf("'stdinOnce' is not supported in 'services'")
+		}
+		if s.GracePeriod != nil {
+			return fmt.Errorf("'gracePeriod' is not supported in 'services'")
+		}
+		if s.CleanCutover {
+			return fmt.Errorf("'cleanCutover' is not supported in 'services'")
+		}
+		if s.VerifyDown {
+			return fmt.Errorf("'verifyDown' is not supported in 'services'")
+		}
+		if s.ForwardConcurrency != 0 {
+			return fmt.Errorf("'forwardConcurrency' is not supported in 'services'")
+		}
+		if len(s.TailLogs) > 0 {
+			return fmt.Errorf("'tailLogs' is not supported in 'services'")
+		}
+		if len(s.RegistryRewrites) > 0 {
+			return fmt.Errorf("'registryRewrites' is not supported in 'services'")
+		}
+		if s.ActiveDeadlineSeconds != nil {
+			return fmt.Errorf("'activeDeadlineSeconds' is not supported in 'services'")
+		}
+		if len(s.WaitFor) > 0 {
+			return fmt.Errorf("'waitFor' is not supported in 'services'")
+		}
+		if s.OpenTelemetry != nil {
+			return fmt.Errorf("'openTelemetry' is not supported in 'services'")
+		}
+		if s.SkipPodAffinity {
+			return fmt.Errorf("'skipPodAffinity' is not supported in 'services'")
+		}
+		if dev.SkipPodAffinity && len(s.Volumes) > 0 {
+			return fmt.Errorf("'skipPodAffinity' cannot be used when 'services' mount 'volumes'")
+		}
+		if err := validateHostname(s.Hostname, s.Subdomain); err != nil {
+			return err
+		}
+		if err := validatePodLabels(s.PodLabels); err != nil {
+			return err
+		}
+		if err := validateReadinessGates(s.ReadinessGates); err != nil {
+			return err
+		}
+		if err := validateTopologySpread(s.TopologySpread); err != nil {
+			return err
+		}
+		if err := validateDNSConfig(s.DNSConfig); err != nil {
+			return err
+		}
+		if err := validateInitContainerVolumes(s.InitContainerVolumes); err != nil {
+			return err
+		}
+		if err := validateProjectedVolumes(s.ProjectedVolumes); err != nil {
+			return err
+		}
+		if err := validateSecurityContext(s.SecurityContext); err != nil {
+			return err
+		}
+		if err := validateContainerPorts(s.ContainerPorts); err != nil {
+			return err
+		}
 		if err := s.validateVolumes(dev); err != nil {
 			return err
 		}
@@ -624,13 +1221,520 @@ func (dev *Dev) validate() error {
 	return nil
 }
 
+//getOpenTelemetryEnvVars returns the env vars that point the OpenTelemetry SDKs to the collector sidecar.
+//Variables already defined in 'environment' are not overridden
+func (dev *Dev) getOpenTelemetryEnvVars() []EnvVar {
+	defined := map[string]bool{}
+	for _, e := range dev.Environment {
+		defined[e.Name] = true
+	}
+
+	result := []EnvVar{}
+	for _, e := range []EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: fmt.Sprintf("http://localhost:%d", OpenTelemetryPort)},
+		{Name: "OTEL_SERVICE_NAME", Value: dev.Name},
+	} {
+		if !defined[e.Name] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (dev *Dev) validateCreateServiceAccount() error {
+	if dev.CreateServiceAccount == nil {
+		return nil
+	}
+	if dev.ServiceAccount != "" {
+		return fmt.Errorf("'serviceAccount' and 'createServiceAccount' cannot be used at the same time")
+	}
+	for _, rb := range dev.CreateServiceAccount.RoleBindings {
+		if rb.Name == "" {
+			return fmt.Errorf("'createServiceAccount.roleBindings' must have a name")
+		}
+		if rb.Kind != RoleKind && rb.Kind != ClusterRoleKind {
+			return fmt.Errorf("supported values for 'createServiceAccount.roleBindings.kind' are: '%s' or '%s'", RoleKind, ClusterRoleKind)
+		}
+	}
+	return nil
+}
+
+func validateHostname(hostname, subdomain string) error {
+	if hostname != "" {
+		if errs := validation.IsDNS1123Label(hostname); len(errs) > 0 {
+			return fmt.Errorf("'hostname' is not valid: %s", strings.Join(errs, ", "))
+		}
+	}
+	if subdomain != "" {
+		if errs := validation.IsDNS1123Label(subdomain); len(errs) > 0 {
+			return fmt.Errorf("'subdomain' is not valid: %s", strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateWaitFor(waitFor []WaitFor) error {
+	for _, w := range waitFor {
+		if w.Host == "" {
+			return fmt.Errorf("'waitFor.host' cannot be empty")
+		}
+		if net.ParseIP(w.Host) == nil {
+			if errs := validation.IsDNS1123Subdomain(w.Host); len(errs) > 0 {
+				return fmt.Errorf("'waitFor.host' '%s' is not valid: %s", w.Host, strings.Join(errs, ", "))
+			}
+		}
+		if w.Port <= 0 || w.Port > 65535 {
+			return fmt.Errorf("'waitFor.port' must be between 1 and 65535")
+		}
+		if w.Timeout < 0 {
+			return fmt.Errorf("'waitFor.timeout' must be >= 0")
+		}
+	}
+	return nil
+}
+
+func validateContainerPorts(ports []ContainerPort) error {
+	names := map[string]bool{}
+	seen := map[string]bool{}
+	for _, p := range ports {
+		if p.ContainerPort <= 0 || p.ContainerPort > 65535 {
+			return fmt.Errorf("'containerPorts.containerPort' must be between 1 and 65535")
+		}
+		switch p.Protocol {
+		case "", apiv1.ProtocolTCP, apiv1.ProtocolUDP, apiv1.ProtocolSCTP:
+		default:
+			return fmt.Errorf("supported values for 'containerPorts.protocol' are: 'TCP', 'UDP' or 'SCTP'")
+		}
+		if p.Name != "" {
+			if errs := validation.IsValidPortName(p.Name); len(errs) > 0 {
+				return fmt.Errorf("'containerPorts.name' '%s' is not valid: %s", p.Name, strings.Join(errs, ", "))
+			}
+			if names[p.Name] {
+				return fmt.Errorf("'containerPorts.name' '%s' is duplicated", p.Name)
+			}
+			names[p.Name] = true
+		}
+		key := fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol)
+		if p.Protocol == "" {
+			key = fmt.Sprintf("%d/%s", p.ContainerPort, apiv1.ProtocolTCP)
+		}
+		if seen[key] {
+			return fmt.Errorf("'containerPorts.containerPort' '%s' is duplicated", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func validateInitContainerVolumes(volumes []InitContainerVolume) error {
+	for _, v := range volumes {
+		if v.Container == "" {
+			return fmt.Errorf("'initContainerVolumes.container' cannot be empty")
+		}
+		if len(v.MountPaths) == 0 {
+			return fmt.Errorf("'initContainerVolumes.mountPaths' of init container '%s' cannot be empty", v.Container)
+		}
+		for _, mountPath := range v.MountPaths {
+			if !strings.HasPrefix(mountPath, "/") {
+				return fmt.Errorf("'initContainerVolumes.mountPaths' value '%s' must be an absolute path", mountPath)
+			}
+		}
+	}
+	return nil
+}
+
+func validateLivenessFailureAction(action string) error {
+	switch action {
+	case "", LivenessFailureActionRestart, LivenessFailureActionIgnore:
+		return nil
+	default:
+		return fmt.Errorf("supported values for 'livenessFailureAction' are: '%s' or '%s'", LivenessFailureActionRestart, LivenessFailureActionIgnore)
+	}
+}
+
+func validateProjectedVolumes(volumes []ProjectedVolume) error {
+	seen := map[string]bool{}
+	for _, v := range volumes {
+		if errs := validation.IsDNS1123Label(v.Name); len(errs) > 0 {
+			return fmt.Errorf("'projectedVolumes.name' value '%s' is not valid: %s", v.Name, strings.Join(errs, ", "))
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("'projectedVolumes.name' value '%s' is duplicated", v.Name)
+		}
+		seen[v.Name] = true
+		if v.MountPath != "" && !strings.HasPrefix(v.MountPath, "/") {
+			return fmt.Errorf("'projectedVolumes.mountPath' value '%s' must be an absolute path", v.MountPath)
+		}
+		if len(v.Sources) == 0 {
+			return fmt.Errorf("'projectedVolumes.sources' of volume '%s' cannot be empty", v.Name)
+		}
+		for _, source := range v.Sources {
+			if err := validateProjectedVolumeSource(v.Name, source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateProjectedVolumeSource(name string, source ProjectedVolumeSource) error {
+	count := 0
+	if source.Secret != nil {
+		count++
+		if source.Secret.Name == "" {
+			return fmt.Errorf("'projectedVolumes.sources.secret.name' of volume '%s' cannot be empty", name)
+		}
+	}
+	if source.ConfigMap != nil {
+		count++
+		if source.ConfigMap.Name == "" {
+			return fmt.Errorf("'projectedVolumes.sources.configMap.name' of volume '%s' cannot be empty", name)
+		}
+	}
+	if source.DownwardAPI != nil {
+		count++
+		if len(source.DownwardAPI.Items) == 0 {
+			return fmt.Errorf("'projectedVolumes.sources.downwardAPI.items' of volume '%s' cannot be empty", name)
+		}
+		for _, item := range source.DownwardAPI.Items {
+			if item.Path == "" || item.FieldPath == "" {
+				return fmt.Errorf("'projectedVolumes.sources.downwardAPI.items' of volume '%s' must define 'path' and 'fieldPath'", name)
+			}
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("'projectedVolumes.sources' of volume '%s' must define exactly one of 'secret', 'configMap' or 'downwardAPI'", name)
+	}
+	return nil
+}
+
+func validateBuildSecrets(secrets map[string]string) error {
+	for id, src := range secrets {
+		if id == "" || strings.ContainsAny(id, ",=") {
+			return fmt.Errorf("'image.secrets' id '%s' is not valid", id)
+		}
+		if src == "" {
+			return fmt.Errorf("'image.secrets' file of secret '%s' cannot be empty", id)
+		}
+	}
+	return nil
+}
+
+func validateRegistryRewrites(rewrites []RegistryRewrite) error {
+	seen := map[string]bool{}
+	for _, r := range rewrites {
+		if r.From == "" || r.To == "" {
+			return fmt.Errorf("'registryRewrites.from' and 'registryRewrites.to' cannot be empty")
+		}
+		if strings.Contains(r.From, "://") || strings.Contains(r.To, "://") {
+			return fmt.Errorf("'registryRewrites' must be registry prefixes without a scheme")
+		}
+		from := strings.TrimSuffix(r.From, "/")
+		if seen[from] {
+			return fmt.Errorf("'registryRewrites.from' value '%s' is duplicated", r.From)
+		}
+		seen[from] = true
+	}
+	return nil
+}
+
+//RewriteImage replaces the registry prefix of an image with the longest matching registry rewrite
+func RewriteImage(image string, rewrites []RegistryRewrite) string {
+	if image == "" || len(rewrites) == 0 {
+		return image
+	}
+	normalized := normalizeImageRegistry(image)
+	result := image
+	longest := 0
+	for _, r := range rewrites {
+		from := strings.TrimSuffix(r.From, "/")
+		if !strings.HasPrefix(normalized, from+"/") || len(from) <= longest {
+			continue
+		}
+		result = strings.TrimSuffix(r.To, "/") + strings.TrimPrefix(normalized, from)
+		longest = len(from)
+	}
+	return result
+}
+
+//normalizeImageRegistry adds the implicit docker hub registry to an image reference
+func normalizeImageRegistry(image string) string {
+	i := strings.IndexRune(image, '/')
+	if i == -1 {
+		return fmt.Sprintf("docker.io/library/%s", image)
+	}
+	if !strings.ContainsAny(image[:i], ".:") && image[:i] != "localhost" {
+		return fmt.Sprintf("docker.io/%s", image)
+	}
+	return image
+}
+
+func validateDNSConfig(d *DNSConfig) error {
+	if d == nil {
+		return nil
+	}
+	for _, nameserver := range d.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return fmt.Errorf("'dnsConfig.nameservers' value '%s' is not a valid IP address", nameserver)
+		}
+	}
+	for _, search := range d.Searches {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, ".")); len(errs) > 0 {
+			return fmt.Errorf("'dnsConfig.searches' value '%s' is not valid: %s", search, strings.Join(errs, ", "))
+		}
+	}
+	for _, option := range d.Options {
+		if option.Name == "" {
+			return fmt.Errorf("'dnsConfig.options.name' cannot be empty")
+		}
+	}
+	return nil
+}
+
+func validateReadinessGates(r *ReadinessGates) error {
+	if r == nil {
+		return nil
+	}
+	seen := map[apiv1.PodConditionType]bool{}
+	for _, conditionType := range r.ConditionTypes {
+		if errs := validation.IsQualifiedName(string(conditionType)); len(errs) > 0 {
+			return fmt.Errorf("'readinessGates.conditionTypes' value '%s' is not valid: %s", conditionType, strings.Join(errs, ", "))
+		}
+		if seen[conditionType] {
+			return fmt.Errorf("'readinessGates.conditionTypes' value '%s' is duplicated", conditionType)
+		}
+		seen[conditionType] = true
+	}
+	return nil
+}
+
+func (dev *Dev) validatePersistentVolumeHydrate() error {
+	if dev.PersistentVolumeInfo == nil || dev.PersistentVolumeInfo.Hydrate == nil {
+		return nil
+	}
+	if !dev.PersistentVolumeEnabled() {
+		return fmt.Errorf("'persistentVolume.hydrate' requires the persistent volume to be enabled")
+	}
+	h := dev.PersistentVolumeInfo.Hydrate
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("'persistentVolume.hydrate.url' must be an http or https url")
+	}
+	if !path.IsAbs(h.Path) {
+		return fmt.Errorf("'persistentVolume.hydrate.path' must be an absolute path")
+	}
+	return nil
+}
+
+//validateLogFifo checks that the directory of the log fifo can be shared with the sidecar without hiding other paths of the development container
+func (dev *Dev) validateLogFifo() error {
+	if dev.LogFifo == "" {
+		return nil
+	}
+	if !path.IsAbs(dev.LogFifo) || path.Clean(dev.LogFifo) == "/" {
+		return fmt.Errorf("'logFifo' must be an absolute file path")
+	}
+	dir := path.Dir(path.Clean(dev.LogFifo))
+	if dir == "/" {
+		return fmt.Errorf("'logFifo' value '%s' can't be in the root directory", dev.LogFifo)
+	}
+	paths := []string{}
+	for _, sync := range dev.Sync.Folders {
+		paths = append(paths, sync.RemotePath)
+	}
+	for _, v := range dev.Volumes {
+		paths = append(paths, v.RemotePath)
+	}
+	for _, v := range dev.ExternalVolumes {
+		paths = append(paths, v.MountPath)
+	}
+	for _, p := range paths {
+		if isSubPath(dir, p) || isSubPath(p, dir) {
+			return fmt.Errorf("'logFifo' value '%s' overlaps with '%s': the directory of the log fifo is mounted in the development container", dev.LogFifo, p)
+		}
+	}
+	return nil
+}
+
+func (dev *Dev) validateSharedCache() error {
+	if dev.SharedCache == nil {
+		return nil
+	}
+	if len(dev.SharedCache.Paths) == 0 {
+		return fmt.Errorf("'sharedCache.paths' cannot be empty")
+	}
+	seen := map[string]bool{}
+	for _, p := range dev.SharedCache.Paths {
+		if !path.IsAbs(p) {
+			return fmt.Errorf("'sharedCache.paths' value '%s' must be an absolute path", p)
+		}
+		p = path.Clean(p)
+		if seen[p] {
+			return fmt.Errorf("'sharedCache.paths' value '%s' is duplicated", p)
+		}
+		seen[p] = true
+		for _, sync := range dev.Sync.Folders {
+			if path.Clean(sync.RemotePath) == p {
+				return fmt.Errorf("'sharedCache.paths' value '%s' is already a sync folder", p)
+			}
+		}
+	}
+	if dev.SharedCache.Size != "" {
+		if _, err := resource.ParseQuantity(dev.SharedCache.Size); err != nil {
+			return fmt.Errorf("'sharedCache.size' value '%s' is not valid: %s", dev.SharedCache.Size, err)
+		}
+	}
+	return nil
+}
+
+func (dev *Dev) validateInotifyTuning() error {
+	if dev.InotifyTuning == nil {
+		return nil
+	}
+	if dev.InotifyTuning.MaxUserWatches < 0 {
+		return fmt.Errorf("'inotifyTuning.maxUserWatches' must be >= 0")
+	}
+	if dev.InotifyTuning.MaxUserInstances < 0 {
+		return fmt.Errorf("'inotifyTuning.maxUserInstances' must be >= 0")
+	}
+	return nil
+}
+
+func validateSidecarResources(container string, sidecars ContainerResources) error {
+	for name := range sidecars {
+		if name == "" {
+			return fmt.Errorf("'sidecarResources' container names cannot be empty")
+		}
+		if name == container {
+			return fmt.Errorf("'sidecarResources' cannot override the resources of the development container '%s': use 'resources' instead", name)
+		}
+	}
+	return nil
+}
+
+func validateEnvironment(environment []EnvVar) error {
+	for _, e := range environment {
+		if e.ValueFrom == nil {
+			continue
+		}
+		if e.Name == "" {
+			return fmt.Errorf("'environment' variables with 'valueFrom' must have a name")
+		}
+		if e.Value != "" {
+			return fmt.Errorf("'environment' variable '%s' cannot have both 'value' and 'valueFrom'", e.Name)
+		}
+		ref := e.ValueFrom.SecretKeyRef
+		if ref == nil {
+			ref = e.ValueFrom.ConfigMapKeyRef
+		} else if e.ValueFrom.ConfigMapKeyRef != nil {
+			return fmt.Errorf("'environment' variable '%s' cannot have both 'secretKeyRef' and 'configMapKeyRef'", e.Name)
+		}
+		if ref == nil {
+			return fmt.Errorf("'environment' variable '%s' must have a 'secretKeyRef' or a 'configMapKeyRef'", e.Name)
+		}
+		if ref.Name == "" || ref.Key == "" {
+			return fmt.Errorf("'environment' variable '%s' must reference a 'name' and a 'key'", e.Name)
+		}
+	}
+	return nil
+}
+
+//validateBuildArgs rejects build args with 'valueFrom', they are resolved before the development container exists
+func validateBuildArgs(builds ...*BuildInfo) error {
+	for _, b := range builds {
+		if b == nil {
+			continue
+		}
+		for _, e := range b.Args {
+			if e.ValueFrom != nil {
+				return fmt.Errorf("build arg '%s' cannot use 'valueFrom': it's only supported in 'environment'", e.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateContainerSuffix(suffix string) error {
+	if suffix == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label("a" + suffix); len(errs) > 0 {
+		return fmt.Errorf("'containerSuffix' value '%s' is not valid: %s", suffix, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func validateTailLogs(containers []string) error {
+	seen := map[string]bool{}
+	for _, container := range containers {
+		if errs := validation.IsDNS1123Label(container); len(errs) > 0 {
+			return fmt.Errorf("'tailLogs' container '%s' is not valid: %s", container, strings.Join(errs, ", "))
+		}
+		if seen[container] {
+			return fmt.Errorf("'tailLogs' container '%s' is duplicated", container)
+		}
+		seen[container] = true
+	}
+	return nil
+}
+
+func validateTopologySpread(t *TopologySpread) error {
+	if t == nil {
+		return nil
+	}
+	switch t.WhenUnsatisfiable {
+	case "", apiv1.DoNotSchedule, apiv1.ScheduleAnyway:
+		return nil
+	default:
+		return fmt.Errorf("supported values for 'topologySpread.whenUnsatisfiable' are: 'DoNotSchedule' or 'ScheduleAnyway'")
+	}
+}
+
+func validatePodLabels(podLabels map[string]string) error {
+	for k, v := range podLabels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("'podLabels' key '%s' is not valid: %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("'podLabels' value '%s' is not valid: %s", v, strings.Join(errs, ", "))
+		}
+		if k == labels.DevLabel || k == labels.InteractiveDevLabel || k == labels.DetachedDevLabel || strings.HasPrefix(k, labels.DevLabel+"/") {
+			return fmt.Errorf("'podLabels' cannot override the okteto label '%s'", k)
+		}
+	}
+	return nil
+}
+
 func validatePullPolicy(pullPolicy apiv1.PullPolicy) error {
 	switch pullPolicy {
 	case apiv1.PullAlways:
 	case apiv1.PullIfNotPresent:
 	case apiv1.PullNever:
+	case PullAuto:
+	default:
+		return fmt.Errorf("supported values for 'imagePullPolicy' are: 'Always', 'IfNotPresent', 'Never' or 'Auto'")
+	}
+	return nil
+}
+
+func validateSecurityContext(s *SecurityContext) error {
+	if s == nil {
+		return nil
+	}
+	if s.LocalUser && (s.RunAsUser != nil || s.RunAsGroup != nil) {
+		return fmt.Errorf("'securityContext.localUser' cannot be combined with 'securityContext.runAsUser' or 'securityContext.runAsGroup'")
+	}
+	if s.Privileged != nil && *s.Privileged && s.AllowPrivilegeEscalation != nil && !*s.AllowPrivilegeEscalation {
+		return fmt.Errorf("'securityContext.allowPrivilegeEscalation' cannot be false when 'securityContext.privileged' is true")
+	}
+	if s.FSGroupChangePolicy == nil {
+		return nil
+	}
+	switch *s.FSGroupChangePolicy {
+	case apiv1.FSGroupChangeAlways:
+	case apiv1.FSGroupChangeOnRootMismatch:
 	default:
-		return fmt.Errorf("supported values for 'imagePullPolicy' are: 'Always', 'IfNotPresent' or 'Never'")
+		return fmt.Errorf("supported values for 'securityContext.fsGroupChangePolicy' are: 'Always' or 'OnRootMismatch'")
 	}
 	return nil
 }
@@ -646,6 +1750,30 @@ func validateSecrets(secrets []Secret) error {
 	return nil
 }
 
+func validateBinPaths(binPath, initPath string) error {
+	if !strings.HasPrefix(binPath, "/") {
+		return fmt.Errorf("'binPath' must be an absolute path")
+	}
+	if !strings.HasPrefix(initPath, "/") {
+		return fmt.Errorf("'initContainer.mountPath' must be an absolute path")
+	}
+	return nil
+}
+
+func validateSockets(sockets []SocketForward) error {
+	seen := map[string]bool{}
+	for _, s := range sockets {
+		if !strings.HasPrefix(s.Remote, "/") {
+			return fmt.Errorf("relative remote paths are not supported in the field 'sockets'")
+		}
+		if seen[s.Local] {
+			return fmt.Errorf("local socket '%s' is listed multiple times in the field 'sockets'", s.Local)
+		}
+		seen[s.Local] = true
+	}
+	return nil
+}
+
 //LoadRemote configures remote execution
 func (dev *Dev) LoadRemote(pubKeyPath string) {
 	if dev.RemotePort == 0 {
@@ -704,6 +1832,22 @@ func (dev *Dev) Save(path string) error {
 	return nil
 }
 
+//RecoveryGracePeriodSeconds returns the grace period used to destroy the development container when okteto up recovers from a failure
+func (dev *Dev) RecoveryGracePeriodSeconds() int64 {
+	if dev.GracePeriod == nil {
+		return 0
+	}
+	return dev.GracePeriod.Recovery
+}
+
+//IsSafeToEvict returns true if the cluster autoscaler can evict the development container when scaling down nodes
+func (dev *Dev) IsSafeToEvict() bool {
+	if dev.SafeToEvict == nil {
+		return false
+	}
+	return *dev.SafeToEvict
+}
+
 //SerializeBuildArgs returns build  aaargs as a llist of strings
 func SerializeBuildArgs(buildArgs []EnvVar) []string {
 	result := []string{}
@@ -716,6 +1860,16 @@ func SerializeBuildArgs(buildArgs []EnvVar) []string {
 	return result
 }
 
+//SerializeBuildSecrets returns the build secrets in the format expected by buildkit, sorted by id
+func SerializeBuildSecrets(secrets map[string]string) []string {
+	result := []string{}
+	for id, src := range secrets {
+		result = append(result, fmt.Sprintf("id=%s,src=%s", id, src))
+	}
+	sort.Strings(result)
+	return result
+}
+
 //SetLastBuiltAnnotation sets the dev timestacmp
 func (dev *Dev) SetLastBuiltAnnotation() {
 	if dev.Annotations == nil {
@@ -724,6 +1878,26 @@ func (dev *Dev) SetLastBuiltAnnotation() {
 	dev.Annotations[labels.LastBuiltAnnotation] = time.Now().UTC().Format(labels.TimeFormat)
 }
 
+//SetBuiltImage sets the image built for the dev container and the services sharing its image.
+//The pull policy is forced to 'Always' so a stale image with the same tag cached on the node is never used
+func (dev *Dev) SetBuiltImage(imageTag string) {
+	for _, s := range dev.Services {
+		if s.Image.Name == dev.Image.Name {
+			s.setBuiltImage(imageTag)
+		}
+	}
+	dev.setBuiltImage(imageTag)
+}
+
+func (dev *Dev) setBuiltImage(imageTag string) {
+	if dev.ImagePullPolicy != apiv1.PullAlways {
+		log.Infof("forcing pull policy 'Always' for the dev image '%s' of '%s'", imageTag, dev.Name)
+	}
+	dev.Image.Name = imageTag
+	dev.ImagePullPolicy = apiv1.PullAlways
+	dev.SetLastBuiltAnnotation()
+}
+
 //GetVolumeName returns the okteto volume name for a given development container
 func (dev *Dev) GetVolumeName() string {
 	return fmt.Sprintf(OktetoVolumeNameTemplate, dev.Name)
@@ -742,22 +1916,62 @@ func (dev *Dev) LabelsSelector() string {
 	return labels
 }
 
+// GetServiceAccountName returns the name of the service account created for the development container
+func (dev *Dev) GetServiceAccountName() string {
+	return fmt.Sprintf("okteto-%s", dev.Name)
+}
+
+// ToTolerations translates the dev tolerations into pod tolerations
+func (dev *Dev) ToTolerations() []apiv1.Toleration {
+	if len(dev.Tolerations) == 0 {
+		return nil
+	}
+	result := []apiv1.Toleration{}
+	for _, t := range dev.Tolerations {
+		toleration := apiv1.Toleration{
+			Key:               t.Key,
+			Operator:          t.Operator,
+			Value:             t.Value,
+			Effect:            t.Effect,
+			TolerationSeconds: t.TolerationSeconds,
+		}
+		result = append(result, toleration)
+	}
+	return result
+}
+
 // ToTranslationRule translates a dev struct into a translation rule
 func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 	rule := &TranslationRule{
-		Container:        dev.Container,
-		ImagePullPolicy:  dev.ImagePullPolicy,
-		Environment:      dev.Environment,
-		Secrets:          dev.Secrets,
-		WorkDir:          dev.WorkDir,
-		PersistentVolume: main.PersistentVolumeEnabled(),
-		Volumes:          []VolumeMount{},
-		SecurityContext:  dev.SecurityContext,
-		ServiceAccount:   dev.ServiceAccount,
-		Resources:        dev.Resources,
-		Healthchecks:     dev.Healthchecks,
-		InitContainer:    dev.InitContainer,
-		Probes:           dev.Probes,
+		Container:             dev.Container,
+		ImagePullPolicy:       dev.ImagePullPolicy,
+		ImageDigest:           dev.ImageDigest,
+		RegistryRewrites:      main.RegistryRewrites,
+		Environment:           dev.Environment,
+		Secrets:               dev.Secrets,
+		WorkDir:               dev.WorkDir,
+		PersistentVolume:      main.PersistentVolumeEnabled(),
+		Volumes:               []VolumeMount{},
+		SecurityContext:       dev.SecurityContext,
+		ServiceAccount:        dev.ServiceAccount,
+		ShareProcessNamespace: dev.ShareProcessNamespace,
+		RuntimeMemoryFlags:    dev.RuntimeMemoryFlags,
+		ContainerPorts:        dev.ContainerPorts,
+		InitContainerVolumes:  dev.InitContainerVolumes,
+		ProjectedVolumes:      dev.ProjectedVolumes,
+		PodLabels:             dev.PodLabels,
+		ReadinessGates:        dev.ReadinessGates,
+		TopologySpread:        dev.TopologySpread,
+		Hostname:              dev.Hostname,
+		Subdomain:             dev.Subdomain,
+		DNSConfig:             dev.DNSConfig,
+		Resources:             dev.Resources,
+		SidecarResources:      dev.SidecarResources,
+		Healthchecks:          dev.Healthchecks,
+		InitContainer:         dev.InitContainer,
+		Probes:                dev.Probes,
+		LivenessGracePeriod:   dev.LivenessGracePeriod,
+		LivenessFailureAction: dev.LivenessFailureAction,
 	}
 
 	if !dev.EmptyImage {
@@ -774,6 +1988,35 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 	if main == dev {
 		rule.Marker = OktetoBinImageTag //for backward compatibility
 		rule.OktetoBinImageTag = OktetoBinImageTag
+		rule.ContainerProbes = dev.ContainerProbes
+		rule.BinPath = main.BinPath
+		rule.Overlays = dev.Overlays
+		rule.LogFifo = dev.LogFifo
+		rule.OpenTelemetry = dev.OpenTelemetry
+		rule.WaitFor = dev.WaitFor
+		rule.Hydrate = dev.PersistentVolumeHydrate()
+		rule.ContainerSuffix = dev.ContainerSuffix
+		rule.InotifyTuning = dev.InotifyTuning
+		rule.GPUDisabled = dev.GPUDisabled
+		rule.StdinOnce = dev.StdinOnce
+		if dev.GracePeriod != nil && dev.GracePeriod.Dev != nil {
+			rule.GracePeriodSeconds = dev.GracePeriod.Dev
+		}
+		if dev.SharedCache != nil {
+			for _, p := range dev.SharedCache.Paths {
+				rule.Volumes = append(
+					rule.Volumes,
+					VolumeMount{
+						Name:      OktetoSharedCacheVolumeName,
+						MountPath: p,
+						SubPath:   getSharedCacheSubPath(p),
+					},
+				)
+			}
+		}
+		if dev.CreateServiceAccount != nil {
+			rule.ServiceAccount = dev.GetServiceAccountName()
+		}
 		rule.Environment = append(
 			rule.Environment,
 			EnvVar{
@@ -786,6 +2029,10 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 			},
 		)
 
+		if dev.OpenTelemetry != nil {
+			rule.Environment = append(rule.Environment, dev.getOpenTelemetryEnvVars()...)
+		}
+
 		// We want to minimize environment mutations, so only reconfigure the SSH
 		// server port if a non-default is specified.
 		if dev.SSHServerPort != oktetoDefaultSSHServerPort {
@@ -815,7 +2062,10 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 				},
 			)
 		}
-		rule.Command = []string{"/var/okteto/bin/start.sh"}
+		rule.Command = []string{path.Join(main.BinPath, "start.sh")}
+		if main.InitContainer.InjectShell {
+			rule.Command = []string{path.Join(main.BinPath, OktetoShellName), path.Join(main.BinPath, "start.sh")}
+		}
 		if main.RemoteModeEnabled() {
 			rule.Args = []string{"-r"}
 		} else {
@@ -827,9 +2077,14 @@ func (dev *Dev) ToTranslationRule(main *Dev) *TranslationRule {
 		if !main.PersistentVolumeEnabled() {
 			rule.Args = append(rule.Args, "-e")
 		}
-	} else if len(dev.Command.Values) > 0 {
-		rule.Command = dev.Command.Values
-		rule.Args = []string{}
+	} else {
+		if len(dev.Command.Values) > 0 {
+			rule.Command = dev.Command.Values
+			rule.Args = []string{}
+		}
+		if len(dev.Args.Values) > 0 {
+			rule.Args = dev.Args.Values
+		}
 	}
 
 	if main.PersistentVolumeEnabled() {
@@ -18,10 +18,13 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/okteto/okteto/pkg/k8s/labels"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 )
 
 func Test_LoadDev(t *testing.T) {
@@ -443,12 +446,47 @@ services:
 			}
 
 			if img.Name != tt.want {
-				t.Errorf("got: '%s', expected: '%s'", img, tt.want)
+				t.Errorf("got: '%s', expected: '%s'", img.Name, tt.want)
 			}
 		})
 	}
 }
 
+func Test_loadImageBuildArgsAndSecrets(t *testing.T) {
+	os.Setenv("BUILD_VERSION", "1.2.3")
+	os.Setenv("TOKEN_FILE", "/secrets/token")
+	defer os.Unsetenv("BUILD_VERSION")
+	defer os.Unsetenv("TOKEN_FILE")
+
+	manifest := []byte(`name: deployment
+image:
+  context: .
+  args:
+    - VERSION=$BUILD_VERSION
+  secrets:
+    git_token: $TOKEN_FILE
+    npmrc: .npmrc
+sync:
+  - .:/app`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.loadAbsPaths("/src/okteto.yml"); err != nil {
+		t.Fatal(err)
+	}
+
+	args := SerializeBuildArgs(dev.Image.Args)
+	if !reflect.DeepEqual(args, []string{"VERSION=1.2.3"}) {
+		t.Errorf("wrong build args: %v", args)
+	}
+	secrets := SerializeBuildSecrets(dev.Image.Secrets)
+	expected := []string{"id=git_token,src=/secrets/token", "id=npmrc,src=" + filepath.Join("/src", ".npmrc")}
+	if !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("wrong build secrets: %v", secrets)
+	}
+}
+
 func TestDev_validateName(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -615,6 +653,142 @@ func Test_LoadForcePull(t *testing.T) {
 	}
 }
 
+func Test_SetBuiltImage(t *testing.T) {
+	manifest := []byte(`
+  name: a
+  image: okteto/app:1.0.0
+  imagePullPolicy: IfNotPresent
+  services:
+    - name: b
+      image: okteto/app:1.0.0
+      imagePullPolicy: IfNotPresent
+    - name: c
+      image: okteto/other:1.0.0
+      imagePullPolicy: IfNotPresent`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev.SetBuiltImage("registry/ns/a:okteto")
+
+	if dev.Image.Name != "registry/ns/a:okteto" {
+		t.Errorf("wrong image for main container: %s", dev.Image.Name)
+	}
+	if dev.ImagePullPolicy != apiv1.PullAlways {
+		t.Errorf("wrong image pull policy for main container: %s", dev.ImagePullPolicy)
+	}
+	if dev.Annotations[labels.LastBuiltAnnotation] == "" {
+		t.Errorf("last built annotation not set for main container")
+	}
+
+	s := dev.Services[0]
+	if s.Image.Name != "registry/ns/a:okteto" {
+		t.Errorf("wrong image for service sharing the dev image: %s", s.Image.Name)
+	}
+	if s.ImagePullPolicy != apiv1.PullAlways {
+		t.Errorf("wrong image pull policy for service sharing the dev image: %s", s.ImagePullPolicy)
+	}
+
+	s = dev.Services[1]
+	if s.Image.Name != "okteto/other:1.0.0" {
+		t.Errorf("wrong image for service with its own image: %s", s.Image.Name)
+	}
+	if s.ImagePullPolicy != apiv1.PullIfNotPresent {
+		t.Errorf("wrong image pull policy for service with its own image: %s", s.ImagePullPolicy)
+	}
+}
+
+func Test_ToTolerations(t *testing.T) {
+	manifest := []byte(`
+  name: a
+  tolerations:
+    - key: spot
+      operator: Exists
+      effect: NoExecute
+      tolerationSeconds: 600
+    - key: preemptible
+      operator: Exists
+      effect: NoExecute
+    - key: nvidia/gpu
+      operator: Exists
+      effect: NoSchedule`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tolerations := dev.ToTolerations()
+	if len(tolerations) != 3 {
+		t.Fatalf("wrong number of tolerations: %d", len(tolerations))
+	}
+	if tolerations[0].Key != "spot" || tolerations[0].Operator != apiv1.TolerationOpExists || tolerations[0].Effect != apiv1.TaintEffectNoExecute {
+		t.Errorf("wrong toleration: %+v", tolerations[0])
+	}
+	if tolerations[0].TolerationSeconds == nil || *tolerations[0].TolerationSeconds != 600 {
+		t.Errorf("tolerationSeconds not set: %+v", tolerations[0])
+	}
+	if tolerations[1].TolerationSeconds != nil {
+		t.Errorf("tolerationSeconds set for a NoExecute toleration that tolerates the taint forever: %+v", tolerations[1])
+	}
+	if tolerations[2].TolerationSeconds != nil {
+		t.Errorf("tolerationSeconds set for NoSchedule toleration: %+v", tolerations[2])
+	}
+}
+
+func Test_CreateServiceAccount(t *testing.T) {
+	manifest := []byte(`
+  name: api
+  createServiceAccount:
+    roleBindings:
+      - name: view
+  services:
+    - name: worker`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dev.CreateServiceAccount.RoleBindings[0].Kind != ClusterRoleKind {
+		t.Errorf("wrong default role binding kind: %s", dev.CreateServiceAccount.RoleBindings[0].Kind)
+	}
+
+	rule := dev.ToTranslationRule(dev)
+	if rule.ServiceAccount != "okteto-api" {
+		t.Errorf("wrong service account for main container: '%s'", rule.ServiceAccount)
+	}
+
+	rule = dev.Services[0].ToTranslationRule(dev)
+	if rule.ServiceAccount != "" {
+		t.Errorf("wrong service account for services: '%s'", rule.ServiceAccount)
+	}
+}
+
+func Test_ContainerProbesOnlyInMainRule(t *testing.T) {
+	manifest := []byte(`
+  name: api
+  containerProbes:
+    api:
+      liveness: true
+  services:
+    - name: worker
+      container: api`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := dev.ToTranslationRule(dev)
+	if _, ok := rule.ContainerProbes["api"]; !ok {
+		t.Errorf("container probes not set for main container: %+v", rule.ContainerProbes)
+	}
+
+	rule = dev.Services[0].ToTranslationRule(dev)
+	if len(rule.ContainerProbes) > 0 {
+		t.Errorf("container probes set for services: %+v", rule.ContainerProbes)
+	}
+}
+
 func Test_validate(t *testing.T) {
 	file, err := ioutil.TempFile("/tmp", "okteto-secret-test")
 	if err != nil {
@@ -790,6 +964,15 @@ func Test_validate(t *testing.T) {
       imagePullPolicy: IfNotPresent`),
 			expectErr: false,
 		},
+		{
+			name: "auto-pull-policy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      imagePullPolicy: Auto`),
+			expectErr: false,
+		},
 		{
 			name: "subpath-on-main-dev",
 			manifest: []byte(`
@@ -817,110 +1000,1573 @@ func Test_validate(t *testing.T) {
       sshServerPort: -1`),
 			expectErr: true,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			dev, err := Read(tt.manifest)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			err = dev.validate()
-			if tt.expectErr && err == nil {
-				t.Error("didn't got the expected error")
-			}
-
-			if !tt.expectErr && err != nil {
-				t.Errorf("got an unexpected error: %s", err)
-			}
-
-		})
-	}
-}
-func TestPersistentVolumeEnabled(t *testing.T) {
-	var tests = []struct {
-		name     string
-		manifest []byte
-		expected bool
-	}{
 		{
-			name: "default",
+			name: "valid-sockets",
 			manifest: []byte(`
       name: deployment
-      container: core
-      image: code/core:0.1.8`),
-			expected: true,
+      sync:
+        - .:/app
+      sockets:
+        - /tmp/docker.sock:/var/run/docker.sock`),
+			expectErr: false,
 		},
 		{
-			name: "set",
+			name: "sockets-relative-remote-path",
 			manifest: []byte(`
       name: deployment
-      container: core
-      image: code/core:0.1.8
-      persistentVolume:
-        enabled: true`),
-			expected: true,
+      sync:
+        - .:/app
+      sockets:
+        - /tmp/docker.sock:docker.sock`),
+			expectErr: true,
 		},
 		{
-			name: "disabled",
+			name: "duplicated-local-sockets",
 			manifest: []byte(`
       name: deployment
-      container: core
-      image: code/core:0.1.8
-      persistentVolume:
-        enabled: false`),
-			expected: false,
+      sync:
+        - .:/app
+      sockets:
+        - /tmp/docker.sock:/var/run/docker.sock
+        - /tmp/docker.sock:/var/run/other.sock`),
+			expectErr: true,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			dev, err := Read(tt.manifest)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			if dev.PersistentVolumeEnabled() != tt.expected {
-				t.Errorf("Expecting %t but got %t", tt.expected, dev.PersistentVolumeEnabled())
-			}
-		})
-	}
-}
-
-func Test_ExpandEnv(t *testing.T) {
-	os.Setenv("BAR", "bar")
-	tests := []struct {
-		name   string
-		value  string
-		result string
-	}{
 		{
-			name:   "no-var",
-			value:  "value",
-			result: "value",
+			name: "negative-liveness-grace-period",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      livenessGracePeriod: -1`),
+			expectErr: true,
 		},
 		{
-			name:   "var",
-			value:  "value-${BAR}-value",
-			result: "value-bar-value",
+			name: "relative-bin-path",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      binPath: okteto/bin`),
+			expectErr: true,
 		},
 		{
-			name:   "default",
-			value:  "value-${FOO:-foo}-value",
-			result: "value-foo-value",
+			name: "relative-init-container-mount-path",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      initContainer:
+        mountPath: okteto/bin`),
+			expectErr: true,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := ExpandEnv(tt.value)
-			if err != nil {
-				t.Errorf("error in test '%s': %s", tt.name, err.Error())
-			}
-			if result != tt.result {
-				t.Errorf("error in test '%s': '%s', expected: '%s'", tt.name, result, tt.result)
-			}
-		})
+		{
+			name: "valid-overlays",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      overlays:
+        - /app/build`),
+			expectErr: false,
+		},
+		{
+			name: "overlay-outside-sync-folder",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      overlays:
+        - /tmp/build`),
+			expectErr: true,
+		},
+		{
+			name: "overlay-on-sync-folder",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      overlays:
+        - /app`),
+			expectErr: true,
+		},
+		{
+			name: "duplicated-overlays",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      overlays:
+        - /app/build
+        - /app/build`),
+			expectErr: true,
+		},
+		{
+			name: "negative-wait-for-service",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      waitForService: -1`),
+			expectErr: true,
+		},
+		{
+			name: "services-with-post-sync",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      postSync: make deps
+      services:
+        - name: foo
+          postSync: make deps
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "create-service-account",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      createServiceAccount:
+        roleBindings:
+          - name: view
+          - kind: Role
+            name: reader`),
+			expectErr: false,
+		},
+		{
+			name: "create-service-account-with-service-account",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      serviceAccount: sa
+      createServiceAccount:
+        roleBindings:
+          - name: view`),
+			expectErr: true,
+		},
+		{
+			name: "create-service-account-wrong-kind",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      createServiceAccount:
+        roleBindings:
+          - kind: Group
+            name: view`),
+			expectErr: true,
+		},
+		{
+			name: "valid-hostname",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      hostname: web-0
+      subdomain: peers`),
+			expectErr: false,
+		},
+		{
+			name: "wrong-hostname",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      hostname: Web_0`),
+			expectErr: true,
+		},
+		{
+			name: "wrong-subdomain",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      subdomain: peers.svc`),
+			expectErr: true,
+		},
+		{
+			name: "log-fifo",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      logFifo: /var/log/fifo`),
+			expectErr: false,
+		},
+		{
+			name: "relative-log-fifo",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      logFifo: log/fifo`),
+			expectErr: true,
+		},
+		{
+			name: "root-log-fifo",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      logFifo: /app.fifo`),
+			expectErr: true,
+		},
+		{
+			name: "log-fifo-in-sync-folder",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      logFifo: /app/log/fifo`),
+			expectErr: true,
+		},
+		{
+			name: "log-fifo-in-volume",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      volumes:
+        - /var/log
+      logFifo: /var/log/fifo`),
+			expectErr: true,
+		},
+		{
+			name: "services-with-log-fifo",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          logFifo: /var/log/fifo
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "pod-labels",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      podLabels:
+        version: dev
+        sidecar.istio.io/inject: "false"`),
+			expectErr: false,
+		},
+		{
+			name: "pod-labels-okteto-label",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      podLabels:
+        interactive.dev.okteto.com: other`),
+			expectErr: true,
+		},
+		{
+			name: "pod-labels-wrong-value",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      podLabels:
+        version: "dev version"`),
+			expectErr: true,
+		},
+		{
+			name: "fs-group-change-policy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      securityContext:
+        fsGroup: 1000
+        fsGroupChangePolicy: OnRootMismatch`),
+			expectErr: false,
+		},
+		{
+			name: "se-linux-options",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      securityContext:
+        seLinuxOptions:
+          level: "s0:c123,c456"`),
+			expectErr: false,
+		},
+		{
+			name: "privileged",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      securityContext:
+        privileged: true`),
+			expectErr: false,
+		},
+		{
+			name: "privileged-without-privilege-escalation",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      securityContext:
+        privileged: true
+        allowPrivilegeEscalation: false`),
+			expectErr: true,
+		},
+		{
+			name: "wrong-fs-group-change-policy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      securityContext:
+        fsGroupChangePolicy: Never`),
+			expectErr: true,
+		},
+		{
+			name: "skip-pod-affinity",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      skipPodAffinity: true
+      services:
+        - name: worker
+          sync:
+            - .:/src`),
+			expectErr: false,
+		},
+		{
+			name: "skip-pod-affinity-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          skipPodAffinity: true
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "skip-pod-affinity-with-service-volumes",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      skipPodAffinity: true
+      services:
+        - name: worker
+          volumes:
+            - /cache
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "open-telemetry",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      openTelemetry: {}`),
+			expectErr: false,
+		},
+		{
+			name: "open-telemetry-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          openTelemetry: {}
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "wrong-persistent-volume-binding-timeout",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      persistentVolume:
+        enabled: true
+        bindingTimeout: -1`),
+			expectErr: true,
+		},
+		{
+			name: "container-ports",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      containerPorts:
+        - name: http
+          containerPort: 8080
+        - containerPort: 8080
+          protocol: UDP`),
+			expectErr: false,
+		},
+		{
+			name: "container-ports-wrong-port",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      containerPorts:
+        - containerPort: 0`),
+			expectErr: true,
+		},
+		{
+			name: "container-ports-duplicated-port",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      containerPorts:
+        - containerPort: 8080
+        - containerPort: 8080
+          protocol: TCP`),
+			expectErr: true,
+		},
+		{
+			name: "container-ports-wrong-name",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      containerPorts:
+        - name: Not_Valid
+          containerPort: 8080`),
+			expectErr: true,
+		},
+		{
+			name: "wrong-ssh-keep-alive-interval",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      sshKeepAliveInterval: -1`),
+			expectErr: true,
+		},
+		{
+			name: "wrong-forward-concurrency",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      forwardConcurrency: -1`),
+			expectErr: true,
+		},
+		{
+			name: "wait-for",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      waitFor:
+        - host: postgres.db.svc
+          port: 5432
+          timeout: 30
+        - host: 10.0.0.1
+          port: 6379`),
+			expectErr: false,
+		},
+		{
+			name: "wait-for-wrong-host",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      waitFor:
+        - host: "db; rm -rf /"
+          port: 5432`),
+			expectErr: true,
+		},
+		{
+			name: "wait-for-wrong-port",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      waitFor:
+        - host: db`),
+			expectErr: true,
+		},
+		{
+			name: "wait-for-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          waitFor:
+            - host: db
+              port: 5432
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "active-deadline-seconds",
+			manifest: []byte(`
+      name: deployment
+      activeDeadlineSeconds: 3600
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "zero-active-deadline-seconds",
+			manifest: []byte(`
+      name: deployment
+      activeDeadlineSeconds: 0
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "active-deadline-seconds-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          activeDeadlineSeconds: 60
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "readiness-gates",
+			manifest: []byte(`
+      name: deployment
+      readinessGates:
+        strip: true
+        conditionTypes:
+          - example.com/dev-ready
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-readiness-gate",
+			manifest: []byte(`
+      name: deployment
+      readinessGates:
+        conditionTypes:
+          - "not a condition"
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "duplicated-readiness-gate",
+			manifest: []byte(`
+      name: deployment
+      readinessGates:
+        conditionTypes:
+          - example.com/dev-ready
+          - example.com/dev-ready
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "dns-config",
+			manifest: []byte(`
+      name: deployment
+      dnsConfig:
+        merge: true
+        nameservers:
+          - 8.8.8.8
+        searches:
+          - dev.example.com
+        options:
+          - name: ndots
+            value: "2"
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "invalid-dns-nameserver",
+			manifest: []byte(`
+      name: deployment
+      dnsConfig:
+        nameservers:
+          - dns.example.com
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "empty-dns-option-name",
+			manifest: []byte(`
+      name: deployment
+      dnsConfig:
+        options:
+          - value: "2"
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "registry-rewrites",
+			manifest: []byte(`
+      name: deployment
+      registryRewrites:
+        - from: docker.io
+          to: mirror.internal
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "registry-rewrite-with-scheme",
+			manifest: []byte(`
+      name: deployment
+      registryRewrites:
+        - from: docker.io
+          to: https://mirror.internal
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "registry-rewrites-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          registryRewrites:
+            - from: docker.io
+              to: mirror.internal
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "init-container-volumes",
+			manifest: []byte(`
+      name: deployment
+      volumes:
+        - /data
+      initContainerVolumes:
+        - container: seed
+          mountPaths:
+            - /data
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "init-container-volumes-without-container",
+			manifest: []byte(`
+      name: deployment
+      initContainerVolumes:
+        - mountPaths:
+            - /app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "init-container-volumes-relative-path",
+			manifest: []byte(`
+      name: deployment
+      initContainerVolumes:
+        - container: seed
+          mountPaths:
+            - app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "git-annotations-in-services",
+			manifest: []byte(`
+      name: deployment
+      gitAnnotations: true
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          gitAnnotations: true
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "local-user-with-run-as-user",
+			manifest: []byte(`
+      name: deployment
+      securityContext:
+        localUser: true
+        runAsUser: 1000
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "local-user",
+			manifest: []byte(`
+      name: deployment
+      securityContext:
+        localUser: true
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "bad-topology-spread",
+			manifest: []byte(`
+      name: deployment
+      topologySpread:
+        whenUnsatisfiable: Never
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "duplicated-tail-logs",
+			manifest: []byte(`
+      name: deployment
+      tailLogs:
+        - proxy
+        - proxy
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "tail-logs-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: worker
+          tailLogs:
+            - proxy
+          sync:
+            - .:/src`),
+			expectErr: true,
+		},
+		{
+			name: "hydrate-bad-url",
+			manifest: []byte(`
+      name: deployment
+      persistentVolume:
+        enabled: true
+        hydrate:
+          url: s3://bucket/app.tar.gz
+          path: /app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "hydrate-relative-path",
+			manifest: []byte(`
+      name: deployment
+      persistentVolume:
+        enabled: true
+        hydrate:
+          url: https://artifacts.example.com/app.tar.gz
+          path: app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "hydrate-without-persistent-volume",
+			manifest: []byte(`
+      name: deployment
+      persistentVolume:
+        enabled: false
+        hydrate:
+          url: https://artifacts.example.com/app.tar.gz
+          path: /app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "container-suffix",
+			manifest: []byte(`
+      name: deployment
+      containerSuffix: -dev
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "bad-container-suffix",
+			manifest: []byte(`
+      name: deployment
+      containerSuffix: _Dev
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "container-suffix-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          containerSuffix: -dev
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "projected-volumes",
+			manifest: []byte(`
+      name: deployment
+      projectedVolumes:
+        - name: podinfo
+          mountPath: /etc/podinfo
+          sources:
+            - secret:
+                name: app-secret
+            - downwardAPI:
+                items:
+                  - path: name
+                    fieldPath: metadata.name
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "projected-volume-relative-mount-path",
+			manifest: []byte(`
+      name: deployment
+      projectedVolumes:
+        - name: podinfo
+          mountPath: etc/podinfo
+          sources:
+            - configMap:
+                name: app-config
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "projected-volume-multiple-sources-in-one",
+			manifest: []byte(`
+      name: deployment
+      projectedVolumes:
+        - name: podinfo
+          sources:
+            - secret:
+                name: app-secret
+              configMap:
+                name: app-config
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "projected-volume-without-sources",
+			manifest: []byte(`
+      name: deployment
+      projectedVolumes:
+        - name: podinfo
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "shared-cache",
+			manifest: []byte(`
+      name: deployment
+      sharedCache:
+        paths:
+          - /root/go/pkg/mod
+        size: 20Gi
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "shared-cache-relative-path",
+			manifest: []byte(`
+      name: deployment
+      sharedCache:
+        paths:
+          - root/.npm
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "shared-cache-sync-folder",
+			manifest: []byte(`
+      name: deployment
+      sharedCache:
+        paths:
+          - /app
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "shared-cache-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          sharedCache:
+            paths:
+              - /root/.npm
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "image-secret-without-file",
+			manifest: []byte(`
+      name: deployment
+      image:
+        context: .
+        secrets:
+          token: ""
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "liveness-failure-action-ignore",
+			manifest: []byte(`
+      name: deployment
+      livenessFailureAction: ignore
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "bad-liveness-failure-action",
+			manifest: []byte(`
+      name: deployment
+      livenessFailureAction: log
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "bad-liveness-failure-action-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          livenessFailureAction: log
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "backup-exclusion-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          backupExclusion: false
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "args-in-main",
+			manifest: []byte(`
+      name: deployment
+      args: ["--debug"]
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "args-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          args: ["--debug"]
+          sync:
+            - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "bad-inotify-tuning",
+			manifest: []byte(`
+      name: deployment
+      inotifyTuning:
+        maxUserWatches: -1
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "inotify-tuning-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          inotifyTuning: {}
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "gpu-fallback-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: svc
+          gpuFallback: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "sidecar-resources-dev-container",
+			manifest: []byte(`
+      name: deployment
+      container: api
+      sidecarResources:
+        api:
+          limits:
+            cpu: 100m
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "bad-grace-period",
+			manifest: []byte(`
+      name: deployment
+      gracePeriod:
+        recovery: -1
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "stdin-once-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          stdinOnce: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "bad-replicas-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          replicas: 0
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "container-probes-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          containerProbes:
+            foo: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "clean-cutover-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          cleanCutover: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "verify-down-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          verifyDown: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "environment-value-from",
+			manifest: []byte(`
+      name: deployment
+      environment:
+        - name: DB_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: db
+              key: password
+      sync:
+        - .:/app`),
+			expectErr: false,
+		},
+		{
+			name: "environment-value-from-without-key",
+			manifest: []byte(`
+      name: deployment
+      environment:
+        - name: DB_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: db
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "environment-value-from-two-sources",
+			manifest: []byte(`
+      name: deployment
+      environment:
+        - name: DB_PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: db
+              key: password
+            configMapKeyRef:
+              name: db
+              key: password
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "image-args-value-from",
+			manifest: []byte(`
+      name: deployment
+      image:
+        context: .
+        args:
+          - name: TOKEN
+            valueFrom:
+              secretKeyRef:
+                name: db
+                key: token
+      sync:
+        - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "safe-to-evict-in-services",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      services:
+        - name: foo
+          safeToEvict: true
+          sync:
+            - .:/app`),
+			expectErr: true,
+		},
+		{
+			name: "patch-deploy-strategy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      deployStrategy: patch`),
+			expectErr: false,
+		},
+		{
+			name: "wrong-deploy-strategy",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+      deployStrategy: merge`),
+			expectErr: true,
+		},
+		{
+			name: "independent-sync-modes",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - src:/app
+        - config:/config:sendonly`),
+			expectErr: false,
+		},
+		{
+			name: "overlapping-sync-modes",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app
+        - config:/app/config:sendonly`),
+			expectErr: true,
+		},
+		{
+			name: "overlapping-same-sync-modes",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - .:/app:sendonly
+        - config:/app/config:sendonly`),
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = dev.validate()
+			if tt.expectErr && err == nil {
+				t.Error("didn't got the expected error")
+			}
+
+			if !tt.expectErr && err != nil {
+				t.Errorf("got an unexpected error: %s", err)
+			}
+
+		})
+	}
+}
+func TestPersistentVolumeEnabled(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest []byte
+		expected bool
+	}{
+		{
+			name: "default",
+			manifest: []byte(`
+      name: deployment
+      container: core
+      image: code/core:0.1.8`),
+			expected: true,
+		},
+		{
+			name: "set",
+			manifest: []byte(`
+      name: deployment
+      container: core
+      image: code/core:0.1.8
+      persistentVolume:
+        enabled: true`),
+			expected: true,
+		},
+		{
+			name: "disabled",
+			manifest: []byte(`
+      name: deployment
+      container: core
+      image: code/core:0.1.8
+      persistentVolume:
+        enabled: false`),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dev.PersistentVolumeEnabled() != tt.expected {
+				t.Errorf("Expecting %t but got %t", tt.expected, dev.PersistentVolumeEnabled())
+			}
+		})
+	}
+}
+
+func TestSyncOwnershipWarning(t *testing.T) {
+	var tests = []struct {
+		name       string
+		manifest   []byte
+		expectWarn bool
+		runsAsRoot bool
+	}{
+		{
+			name: "default-root",
+			manifest: []byte(`
+      name: deployment
+      image: code/core:0.1.8`),
+			expectWarn: false,
+			runsAsRoot: true,
+		},
+		{
+			name: "non-root-with-persistent-volume",
+			manifest: []byte(`
+      name: deployment
+      image: code/core:0.1.8
+      securityContext:
+        runAsUser: 1000`),
+			expectWarn: false,
+			runsAsRoot: false,
+		},
+		{
+			name: "non-root-without-persistent-volume",
+			manifest: []byte(`
+      name: deployment
+      image: code/core:0.1.8
+      securityContext:
+        runAsUser: 1000
+      persistentVolume:
+        enabled: false`),
+			expectWarn: true,
+			runsAsRoot: false,
+		},
+		{
+			name: "non-root-with-fsgroup",
+			manifest: []byte(`
+      name: deployment
+      image: code/core:0.1.8
+      securityContext:
+        runAsUser: 1000
+        fsGroup: 1000
+      persistentVolume:
+        enabled: false`),
+			expectWarn: false,
+			runsAsRoot: false,
+		},
+		{
+			name: "unknown-user",
+			manifest: []byte(`
+      name: deployment
+      image: code/core:0.1.8
+      persistentVolume:
+        enabled: false`),
+			expectWarn: false,
+			runsAsRoot: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			warning := dev.SyncOwnershipWarning()
+			if tt.expectWarn && warning == "" {
+				t.Errorf("expected a warning")
+			}
+			if !tt.expectWarn && warning != "" {
+				t.Errorf("unexpected warning: %s", warning)
+			}
+			if dev.RunsAsRoot() != tt.runsAsRoot {
+				t.Errorf("expected runs as root %t, got %t", tt.runsAsRoot, dev.RunsAsRoot())
+			}
+		})
+	}
+}
+
+func TestRewriteImage(t *testing.T) {
+	rewrites := []RegistryRewrite{
+		{From: "docker.io", To: "mirror.internal"},
+		{From: "docker.io/okteto/", To: "mirror.internal/okteto-mirror/"},
+		{From: "localhost:5000", To: "registry.internal"},
+	}
+	var tests = []struct {
+		name     string
+		image    string
+		expected string
+	}{
+		{name: "official-image", image: "python:3", expected: "mirror.internal/library/python:3"},
+		{name: "docker-hub-image", image: "bitnami/redis", expected: "mirror.internal/bitnami/redis"},
+		{name: "explicit-docker-hub", image: "docker.io/bitnami/redis:6", expected: "mirror.internal/bitnami/redis:6"},
+		{name: "longest-prefix", image: "okteto/bin:1.2.24", expected: "mirror.internal/okteto-mirror/bin:1.2.24"},
+		{name: "registry-with-port", image: "localhost:5000/app@sha256:abc", expected: "registry.internal/app@sha256:abc"},
+		{name: "no-match", image: "gcr.io/project/app", expected: "gcr.io/project/app"},
+		{name: "partial-match", image: "docker.iox/app", expected: "docker.iox/app"},
+		{name: "empty", image: "", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := RewriteImage(tt.image, rewrites); result != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_ExpandEnv(t *testing.T) {
+	os.Setenv("BAR", "bar")
+	tests := []struct {
+		name   string
+		value  string
+		result string
+	}{
+		{
+			name:   "no-var",
+			value:  "value",
+			result: "value",
+		},
+		{
+			name:   "var",
+			value:  "value-${BAR}-value",
+			result: "value-bar-value",
+		},
+		{
+			name:   "default",
+			value:  "value-${FOO:-foo}-value",
+			result: "value-foo-value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExpandEnv(tt.value)
+			if err != nil {
+				t.Errorf("error in test '%s': %s", tt.name, err.Error())
+			}
+			if result != tt.result {
+				t.Errorf("error in test '%s': '%s', expected: '%s'", tt.name, result, tt.result)
+			}
+		})
+	}
+}
+
+func TestGracePeriodByShutdownPath(t *testing.T) {
+	var tests = []struct {
+		name             string
+		manifest         []byte
+		expectedDev      *int64
+		expectedRecovery int64
+	}{
+		{
+			name: "default",
+			manifest: []byte(`name: deployment
+sync:
+  - .:/app`),
+			expectedDev:      nil,
+			expectedRecovery: 0,
+		},
+		{
+			name: "custom",
+			manifest: []byte(`name: deployment
+gracePeriod:
+  dev: 30
+  recovery: 2
+sync:
+  - .:/app`),
+			expectedDev:      pointer.Int64Ptr(30),
+			expectedRecovery: 2,
+		},
+		{
+			name: "recovery",
+			manifest: []byte(`name: deployment
+gracePeriod:
+  recovery: 2
+sync:
+  - .:/app`),
+			expectedDev:      nil,
+			expectedRecovery: 2,
+		},
+		{
+			name: "zero-dev",
+			manifest: []byte(`name: deployment
+gracePeriod:
+  dev: 0
+sync:
+  - .:/app`),
+			expectedDev:      pointer.Int64Ptr(0),
+			expectedRecovery: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := Read(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := dev.ToTranslationRule(dev)
+			if !reflect.DeepEqual(rule.GracePeriodSeconds, tt.expectedDev) {
+				t.Errorf("wrong dev grace period: expected %v, got %v", tt.expectedDev, rule.GracePeriodSeconds)
+			}
+			if result := dev.RecoveryGracePeriodSeconds(); result != tt.expectedRecovery {
+				t.Errorf("wrong recovery grace period: expected %d, got %d", tt.expectedRecovery, result)
+			}
+		})
+	}
+}
+
+func TestSELinuxOptions(t *testing.T) {
+	manifest := []byte(`
+name: deployment
+sync:
+  - .:/app
+securityContext:
+  seLinuxOptions:
+    type: spc_t
+    level: "s0:c123,c456"`)
+	dev, err := Read(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &apiv1.SELinuxOptions{Type: "spc_t", Level: "s0:c123,c456"}
+	if !reflect.DeepEqual(dev.SecurityContext.SELinuxOptions, expected) {
+		t.Errorf("wrong seLinuxOptions: expected %+v, got %+v", expected, dev.SecurityContext.SELinuxOptions)
 	}
 }
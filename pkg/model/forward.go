@@ -19,7 +19,10 @@ import (
 	"strings"
 )
 
-const malformedPortForward = "Wrong port-forward syntax '%s', must be of the form 'localPort:remotePort' or 'localPort:serviceName:remotePort'"
+const (
+	malformedPortForward   = "Wrong port-forward syntax '%s', must be of the form 'localPort:remotePort' or 'localPort:serviceName:remotePort'"
+	malformedSocketForward = "Wrong socket-forward syntax '%s', must be of the form 'localSocketPath:remoteSocketPath'"
+)
 
 // Forward represents a port forwarding definition
 type Forward struct {
@@ -130,3 +133,40 @@ func (f *Forward) UnmarshalExtendedForm(unmarshal func(interface{}) error) error
 	}
 	return nil
 }
+
+// SocketForward represents a unix socket forwarding definition
+type SocketForward struct {
+	Local  string `json:"localPath" yaml:"localPath"`
+	Remote string `json:"remotePath" yaml:"remotePath"`
+}
+
+// UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg for socket forwards.
+// It supports the syntax 'localSocketPath:remoteSocketPath'
+func (f *SocketForward) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(raw, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf(malformedSocketForward, raw)
+	}
+
+	f.Local, err = ExpandEnv(parts[0])
+	if err != nil {
+		return err
+	}
+	f.Remote = parts[1]
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (f SocketForward) MarshalYAML() (interface{}, error) {
+	return f.String(), nil
+}
+
+func (f SocketForward) String() string {
+	return fmt.Sprintf("%s:%s", f.Local, f.Remote)
+}
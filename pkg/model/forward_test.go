@@ -224,3 +224,64 @@ func TestForwardExtended_MarshalYAML(t *testing.T) {
 		})
 	}
 }
+
+func TestSocketForward_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expected  SocketForward
+		expectErr bool
+	}{
+		{
+			name:     "basic",
+			data:     "/tmp/docker.sock:/var/run/docker.sock",
+			expected: SocketForward{Local: "/tmp/docker.sock", Remote: "/var/run/docker.sock"},
+		},
+		{
+			name:      "too-little-parts",
+			data:      "/tmp/docker.sock",
+			expectErr: true,
+		},
+		{
+			name:      "too-many-parts",
+			data:      "/tmp/docker.sock:/var/run/docker.sock:/other",
+			expectErr: true,
+		},
+		{
+			name:      "empty-remote",
+			data:      "/tmp/docker.sock:",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result SocketForward
+			if err := yaml.Unmarshal([]byte(tt.data), &result); err != nil {
+				if tt.expectErr {
+					return
+				}
+
+				t.Fatal(err)
+			}
+
+			if tt.expectErr {
+				t.Fatal("didn't got expected error")
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("didn't unmarshal correctly. Actual '%+v', Expected '%+v'", result, tt.expected)
+			}
+
+			out, err := yaml.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			outStr := strings.TrimSuffix(string(out), "\n")
+			if outStr != tt.data {
+				t.Errorf("didn't marshal correctly. Actual '%+v', Expected '%+v'", outStr, tt.data)
+			}
+		})
+	}
+}
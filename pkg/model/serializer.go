@@ -26,12 +26,13 @@ import (
 
 // BuildInfoRaw represents the build info for serialization
 type buildInfoRaw struct {
-	Name       string   `yaml:"name,omitempty"`
-	Context    string   `yaml:"context,omitempty"`
-	Dockerfile string   `yaml:"dockerfile,omitempty"`
-	CacheFrom  []string `yaml:"cache_from,omitempty"`
-	Target     string   `yaml:"target,omitempty"`
-	Args       []EnvVar `yaml:"args,omitempty"`
+	Name       string            `yaml:"name,omitempty"`
+	Context    string            `yaml:"context,omitempty"`
+	Dockerfile string            `yaml:"dockerfile,omitempty"`
+	CacheFrom  []string          `yaml:"cache_from,omitempty"`
+	Target     string            `yaml:"target,omitempty"`
+	Args       []EnvVar          `yaml:"args,omitempty"`
+	Secrets    map[string]string `yaml:"secrets,omitempty"`
 }
 
 type syncRaw struct {
@@ -47,6 +48,13 @@ type storageResourceRaw struct {
 	Class string   `json:"class,omitempty" yaml:"class,omitempty"`
 }
 
+// envVarRaw represents an environment variable with a value source for serialization
+type envVarRaw struct {
+	Name      string        `yaml:"name,omitempty"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *EnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
 // healthCheckProbesRaw represents the healthchecks info for serialization
 type healthCheckProbesRaw struct {
 	Liveness  bool `json:"liveness,omitempty" yaml:"liveness,omitempty"`
@@ -59,6 +67,13 @@ func (e *EnvVar) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw string
 	err := unmarshal(&raw)
 	if err != nil {
+		var envRaw envVarRaw
+		if err := unmarshal(&envRaw); err != nil {
+			return err
+		}
+		e.Name = envRaw.Name
+		e.ValueFrom = envRaw.ValueFrom
+		e.Value, err = ExpandEnv(envRaw.Value)
 		return err
 	}
 
@@ -78,6 +93,9 @@ func (e *EnvVar) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (e EnvVar) MarshalYAML() (interface{}, error) {
+	if e.ValueFrom != nil {
+		return envVarRaw(e), nil
+	}
 	return e.Name + "=" + e.Value, nil
 }
 
@@ -182,6 +200,13 @@ func (buildInfo *BuildInfo) UnmarshalYAML(unmarshal func(interface{}) error) err
 	buildInfo.Dockerfile = rawBuildInfo.Dockerfile
 	buildInfo.Target = rawBuildInfo.Target
 	buildInfo.Args = rawBuildInfo.Args
+	buildInfo.Secrets = rawBuildInfo.Secrets
+	for id, src := range buildInfo.Secrets {
+		buildInfo.Secrets[id], err = ExpandEnv(src)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -199,6 +224,9 @@ func (buildInfo BuildInfo) MarshalYAML() (interface{}, error) {
 	if buildInfo.Args != nil && len(buildInfo.Args) != 0 {
 		return buildInfoRaw(buildInfo), nil
 	}
+	if len(buildInfo.Secrets) != 0 {
+		return buildInfoRaw(buildInfo), nil
+	}
 	return buildInfo.Name, nil
 }
 
@@ -402,15 +430,24 @@ func (s *SyncFolder) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			return err
 		}
 		s.RemotePath = parts[1]
+		for _, mode := range []string{SyncModeSendReceive, SyncModeSendOnly} {
+			if strings.HasSuffix(s.RemotePath, ":"+mode) {
+				s.RemotePath = strings.TrimSuffix(s.RemotePath, ":"+mode)
+				s.Mode = mode
+			}
+		}
 		return nil
 	}
 
-	return fmt.Errorf("each element in the 'sync' field must follow the syntax 'localPath:remotePath'")
+	return fmt.Errorf("each element in the 'sync' field must follow the syntax 'localPath:remotePath[:mode]'")
 }
 
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (s SyncFolder) MarshalYAML() (interface{}, error) {
-	return s.LocalPath + ":" + s.RemotePath, nil
+	if s.Mode == "" {
+		return s.LocalPath + ":" + s.RemotePath, nil
+	}
+	return s.LocalPath + ":" + s.RemotePath + ":" + s.Mode, nil
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
@@ -135,6 +135,24 @@ func TestEnvVarMashalling(t *testing.T) {
 			[]byte(`$UNDEFINED`),
 			EnvVar{Name: "", Value: ""},
 		},
+		{
+			"secret-key-ref",
+			[]byte(`name: DB_PASSWORD
+valueFrom:
+  secretKeyRef:
+    name: db
+    key: password`),
+			EnvVar{Name: "DB_PASSWORD", ValueFrom: &EnvVarSource{SecretKeyRef: &KeySelector{Name: "db", Key: "password"}}},
+		},
+		{
+			"config-map-key-ref",
+			[]byte(`name: LOG_LEVEL
+valueFrom:
+  configMapKeyRef:
+    name: settings
+    key: log-level`),
+			EnvVar{Name: "LOG_LEVEL", ValueFrom: &EnvVarSource{ConfigMapKeyRef: &KeySelector{Name: "settings", Key: "log-level"}}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -153,10 +171,18 @@ func TestEnvVarMashalling(t *testing.T) {
 				t.Errorf("didn't unmarshal correctly. Actual %+v, Expected %+v", result, tt.expected)
 			}
 
-			_, err := yaml.Marshal(&result)
+			b, err := yaml.Marshal(&result)
 			if err != nil {
 				t.Fatal(err)
 			}
+
+			var roundTrip EnvVar
+			if err := yaml.Unmarshal(b, &roundTrip); err != nil {
+				t.Fatal(err)
+			}
+			if result.ValueFrom != nil && !reflect.DeepEqual(roundTrip, result) {
+				t.Errorf("didn't marshal correctly. Actual %+v, Expected %+v", roundTrip, result)
+			}
 		})
 	}
 }
@@ -252,6 +278,11 @@ func TestImageMashalling(t *testing.T) {
 			image:    BuildInfo{Name: "image-name", Context: "path"},
 			expected: "name: image-name\ncontext: path\n",
 		},
+		{
+			name:     "secrets",
+			image:    BuildInfo{Name: "image-name", Secrets: map[string]string{"token": "/tmp/token"}},
+			expected: "name: image-name\nsecrets:\n  token: /tmp/token\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -434,6 +465,51 @@ func TestVolumeMashalling(t *testing.T) {
 	}
 }
 
+func TestSyncFolderMashalling(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected SyncFolder
+	}{
+		{
+			"default",
+			[]byte("src:/app"),
+			SyncFolder{LocalPath: "src", RemotePath: "/app"},
+		},
+		{
+			"sendreceive",
+			[]byte("src:/app:sendreceive"),
+			SyncFolder{LocalPath: "src", RemotePath: "/app", Mode: SyncModeSendReceive},
+		},
+		{
+			"sendonly",
+			[]byte("config:/app/config:sendonly"),
+			SyncFolder{LocalPath: "config", RemotePath: "/app/config", Mode: SyncModeSendOnly},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s SyncFolder
+			if err := yaml.Unmarshal(tt.data, &s); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(s, tt.expected) {
+				t.Errorf("didn't unmarshal correctly. Actual %+v, Expected %+v", s, tt.expected)
+			}
+
+			marshalled, err := yaml.Marshal(&s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(marshalled)) != string(tt.data) {
+				t.Errorf("didn't marshal correctly. Actual %s, Expected %s", marshalled, tt.data)
+			}
+		})
+	}
+}
+
 func TestDevMarshalling(t *testing.T) {
 	tests := []struct {
 		name     string
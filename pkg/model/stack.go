@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
@@ -36,12 +38,21 @@ type Stack struct {
 	Name      string             `yaml:"name"`
 	Namespace string             `yaml:"namespace,omitempty"`
 	Services  map[string]Service `yaml:"services,omitempty"`
+	Helm      *StackHelm         `yaml:"helm,omitempty"`
+}
+
+//StackHelm represents the overrides applied to the helm release of an okteto stack.
+//Stacks are no longer deployed with helm, the overrides only apply when the legacy release is uninstalled
+type StackHelm struct {
+	KeepHistory  bool `yaml:"keep_history,omitempty"`
+	DisableHooks bool `yaml:"disable_hooks,omitempty"`
 }
 
 //Service represents an okteto stack service
 type Service struct {
 	Labels          map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Annotations     map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Namespace       string             `yaml:"namespace,omitempty"`
 	Public          bool               `yaml:"public,omitempty"`
 	Image           string             `yaml:"image"`
 	Build           *BuildInfo         `yaml:"build,omitempty"`
@@ -174,6 +185,19 @@ func (s *Stack) validate() error {
 		if svc.Image == "" {
 			return fmt.Errorf(fmt.Sprintf("Invalid service '%s': image cannot be empty", name))
 		}
+		if svc.Namespace != "" {
+			if errs := validation.IsDNS1123Label(svc.Namespace); len(errs) > 0 {
+				return fmt.Errorf("Invalid namespace '%s' in service '%s': %s", svc.Namespace, name, strings.Join(errs, ", "))
+			}
+		}
+		for _, e := range svc.Environment {
+			if e.ValueFrom != nil {
+				return fmt.Errorf("Invalid environment variable '%s' in service '%s': 'valueFrom' is not supported in stacks", e.Name, name)
+			}
+		}
+		if err := validateBuildArgs(svc.Build); err != nil {
+			return fmt.Errorf("Invalid build in service '%s': %s", name, err)
+		}
 		for _, v := range svc.Volumes {
 			if !strings.HasPrefix(v, "/") {
 				return fmt.Errorf(fmt.Sprintf("Invalid volume '%s' in service '%s': must be an absolute path", v, name))
@@ -212,6 +236,31 @@ func (s *Stack) UpdateNamespace(namespace string) error {
 	return nil
 }
 
+//GetServiceNamespace returns the namespace of a stack service
+func (s *Stack) GetServiceNamespace(name string) string {
+	if svc, ok := s.Services[name]; ok && svc.Namespace != "" {
+		return svc.Namespace
+	}
+	return s.Namespace
+}
+
+//GetNamespaces returns the namespaces of the stack resources, starting with the stack namespace
+func (s *Stack) GetNamespaces() []string {
+	result := []string{s.Namespace}
+	seen := map[string]bool{s.Namespace: true}
+	others := []string{}
+	for name := range s.Services {
+		ns := s.GetServiceNamespace(name)
+		if seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		others = append(others, ns)
+	}
+	sort.Strings(others)
+	return append(result, others...)
+}
+
 //GetLabelSelector returns the label selector for the stack name
 func (s *Stack) GetLabelSelector() string {
 	return fmt.Sprintf("%s=%s", labels.StackNameLabel, s.Name)
@@ -14,6 +14,7 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -132,6 +133,29 @@ services:
 	}
 }
 
+func Test_ReadStackHelm(t *testing.T) {
+	manifest := []byte(`name: voting-app
+helm:
+  keep_history: true
+  disable_hooks: true
+services:
+  vote:
+    image: okteto/vote:1`)
+	s, err := ReadStack(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Helm == nil {
+		t.Fatalf("'helm' was not parsed: %+v", s)
+	}
+	if !s.Helm.KeepHistory {
+		t.Errorf("'helm.keep_history' was not parsed: %+v", s.Helm)
+	}
+	if !s.Helm.DisableHooks {
+		t.Errorf("'helm.disable_hooks' was not parsed: %+v", s.Helm)
+	}
+}
+
 func TestStack_validate(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -191,6 +215,18 @@ func TestStack_validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "bad-service-namespace",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]Service{
+					"name": {
+						Image:     "image",
+						Namespace: "Bad_Namespace",
+					},
+				},
+			},
+		},
 		{
 			name: "volume-bind-mount",
 			stack: &Stack{
@@ -202,6 +238,30 @@ func TestStack_validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "environment-value-from",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]Service{
+					"name": {
+						Image:       "image",
+						Environment: []EnvVar{{Name: "DB_PASSWORD", ValueFrom: &EnvVarSource{SecretKeyRef: &KeySelector{Name: "db", Key: "password"}}}},
+					},
+				},
+			},
+		},
+		{
+			name: "build-args-value-from",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]Service{
+					"name": {
+						Image: "image",
+						Build: &BuildInfo{Args: []EnvVar{{Name: "TOKEN", ValueFrom: &EnvVarSource{SecretKeyRef: &KeySelector{Name: "db", Key: "token"}}}}},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -233,3 +293,27 @@ func Test_validateStackName(t *testing.T) {
 		})
 	}
 }
+
+func TestStack_GetNamespaces(t *testing.T) {
+	s := &Stack{
+		Name:      "name",
+		Namespace: "apps",
+		Services: map[string]Service{
+			"api":    {},
+			"db":     {Namespace: "infra"},
+			"cache":  {Namespace: "infra"},
+			"worker": {Namespace: "apps"},
+			"queue":  {Namespace: "batch"},
+		},
+	}
+	expected := []string{"apps", "batch", "infra"}
+	if got := s.GetNamespaces(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong namespaces: got %v, expected %v", got, expected)
+	}
+	if got := s.GetServiceNamespace("db"); got != "infra" {
+		t.Errorf("wrong namespace for 'db': %s", got)
+	}
+	if got := s.GetServiceNamespace("api"); got != "apps" {
+		t.Errorf("wrong namespace for 'api': %s", got)
+	}
+}
@@ -14,43 +14,101 @@
 package model
 
 import (
+	"fmt"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 )
 
 //Translation represents the information for translating a deployment
 type Translation struct {
-	Interactive bool               `json:"interactive"`
-	Name        string             `json:"name"`
-	Version     string             `json:"version"`
-	Deployment  *appsv1.Deployment `json:"-"`
-	Annotations map[string]string  `json:"annotations,omitempty"`
-	Tolerations []apiv1.Toleration `json:"tolerations,omitempty"`
-	Replicas    int32              `json:"replicas"`
-	Rules       []*TranslationRule `json:"rules"`
+	Interactive     bool               `json:"interactive"`
+	Name            string             `json:"name"`
+	Version         string             `json:"version"`
+	Deployment      *appsv1.Deployment `json:"-"`
+	Annotations     map[string]string  `json:"annotations,omitempty"`
+	GitAnnotations  map[string]string  `json:"gitAnnotations,omitempty"`
+	Tolerations     []apiv1.Toleration `json:"tolerations,omitempty"`
+	Replicas        int32              `json:"replicas"`
+	DevReplicas     *int32             `json:"devReplicas,omitempty"`
+	Paused          bool               `json:"paused,omitempty"`
+	MinReadySeconds int32              `json:"minReadySeconds,omitempty"`
+	HistoryLimit    *int32             `json:"historyLimit,omitempty"`
+	SkipAffinity    bool               `json:"skipAffinity,omitempty"`
+	BackupExclusion bool               `json:"backupExclusion,omitempty"`
+	PreventEviction bool               `json:"preventEviction,omitempty"`
+	Rules           []*TranslationRule `json:"rules"`
+	RecordSteps     bool               `json:"-"`
+	AllowHostPath   bool               `json:"-"`
+	PodSecurity     string             `json:"-"`
+	Steps           []TranslationStep  `json:"-"`
+}
+
+//TranslationStep represents a translation step that changed the pod template of a deployment
+type TranslationStep struct {
+	Name      string
+	Container string
+	Fields    []string
+}
+
+//String returns the description of a translation step
+func (s TranslationStep) String() string {
+	if s.Container == "" {
+		return fmt.Sprintf("%s: %s", s.Name, strings.Join(s.Fields, ", "))
+	}
+	return fmt.Sprintf("%s [%s]: %s", s.Name, s.Container, strings.Join(s.Fields, ", "))
 }
 
 //TranslationRule represents how to apply a container translation in a deployment
 type TranslationRule struct {
-	Marker            string               `json:"marker"`
-	OktetoBinImageTag string               `json:"oktetoBinImageTag"`
-	Node              string               `json:"node,omitempty"`
-	Container         string               `json:"container,omitempty"`
-	Image             string               `json:"image,omitempty"`
-	ImagePullPolicy   apiv1.PullPolicy     `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
-	Environment       []EnvVar             `json:"environment,omitempty"`
-	Secrets           []Secret             `json:"secrets,omitempty"`
-	Command           []string             `json:"command,omitempty"`
-	Args              []string             `json:"args,omitempty"`
-	WorkDir           string               `json:"workdir"`
-	Healthchecks      bool                 `json:"healthchecks" yaml:"healthchecks"`
-	PersistentVolume  bool                 `json:"persistentVolume" yaml:"persistentVolume"`
-	Volumes           []VolumeMount        `json:"volumes,omitempty"`
-	SecurityContext   *SecurityContext     `json:"securityContext,omitempty"`
-	ServiceAccount    string               `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
-	Resources         ResourceRequirements `json:"resources,omitempty"`
-	InitContainer     InitContainer        `json:"initContainers,omitempty"`
-	Probes            *Probes              `json:"probes" yaml:"probes"`
+	Marker                string                   `json:"marker"`
+	OktetoBinImageTag     string                   `json:"oktetoBinImageTag"`
+	Node                  string                   `json:"node,omitempty"`
+	Container             string                   `json:"container,omitempty"`
+	ContainerSuffix       string                   `json:"containerSuffix,omitempty"`
+	Image                 string                   `json:"image,omitempty"`
+	ImagePullPolicy       apiv1.PullPolicy         `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	ImageDigest           string                   `json:"imageDigest,omitempty" yaml:"imageDigest,omitempty"`
+	RegistryRewrites      []RegistryRewrite        `json:"registryRewrites,omitempty" yaml:"registryRewrites,omitempty"`
+	Environment           []EnvVar                 `json:"environment,omitempty"`
+	Secrets               []Secret                 `json:"secrets,omitempty"`
+	Command               []string                 `json:"command,omitempty"`
+	Args                  []string                 `json:"args,omitempty"`
+	WorkDir               string                   `json:"workdir"`
+	Healthchecks          bool                     `json:"healthchecks" yaml:"healthchecks"`
+	PersistentVolume      bool                     `json:"persistentVolume" yaml:"persistentVolume"`
+	Volumes               []VolumeMount            `json:"volumes,omitempty"`
+	InitContainerVolumes  []InitContainerVolume    `json:"initContainerVolumes,omitempty" yaml:"initContainerVolumes,omitempty"`
+	ProjectedVolumes      []ProjectedVolume        `json:"projectedVolumes,omitempty" yaml:"projectedVolumes,omitempty"`
+	SecurityContext       *SecurityContext         `json:"securityContext,omitempty"`
+	ServiceAccount        string                   `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	ShareProcessNamespace bool                     `json:"shareProcessNamespace,omitempty" yaml:"shareProcessNamespace,omitempty"`
+	RuntimeMemoryFlags    bool                     `json:"runtimeMemoryFlags,omitempty" yaml:"runtimeMemoryFlags,omitempty"`
+	ContainerPorts        []ContainerPort          `json:"containerPorts,omitempty" yaml:"containerPorts,omitempty"`
+	PodLabels             map[string]string        `json:"podLabels,omitempty" yaml:"podLabels,omitempty"`
+	ReadinessGates        *ReadinessGates          `json:"readinessGates,omitempty" yaml:"readinessGates,omitempty"`
+	TopologySpread        *TopologySpread          `json:"topologySpread,omitempty" yaml:"topologySpread,omitempty"`
+	Hostname              string                   `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Subdomain             string                   `json:"subdomain,omitempty" yaml:"subdomain,omitempty"`
+	DNSConfig             *DNSConfig               `json:"dnsConfig,omitempty" yaml:"dnsConfig,omitempty"`
+	Resources             ResourceRequirements     `json:"resources,omitempty"`
+	SidecarResources      ContainerResources       `json:"sidecarResources,omitempty" yaml:"sidecarResources,omitempty"`
+	GPUDisabled           bool                     `json:"gpuDisabled,omitempty" yaml:"gpuDisabled,omitempty"`
+	StdinOnce             bool                     `json:"stdinOnce,omitempty" yaml:"stdinOnce,omitempty"`
+	InitContainer         InitContainer            `json:"initContainers,omitempty"`
+	Probes                *Probes                  `json:"probes" yaml:"probes"`
+	ContainerProbes       map[string]Probes        `json:"containerProbes,omitempty" yaml:"containerProbes,omitempty"`
+	LivenessGracePeriod   int32                    `json:"livenessGracePeriod,omitempty" yaml:"livenessGracePeriod,omitempty"`
+	LivenessFailureAction string                   `json:"livenessFailureAction,omitempty" yaml:"livenessFailureAction,omitempty"`
+	BinPath               string                   `json:"binPath,omitempty" yaml:"binPath,omitempty"`
+	Overlays              []string                 `json:"overlays,omitempty" yaml:"overlays,omitempty"`
+	LogFifo               string                   `json:"logFifo,omitempty" yaml:"logFifo,omitempty"`
+	OpenTelemetry         *OpenTelemetry           `json:"openTelemetry,omitempty" yaml:"openTelemetry,omitempty"`
+	WaitFor               []WaitFor                `json:"waitFor,omitempty" yaml:"waitFor,omitempty"`
+	GracePeriodSeconds    *int64                   `json:"gracePeriodSeconds,omitempty" yaml:"gracePeriodSeconds,omitempty"`
+	Hydrate               *PersistentVolumeHydrate `json:"hydrate,omitempty" yaml:"hydrate,omitempty"`
+	InotifyTuning         *InotifyTuning           `json:"inotifyTuning,omitempty" yaml:"inotifyTuning,omitempty"`
 }
 
 //IsMainDevContainer returns true if the translation rule applies to the main dev container of the okteto manifest
@@ -111,8 +111,10 @@ services:
 			},
 		},
 		InitContainer: InitContainer{
-			Image: OktetoBinImageTag,
+			Image:     OktetoBinImageTag,
+			MountPath: OktetoInitBinMountPath,
 		},
+		BinPath: OktetoBinMountPath,
 	}
 
 	marshalled1, _ := yaml.Marshal(rule1)
@@ -15,8 +15,10 @@ package model
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/log"
@@ -95,7 +97,7 @@ func (dev *Dev) translateDeprecatedVolumes() {
 			volumes = append(volumes, v)
 			continue
 		}
-		dev.Sync.Folders = append(dev.Sync.Folders, SyncFolder(v))
+		dev.Sync.Folders = append(dev.Sync.Folders, SyncFolder{LocalPath: v.LocalPath, RemotePath: v.RemotePath})
 	}
 	dev.Volumes = volumes
 }
@@ -159,6 +161,10 @@ func getDataSubPath(path string) string {
 	return filepath.ToSlash(filepath.Join(DataSubPath, path))
 }
 
+func getSharedCacheSubPath(path string) string {
+	return filepath.ToSlash(filepath.Join(SharedCacheSubPath, path))
+}
+
 func (dev *Dev) getSourceSubPath(path string) string {
 	path = path[len(filepath.VolumeName(path)):]
 	rel, err := filepath.Rel(dev.parentSyncFolder, filepath.ToSlash(path))
@@ -176,6 +182,14 @@ func (dev *Dev) PersistentVolumeEnabled() bool {
 	return dev.PersistentVolumeInfo.Enabled
 }
 
+// BackupExclusionEnabled returns true if the volumes of the dev pods must be excluded from backups
+func (dev *Dev) BackupExclusionEnabled() bool {
+	if dev.BackupExclusion == nil {
+		return true
+	}
+	return *dev.BackupExclusion
+}
+
 // PersistentVolumeSize returns the persistent volume size
 func (dev *Dev) PersistentVolumeSize() string {
 	if dev.PersistentVolumeInfo == nil {
@@ -195,6 +209,30 @@ func (dev *Dev) PersistentVolumeStorageClass() string {
 	return dev.PersistentVolumeInfo.StorageClass
 }
 
+// PersistentVolumeBindingTimeout returns how long to wait for the persistent volume to be bound, 0 if not set
+func (dev *Dev) PersistentVolumeBindingTimeout() time.Duration {
+	if dev.PersistentVolumeInfo == nil {
+		return 0
+	}
+	return time.Duration(dev.PersistentVolumeInfo.BindingTimeout) * time.Second
+}
+
+// SharedCacheSize returns the shared cache volume size
+func (dev *Dev) SharedCacheSize() string {
+	if dev.SharedCache == nil || dev.SharedCache.Size == "" {
+		return OktetoDefaultSharedCacheSize
+	}
+	return dev.SharedCache.Size
+}
+
+// PersistentVolumeHydrate returns the artifact used to hydrate the persistent volume, nil if not set
+func (dev *Dev) PersistentVolumeHydrate() *PersistentVolumeHydrate {
+	if dev.PersistentVolumeInfo == nil || !dev.PersistentVolumeEnabled() {
+		return nil
+	}
+	return dev.PersistentVolumeInfo.Hydrate
+}
+
 func (dev *Dev) AreDefaultPersistentVolumeValues() bool {
 	if dev.PersistentVolumeInfo != nil {
 		if dev.PersistentVolumeSize() == OktetoDefaultPVSize && dev.PersistentVolumeStorageClass() == "" && dev.PersistentVolumeEnabled() {
@@ -282,6 +320,34 @@ func (dev *Dev) validateDuplicatedSyncFolders() error {
 	return nil
 }
 
+func (dev *Dev) validateSyncModes() error {
+	for i, sync := range dev.Sync.Folders {
+		switch sync.Mode {
+		case "", SyncModeSendReceive, SyncModeSendOnly:
+		default:
+			return fmt.Errorf("supported sync modes are '%s' and '%s'", SyncModeSendReceive, SyncModeSendOnly)
+		}
+		for _, other := range dev.Sync.Folders[i+1:] {
+			if sync.GetMode() == other.GetMode() {
+				continue
+			}
+			if isSubPath(sync.LocalPath, other.LocalPath) || isSubPath(other.LocalPath, sync.LocalPath) ||
+				isSubPath(sync.RemotePath, other.RemotePath) || isSubPath(other.RemotePath, sync.RemotePath) {
+				return fmt.Errorf("sync folders '%s' and '%s' overlap but have different sync modes", sync, other)
+			}
+		}
+	}
+	return nil
+}
+
+func isSubPath(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil {
+		return false
+	}
+	return !strings.HasPrefix(filepath.ToSlash(rel), "..")
+}
+
 func (dev *Dev) validateServiceSyncFolders(main *Dev) error {
 	for _, sync := range dev.Sync.Folders {
 		_, err := main.IsSubPathFolder(sync.LocalPath)
@@ -312,6 +378,10 @@ func (dev *Dev) validateVolumes(main *Dev) error {
 		return err
 	}
 
+	if err := dev.validateSyncModes(); err != nil {
+		return err
+	}
+
 	if main == nil {
 		return nil
 	}
@@ -330,3 +400,40 @@ func (dev *Dev) validateExternalVolumes() error {
 	}
 	return nil
 }
+
+func (dev *Dev) validateOverlays() error {
+	seen := map[string]bool{}
+	for _, overlay := range dev.Overlays {
+		if seen[overlay] {
+			return fmt.Errorf("duplicated overlay '%s'", overlay)
+		}
+		seen[overlay] = true
+		if !strings.HasPrefix(overlay, "/") {
+			return fmt.Errorf("relative paths are not supported in the field 'overlays'")
+		}
+		if dev.GetSyncFolderForOverlay(overlay) == nil {
+			return fmt.Errorf("overlay '%s' must be a subfolder of the remote path of a sync folder", overlay)
+		}
+	}
+	return nil
+}
+
+//GetSyncFolderForOverlay returns the sync folder that contains an overlay, if any
+func (dev *Dev) GetSyncFolderForOverlay(overlay string) *SyncFolder {
+	overlay = path.Clean(overlay)
+	for i := range dev.Sync.Folders {
+		remotePath := path.Clean(dev.Sync.Folders[i].RemotePath)
+		if strings.HasPrefix(overlay, remotePath+"/") {
+			return &dev.Sync.Folders[i]
+		}
+	}
+	return nil
+}
+
+//GetMode returns the sync mode of a sync folder
+func (s SyncFolder) GetMode() string {
+	if s.Mode == "" {
+		return SyncModeSendReceive
+	}
+	return s.Mode
+}
@@ -15,10 +15,12 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
 
+	"github.com/heroku/docker-registry-client/registry"
 	"github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/client"
 	"github.com/okteto/okteto/pkg/k8s/namespaces"
@@ -26,57 +28,111 @@ import (
 	"github.com/okteto/okteto/pkg/okteto"
 )
 
+//imageConfig represents the platform fields of an image config
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
 //GetImageTagWithDigest returns the image tag diggest
 func GetImageTagWithDigest(ctx context.Context, namespace, imageTag string) (string, error) {
+	c, repoName, tag := getOktetoRegistryClient(ctx, namespace, imageTag)
+	if c == nil {
+		return imageTag, nil
+	}
+	digest, err := c.ManifestDigest(repoName, tag)
+	if err != nil {
+		if strings.Contains(err.Error(), "status=404") {
+			return "", errors.ErrNotFound
+		}
+		return "", fmt.Errorf("error getting image tag diggest: %s", err.Error())
+	}
+	return fmt.Sprintf("%s@%s", repoName, digest.String()), nil
+}
+
+//GetImageArchitecture returns the architecture of an image, or an empty string if the architecture can't be resolved
+func GetImageArchitecture(ctx context.Context, namespace, imageTag string) (string, error) {
+	c, repoName, tag := getOktetoRegistryClient(ctx, namespace, imageTag)
+	if c == nil {
+		return "", nil
+	}
+	return getImageArchitecture(c, repoName, tag)
+}
+
+func getImageArchitecture(c *registry.Registry, repoName, tag string) (string, error) {
+	manifest, err := c.ManifestV2(repoName, tag)
+	if err != nil {
+		return "", fmt.Errorf("error getting image manifest: %s", err.Error())
+	}
+	blob, err := c.DownloadBlob(repoName, manifest.Config.Digest)
+	if err != nil {
+		return "", fmt.Errorf("error getting image config: %s", err.Error())
+	}
+	defer blob.Close()
+
+	config := &imageConfig{}
+	if err := json.NewDecoder(blob).Decode(config); err != nil {
+		return "", fmt.Errorf("error decoding image config: %s", err.Error())
+	}
+	return config.Architecture, nil
+}
+
+//getOktetoRegistryClient returns a client of the okteto registry with the repository and tag of the image, or a nil client if the image is not stored in the okteto registry
+func getOktetoRegistryClient(ctx context.Context, namespace, imageTag string) (*registry.Registry, string, string) {
 	registryURL, err := okteto.GetRegistry()
 	if err != nil {
 		if err != errors.ErrNotLogged {
 			log.Infof("error accessing to okteto registry: %s", err.Error())
 		}
-		return imageTag, nil
+		return nil, "", ""
 	}
 
 	expandedTag, err := ExpandOktetoDevRegistry(ctx, namespace, imageTag)
 	if err != nil {
 		log.Infof("error expanding okteto registry: %s", err.Error())
-		return imageTag, nil
+		return nil, "", ""
 	}
 	if !strings.HasPrefix(expandedTag, registryURL) {
-		return imageTag, nil
+		return nil, "", ""
 	}
 	username := okteto.GetUserID()
 	token, err := okteto.GetToken()
 	if err != nil {
 		log.Infof("error getting token: %s", err.Error())
-		return imageTag, nil
+		return nil, "", ""
 	}
 	u, err := url.Parse(registryURL)
 	if err != nil {
 		log.Infof("error parsing registry url: %s", err.Error())
-		return imageTag, nil
+		return nil, "", ""
 	}
 	u.Scheme = "https"
 	c, err := NewRegistryClient(u.String(), username, token.Token)
 	if err != nil {
 		log.Infof("error creating registry client: %s", err.Error())
-		return imageTag, nil
+		return nil, "", ""
 	}
 
 	repoURL, tag := GetRepoNameAndTag(expandedTag)
 	index := strings.IndexRune(repoURL, '/')
 	if index == -1 {
 		log.Infof("malformed registry url: %s", repoURL)
-		return imageTag, nil
+		return nil, "", ""
 	}
-	repoName := repoURL[index+1:]
-	digest, err := c.ManifestDigest(repoName, tag)
+	return c, repoURL[index+1:], tag
+}
+
+//GetImageDigest returns the digest of an image tag, or an empty string if the digest can't be resolved
+func GetImageDigest(ctx context.Context, namespace, imageTag string) (string, error) {
+	imageWithDigest, err := GetImageTagWithDigest(ctx, namespace, imageTag)
 	if err != nil {
-		if strings.Contains(err.Error(), "status=404") {
-			return "", errors.ErrNotFound
-		}
-		return "", fmt.Errorf("error getting image tag diggest: %s", err.Error())
+		return "", err
 	}
-	return fmt.Sprintf("%s@%s", repoName, digest.String()), nil
+	index := strings.LastIndex(imageWithDigest, "@")
+	if index == -1 {
+		return "", nil
+	}
+	return imageWithDigest[index+1:], nil
 }
 
 //ExpandOktetoDevRegistry translates okteto.dev
@@ -0,0 +1,79 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_getImageArchitecture(t *testing.T) {
+	const configDigest = "sha256:6d3d8e1f4a5b7e04d1c3f1e09f2b7c6a8e1d2f3a4b5c6d7e8f9a0b1c2d3e4f5a"
+	var tests = []struct {
+		name      string
+		config    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "amd64",
+			config:   `{"architecture":"amd64","os":"linux"}`,
+			expected: "amd64",
+		},
+		{
+			name:     "arm64",
+			config:   `{"architecture":"arm64","os":"linux","variant":"v8"}`,
+			expected: "arm64",
+		},
+		{
+			name:      "malformed-config",
+			config:    `{"architecture":`,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v2/test/app/manifests/dev", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+				fmt.Fprintf(w, `{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{"mediaType":"application/vnd.docker.container.image.v1+json","size":%d,"digest":"%s"},"layers":[]}`, len(tt.config), configDigest)
+			})
+			mux.HandleFunc("/v2/test/app/blobs/"+configDigest, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.config)
+			})
+			s := httptest.NewServer(mux)
+			defer s.Close()
+
+			c, err := newFromTransport(s.URL, "", "", http.DefaultTransport)
+			if err != nil {
+				t.Fatal(err)
+			}
+			arch, err := getImageArchitecture(c, "test/app", "dev")
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if arch != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, arch)
+			}
+		})
+	}
+}
@@ -50,13 +50,19 @@ func (f *forward) setDisconnected() {
 	f.c = false
 }
 
-func (f *forward) start(ctx context.Context) {
-	localListener, err := net.Listen("tcp", f.localAddress)
+// listenForward opens the local listener of a forward
+var listenForward = net.Listen
+
+func (f *forward) listen() (net.Listener, error) {
+	localListener, err := listenForward("tcp", f.localAddress)
 	if err != nil {
 		log.Infof("%s -> failed to listen: %s", f.String(), err)
-		return
+		return nil, err
 	}
+	return localListener, nil
+}
 
+func (f *forward) serve(ctx context.Context, localListener net.Listener) {
 	go func() {
 		<-ctx.Done()
 		f.setDisconnected()
@@ -16,37 +16,54 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"net"
 	"runtime"
+	"sort"
+	"strings"
+	"time"
 
 	k8sforward "github.com/okteto/okteto/pkg/k8s/forward"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 )
 
+const defaultForwardConcurrency = 10
+
 // ForwardManager handles the lifecycle of all the forwards
 type ForwardManager struct {
 	localInterface  string
 	remoteInterface string
 	forwards        map[int]*forward
 	reverses        map[int]*reverse
+	sockets         map[string]*socketForward
 	ctx             context.Context
 	sshAddr         string
 	pf              *k8sforward.PortForwardManager
 	pool            *pool
 	namespace       string
+	keepAlive       time.Duration
+	concurrency     int
 }
 
-// NewForwardManager returns a newly initialized instance of ForwardManager
-func NewForwardManager(ctx context.Context, sshAddr, localInterface, remoteInterface string, pf *k8sforward.PortForwardManager, namespace string) *ForwardManager {
+// NewForwardManager returns a newly initialized instance of ForwardManager.
+// keepAlive is the interval between keepalive requests on the SSH connection, the default one is used if it's zero.
+// concurrency is the maximum number of SSH channels opened at the same time by the forwards, reverse forwards and sockets, the default one is used if it's zero
+func NewForwardManager(ctx context.Context, sshAddr, localInterface, remoteInterface string, pf *k8sforward.PortForwardManager, namespace string, keepAlive time.Duration, concurrency int) *ForwardManager {
+	if concurrency <= 0 {
+		concurrency = defaultForwardConcurrency
+	}
 	return &ForwardManager{
 		ctx:             ctx,
 		localInterface:  localInterface,
 		remoteInterface: remoteInterface,
 		forwards:        make(map[int]*forward),
 		reverses:        make(map[int]*reverse),
+		sockets:         make(map[string]*socketForward),
 		sshAddr:         sshAddr,
 		pf:              pf,
 		namespace:       namespace,
+		keepAlive:       keepAlive,
+		concurrency:     concurrency,
 	}
 }
 
@@ -117,17 +134,15 @@ func (fm *ForwardManager) Start(devPod, namespace string) error {
 	}
 
 	log.Infof("starting SSH connection pool on %s", fm.sshAddr)
-	pool, err := startPool(fm.ctx, fm.sshAddr, c)
+	pool, err := startPool(fm.ctx, fm.sshAddr, c, fm.keepAlive, fm.concurrency)
 	if err != nil {
 		return err
 	}
 
 	fm.pool = pool
 
-	for _, ff := range fm.forwards {
-		ff.pool = pool
-		go ff.start(fm.ctx)
-
+	if _, err := fm.startForwards(); err != nil {
+		return err
 	}
 
 	for _, rt := range fm.reverses {
@@ -135,9 +150,61 @@ func (fm *ForwardManager) Start(devPod, namespace string) error {
 		go rt.start(fm.ctx)
 	}
 
+	for _, sf := range fm.sockets {
+		sf.pool = pool
+		go sf.start(fm.ctx)
+	}
+
 	return nil
 }
 
+// startForwards opens the local listeners of the forwards, serves them as goroutines and returns the status of each one by local port.
+// Forwards that fail to listen are reported and skipped, it only fails if none of the forwards could listen.
+// The SSH channels of the forwards are opened on every local connection, bounded by the concurrency of the pool
+func (fm *ForwardManager) startForwards() (map[int]error, error) {
+	status := make(map[int]error, len(fm.forwards))
+	listeners := make(map[int]net.Listener, len(fm.forwards))
+	for port, ff := range fm.forwards {
+		ff.pool = fm.pool
+		l, err := ff.listen()
+		status[port] = err
+		if err != nil {
+			log.Yellow("Failed to forward local port %d: %s", port, err)
+			continue
+		}
+		listeners[port] = l
+	}
+
+	if len(fm.forwards) > 0 && len(listeners) == 0 {
+		return status, forwardsError(status)
+	}
+
+	for port, l := range listeners {
+		go fm.forwards[port].serve(fm.ctx, l)
+	}
+	return status, nil
+}
+
+// forwardsError returns an error with the local ports that couldn't be forwarded
+func forwardsError(status map[int]error) error {
+	ports := []int{}
+	for port, err := range status {
+		if err != nil {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	sort.Ints(ports)
+
+	messages := []string{}
+	for _, port := range ports {
+		messages = append(messages, fmt.Sprintf("local port %d: %s", port, status[port]))
+	}
+	return fmt.Errorf("failed to forward %s", strings.Join(messages, "; "))
+}
+
 // Stop sends a stop signal to all the connections
 func (fm *ForwardManager) Stop() {
 
@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gliderlabs/ssh"
 	"github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 type testHTTPHandler struct {
@@ -29,8 +33,9 @@ func (t *testSSHHandler) listenAndServe(address string) {
 	server := &ssh.Server{
 		Addr: address,
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip": ssh.DirectTCPIPHandler,
-			"session":      ssh.DefaultSessionHandler,
+			"direct-tcpip":                   ssh.DirectTCPIPHandler,
+			"direct-streamlocal@openssh.com": directStreamLocalHandler,
+			"session":                        ssh.DefaultSessionHandler,
 		},
 		LocalPortForwardingCallback: ssh.LocalPortForwardingCallback(func(ctx ssh.Context, dhost string, dport uint32) bool {
 			log.Println("Accepted forward", dhost, dport)
@@ -61,7 +66,7 @@ func TestForward(t *testing.T) {
 	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
 	ssh := testSSHHandler{}
 	go ssh.listenAndServe(sshAddr)
-	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "")
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "", 0, 0)
 
 	if err := startServers(fm); err != nil {
 		t.Fatal(err)
@@ -102,7 +107,7 @@ func TestReverse(t *testing.T) {
 	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
 	ssh := testSSHHandler{}
 	go ssh.listenAndServe(sshAddr)
-	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "")
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "", 0, 0)
 
 	if err := connectReverseForwards(fm); err != nil {
 		t.Fatal(err)
@@ -122,6 +127,44 @@ func TestReverse(t *testing.T) {
 
 }
 
+func TestKeepAlive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keepAlives int32
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	server := &ssh.Server{
+		Addr: sshAddr,
+		RequestHandlers: map[string]ssh.RequestHandler{
+			"dev.okteto.com/keepalive": func(ctx ssh.Context, srv *ssh.Server, req *gossh.Request) (bool, []byte) {
+				atomic.AddInt32(&keepAlives, 1)
+				return true, nil
+			},
+		},
+	}
+	go server.ListenAndServe()
+	defer server.Close()
+
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "", 100*time.Millisecond, 0)
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+	defer fm.Stop()
+
+	if fm.pool.ka != 100*time.Millisecond {
+		t.Fatalf("wrong keepalive interval: %s", fm.pool.ka)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if atomic.LoadInt32(&keepAlives) < 2 {
+		t.Errorf("expected at least 2 keepalive requests, got %d", atomic.LoadInt32(&keepAlives))
+	}
+}
+
 func startServers(fm *ForwardManager) error {
 	for i := 0; i < 1; i++ {
 		local, err := model.GetAvailablePort(model.Localhost)
@@ -309,7 +352,7 @@ func (fm *ForwardManager) waitForwardsDisconnected() error {
 
 func TestAdd(t *testing.T) {
 
-	pf := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil, "")
+	pf := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil, "", 0, 0)
 	if err := pf.Add(model.Forward{Local: 10010, Remote: 1010}); err != nil {
 		t.Fatal(err)
 	}
@@ -330,3 +373,117 @@ func TestAdd(t *testing.T) {
 		t.Fatalf("expected 'svc:15123', got '%s'", pf.forwards[1012].remoteAddress)
 	}
 }
+
+func TestStartForwards(t *testing.T) {
+	defer func(f func(network, address string) (net.Listener, error)) { listenForward = f }(listenForward)
+
+	listenForward = func(network, address string) (net.Listener, error) {
+		if address == "localhost:20000" {
+			return nil, fmt.Errorf("address already in use")
+		}
+		return net.Listen(network, "localhost:0")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fm := NewForwardManager(ctx, "localhost:22000", model.Localhost, "0.0.0.0", nil, "", 0, 0)
+	total := 30
+	for i := 0; i < total; i++ {
+		port := 20000 + i
+		fm.forwards[port] = &forward{
+			localAddress:  fmt.Sprintf("localhost:%d", port),
+			remoteAddress: fmt.Sprintf("0.0.0.0:%d", port),
+		}
+	}
+
+	status, err := fm.startForwards()
+	if err != nil {
+		t.Fatalf("a single failed forward stopped the rest: %s", err)
+	}
+	if len(status) != total {
+		t.Fatalf("expected the status of %d forwards, got %d", total, len(status))
+	}
+	for port, err := range status {
+		if port == 20000 && err == nil {
+			t.Errorf("expected an error for port %d", port)
+		}
+		if port != 20000 && err != nil {
+			t.Errorf("unexpected error for port %d: %s", port, err)
+		}
+	}
+
+	err = forwardsError(status)
+	if err == nil {
+		t.Fatal("failed forward was not reported")
+	}
+	if err.Error() != "failed to forward local port 20000: address already in use" {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := forwardsError(map[int]error{8080: nil}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestStartForwardsAllFailed(t *testing.T) {
+	defer func(f func(network, address string) (net.Listener, error)) { listenForward = f }(listenForward)
+
+	listenForward = func(network, address string) (net.Listener, error) {
+		return nil, fmt.Errorf("address already in use")
+	}
+
+	fm := NewForwardManager(context.Background(), "localhost:22000", model.Localhost, "0.0.0.0", nil, "", 0, 0)
+	for _, port := range []int{20000, 20001} {
+		fm.forwards[port] = &forward{
+			localAddress:  fmt.Sprintf("localhost:%d", port),
+			remoteAddress: fmt.Sprintf("0.0.0.0:%d", port),
+		}
+	}
+
+	if _, err := fm.startForwards(); err == nil {
+		t.Fatal("didn't fail when none of the forwards could listen")
+	}
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	defer func(f func(*gossh.Client, string, string) (net.Conn, error)) { dialChannel = f }(dialChannel)
+
+	var lock sync.Mutex
+	var inFlight, maxInFlight int
+	dialChannel = func(client *gossh.Client, network, address string) (net.Conn, error) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	concurrency := 3
+	p := &pool{sem: make(chan struct{}, concurrency)}
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, _ = p.get(fmt.Sprintf("0.0.0.0:%d", 20000+i))
+				return
+			}
+			_, _ = p.getUnix("/var/run/docker.sock")
+		}(i)
+	}
+	wg.Wait()
+
+	if maxInFlight > concurrency {
+		t.Errorf("expected at most %d SSH channels opened at the same time, got %d", concurrency, maxInFlight)
+	}
+}
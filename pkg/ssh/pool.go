@@ -25,16 +25,30 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+const defaultKeepAlive = 30 * time.Second
+
 type pool struct {
 	ka      time.Duration
 	client  *ssh.Client
 	stopped bool
+	sem     chan struct{}
+}
+
+// dialChannel opens an SSH channel to the address of the development container
+var dialChannel = func(client *ssh.Client, network, address string) (net.Conn, error) {
+	return client.Dial(network, address)
 }
 
-func startPool(ctx context.Context, serverAddr string, config *ssh.ClientConfig) (*pool, error) {
+// startPool opens the SSH connection to the development container.
+// concurrency is the maximum number of SSH channels being opened at the same time
+func startPool(ctx context.Context, serverAddr string, config *ssh.ClientConfig, keepAlive time.Duration, concurrency int) (*pool, error) {
+	if keepAlive <= 0 {
+		keepAlive = defaultKeepAlive
+	}
 	p := &pool{
-		ka:      30 * time.Second,
+		ka:      keepAlive,
 		stopped: false,
+		sem:     make(chan struct{}, concurrency),
 	}
 
 	var err error
@@ -138,12 +152,24 @@ func (p *pool) keepAlive(ctx context.Context) {
 }
 
 func (p *pool) get(address string) (net.Conn, error) {
-	c, err := p.client.Dial("tcp", address)
-	return c, err
+	return p.dial("tcp", address)
+}
+
+func (p *pool) getUnix(path string) (net.Conn, error) {
+	return p.dial("unix", path)
+}
+
+// dial opens an SSH channel, waiting while there are too many channels being opened
+func (p *pool) dial(network, address string) (net.Conn, error) {
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+	return dialChannel(p.client, network, address)
 }
 
 func (p *pool) getListener(address string) (net.Listener, error) {
+	p.sem <- struct{}{}
 	l, err := p.client.Listen("tcp", address)
+	<-p.sem
 	if err != nil {
 		return nil, fmt.Errorf("failed to start ssh listener on %s: %w", address, err)
 	}
@@ -0,0 +1,139 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// localSocketMode restricts the local socket to the current user
+const localSocketMode os.FileMode = 0600
+
+type socketForward struct {
+	forward
+}
+
+// AddSocket adds a unix socket forward
+func (fm *ForwardManager) AddSocket(f model.SocketForward) error {
+	if _, ok := fm.sockets[f.Local]; ok {
+		return fmt.Errorf("socket %s is listed multiple times, please check your sockets configuration", f.Local)
+	}
+
+	fm.sockets[f.Local] = &socketForward{
+		forward: forward{
+			localAddress:  f.Local,
+			remoteAddress: f.Remote,
+		},
+	}
+
+	return nil
+}
+
+func (s *socketForward) start(ctx context.Context) {
+	if err := removeStaleSocket(s.localAddress); err != nil {
+		log.Infof("%s -> %s", s.String(), err)
+		return
+	}
+
+	localListener, err := net.Listen("unix", s.localAddress)
+	if err != nil {
+		log.Infof("%s -> failed to listen: %s", s.String(), err)
+		return
+	}
+
+	if err := os.Chmod(s.localAddress, localSocketMode); err != nil {
+		log.Infof("%s -> failed to set socket permissions: %s", s.String(), err)
+		localListener.Close()
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.setDisconnected()
+		if err := localListener.Close(); err != nil {
+			log.Infof("%s -> failed to close: %s", s.String(), err)
+		}
+		if err := removeStaleSocket(s.localAddress); err != nil {
+			log.Infof("%s -> %s", s.String(), err)
+		}
+		log.Infof("%s -> done", s.String())
+	}()
+
+	s.setConnected()
+
+	for {
+		log.Infof("%s -> listening for local connections", s.String())
+		localConn, err := localListener.Accept()
+		if err != nil {
+			if !s.connected() {
+				return
+			}
+
+			log.Infof("%s -> failed to accept connection: %v", s.String(), err)
+			continue
+		}
+		go s.handle(localConn)
+	}
+}
+
+func (s *socketForward) handle(local net.Conn) {
+	defer local.Close()
+
+	remote, err := s.pool.getUnix(s.remoteAddress)
+	if err != nil {
+		log.Infof("%s -> failed to dial remote socket: %s", s.String(), err)
+		return
+	}
+
+	defer remote.Close()
+
+	quit := make(chan struct{}, 1)
+
+	go s.transfer(remote, local, quit)
+	go s.transfer(local, remote, quit)
+
+	<-quit
+}
+
+func (s *socketForward) String() string {
+	return fmt.Sprintf("ssh socket forward %s->%s", s.localAddress, s.remoteAddress)
+}
+
+// removeStaleSocket deletes a socket file left behind by a previous session.
+// It refuses to delete anything that is not a socket.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to check local socket: %w", err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("'%s' already exists and is not a socket", path)
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove local socket: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/okteto/okteto/pkg/model"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// directStreamLocalHandler serves "direct-streamlocal@openssh.com" channels for the test ssh server
+func directStreamLocalHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	payload := struct {
+		SocketPath string
+		Reserved0  string
+		Reserved1  uint32
+	}{}
+
+	if err := gossh.Unmarshal(newChan.ExtraData(), &payload); err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, "error parsing streamlocal data: "+err.Error())
+		return
+	}
+
+	remote, err := net.Dial("unix", payload.SocketPath)
+	if err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, err.Error())
+		return
+	}
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		remote.Close()
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+
+	go func() {
+		defer ch.Close()
+		defer remote.Close()
+		_, _ = io.Copy(ch, remote)
+	}()
+	go func() {
+		defer ch.Close()
+		defer remote.Close()
+		_, _ = io.Copy(remote, ch)
+	}()
+}
+
+func TestSocketForward(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sshPort, err := model.GetAvailablePort(model.Localhost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "okteto-socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	localSocket := filepath.Join(dir, "local.sock")
+	remoteSocket := filepath.Join(dir, "remote.sock")
+
+	remoteListener, err := net.Listen("unix", remoteSocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remoteListener.Close()
+	go func() {
+		_ = http.Serve(remoteListener, &testHTTPHandler{message: "socket"})
+	}()
+
+	sshAddr := fmt.Sprintf("localhost:%d", sshPort)
+	ssh := testSSHHandler{}
+	go ssh.listenAndServe(sshAddr)
+	fm := NewForwardManager(ctx, sshAddr, model.Localhost, "0.0.0.0", nil, "", 0, 0)
+
+	if err := fm.AddSocket(model.SocketForward{Local: localSocket, Remote: remoteSocket}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.Start("", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := waitSocketsConnected(fm, true); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(localSocket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != localSocketMode {
+		t.Errorf("local socket mode: expected %s, got %s", localSocketMode, info.Mode().Perm())
+	}
+
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", localSocket)
+			},
+		},
+	}
+	r, err := client.Get("http://socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "socket" {
+		t.Errorf("got: %s, expected: socket", string(body))
+	}
+
+	cancel()
+	fm.Stop()
+	if err := waitSocketsConnected(fm, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := waitSocketRemoved(localSocket); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAddSocket(t *testing.T) {
+	fm := NewForwardManager(context.Background(), "0.0.0.0:22000", "0.0.0.0", "0.0.0.0", nil, "", 0, 0)
+	if err := fm.AddSocket(model.SocketForward{Local: "/tmp/a.sock", Remote: "/var/run/a.sock"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.AddSocket(model.SocketForward{Local: "/tmp/a.sock", Remote: "/var/run/b.sock"}); err == nil {
+		t.Fatal("duplicated local socket didn't return an error")
+	}
+
+	if fm.sockets["/tmp/a.sock"].remoteAddress != "/var/run/a.sock" {
+		t.Fatalf("expected '/var/run/a.sock', got '%s'", fm.sockets["/tmp/a.sock"].remoteAddress)
+	}
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "okteto-socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("missing socket returned an error: %s", err)
+	}
+
+	regular := filepath.Join(dir, "regular")
+	if err := ioutil.WriteFile(regular, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeStaleSocket(regular); err == nil {
+		t.Error("regular file didn't return an error")
+	}
+	if _, err := os.Stat(regular); err != nil {
+		t.Errorf("regular file was removed: %s", err)
+	}
+
+	stale := filepath.Join(dir, "stale.sock")
+	l, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	if err := removeStaleSocket(stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale socket was not removed")
+	}
+}
+
+func waitSocketsConnected(fm *ForwardManager, connected bool) error {
+	tk := time.NewTicker(100 * time.Millisecond)
+	defer tk.Stop()
+	for i := 0; i < 100; i++ {
+		done := true
+		for _, s := range fm.sockets {
+			done = done && s.connected() == connected
+		}
+
+		if done {
+			return nil
+		}
+		<-tk.C
+	}
+
+	return fmt.Errorf("sockets connected state is not %t", connected)
+}
+
+func waitSocketRemoved(path string) error {
+	tk := time.NewTicker(100 * time.Millisecond)
+	defer tk.Stop()
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+		<-tk.C
+	}
+
+	return fmt.Errorf("local socket %s was not removed", path)
+}
@@ -32,6 +32,9 @@ import (
 const (
 	syncthingVersion       = "1.14.0"
 	syncthingVersionEnvVar = "OKTETO_SYNCTHING_VERSION"
+
+	//syncthingVersionCheckEnvVar configures what happens when the local and remote syncthing versions are not compatible: "error" (default), "warn" or "skip"
+	syncthingVersionCheckEnvVar = "OKTETO_SYNCTHING_VERSION_CHECK"
 )
 
 var (
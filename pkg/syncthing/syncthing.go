@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -38,6 +39,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/google/uuid"
 	gops "github.com/mitchellh/go-ps"
 	"github.com/shirou/gopsutil/process"
@@ -94,6 +96,7 @@ type Syncthing struct {
 	pid              int          `yaml:"-"`
 	RescanInterval   string       `yaml:"-"`
 	Compression      string       `yaml:"-"`
+	CopyOwnership    bool         `yaml:"-"`
 }
 
 //Folder represents a sync folder
@@ -101,9 +104,27 @@ type Folder struct {
 	Name         string `yaml:"name"`
 	LocalPath    string `yaml:"localPath"`
 	RemotePath   string `yaml:"remotePath"`
-	Retries      int    `yaml:"-"`
-	SentStIgnore bool   `yaml:"-"`
-	Overwritten  bool   `yaml:"-"`
+	Retries      int      `yaml:"-"`
+	SentStIgnore bool     `yaml:"-"`
+	Overwritten  bool     `yaml:"-"`
+	Overlays     []string `yaml:"-"`
+	Mode         string   `yaml:"-"`
+}
+
+//LocalType returns the syncthing type of the local folder
+func (f *Folder) LocalType(defaultType string) string {
+	if f.Mode == model.SyncModeSendOnly {
+		return "sendonly"
+	}
+	return defaultType
+}
+
+//RemoteType returns the syncthing type of the remote folder
+func (f *Folder) RemoteType() string {
+	if f.Mode == model.SyncModeSendOnly {
+		return "receiveonly"
+	}
+	return "sendreceive"
 }
 
 //Ignores represents the .stignore file
@@ -127,6 +148,17 @@ type Completion struct {
 	NeedDeletes int64   `json:"needDeletes"`
 }
 
+// Connections represents the connections of syncthing.
+type Connections struct {
+	Total ConnectionStats `json:"total"`
+}
+
+// ConnectionStats represents the bytes transferred by syncthing connections.
+type ConnectionStats struct {
+	InBytesTotal  int64 `json:"inBytesTotal"`
+	OutBytesTotal int64 `json:"outBytesTotal"`
+}
+
 // FolderErrors represents folder errors in syncthing.
 type FolderErrors struct {
 	Data DataFolderErrors `json:"data"`
@@ -156,6 +188,11 @@ type DownloadProgressData struct {
 	BytesTotal int64 `json:"bytesTotal"`
 }
 
+// SystemVersion represents the version reported by syncthing.
+type SystemVersion struct {
+	Version string `json:"version"`
+}
+
 // New constructs a new Syncthing.
 func New(dev *model.Dev) (*Syncthing, error) {
 	fullPath := getInstallPath()
@@ -213,6 +250,7 @@ func New(dev *model.Dev) (*Syncthing, error) {
 		Folders:          []*Folder{},
 		RescanInterval:   strconv.Itoa(dev.Sync.RescanInterval),
 		Compression:      compression,
+		CopyOwnership:    dev.RunsAsRoot(),
 	}
 	index := 1
 	for _, sync := range dev.Sync.Folders {
@@ -227,6 +265,8 @@ func New(dev *model.Dev) (*Syncthing, error) {
 					Name:       strconv.Itoa(index),
 					LocalPath:  sync.LocalPath,
 					RemotePath: sync.RemotePath,
+					Overlays:   getOverlayIgnores(dev, sync),
+					Mode:       sync.GetMode(),
 				},
 			)
 			index++
@@ -341,6 +381,58 @@ func (s *Syncthing) Ping(ctx context.Context, local bool) bool {
 	return false
 }
 
+//GetVersion returns the version of the local or remote syncthing
+func (s *Syncthing) GetVersion(ctx context.Context, local bool) (*semver.Version, error) {
+	body, err := s.APICall(ctx, "rest/system/version", "GET", 200, nil, local, nil, true, 3)
+	if err != nil {
+		return nil, err
+	}
+	v := &SystemVersion{}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal syncthing version local=%t: %w", local, err)
+	}
+	version, err := semver.NewVersion(v.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse syncthing version '%s' local=%t: %w", v.Version, local, err)
+	}
+	return version, nil
+}
+
+//CheckVersions returns an error if the local and remote syncthing versions don't speak the same protocol
+func (s *Syncthing) CheckVersions(ctx context.Context) error {
+	mode := os.Getenv(syncthingVersionCheckEnvVar)
+	if mode == "skip" {
+		return nil
+	}
+
+	localVersion, err := s.GetVersion(ctx, true)
+	if err != nil {
+		log.Infof("failed to get the local syncthing version: %s", err)
+		return nil
+	}
+	remoteVersion, err := s.GetVersion(ctx, false)
+	if err != nil {
+		log.Infof("failed to get the remote syncthing version: %s", err)
+		return nil
+	}
+	log.Infof("syncthing versions: local=%s remote=%s", localVersion.String(), remoteVersion.String())
+
+	if localVersion.Major() == remoteVersion.Major() {
+		return nil
+	}
+
+	if mode == "warn" {
+		log.Warning("The local synchronization service version %s is not compatible with the remote version %s", localVersion.String(), remoteVersion.String())
+		return nil
+	}
+
+	return errors.UserError{
+		E: fmt.Errorf("the local synchronization service version %s is not compatible with the remote version %s", localVersion.String(), remoteVersion.String()),
+		Hint: fmt.Sprintf(`Upgrade the Okteto CLI and the init container image of your okteto manifest to use the same syncthing version.
+    Set '%s=warn' to continue anyway`, syncthingVersionCheckEnvVar),
+	}
+}
+
 //SendStignoreFile sends .stignore from local to remote
 func (s *Syncthing) SendStignoreFile(ctx context.Context) {
 	for _, folder := range s.Folders {
@@ -370,6 +462,11 @@ func (s *Syncthing) SendStignoreFile(ctx context.Context) {
 			}
 			ignores.Ignore[i] = fmt.Sprintf("(?d)%s", line)
 		}
+		for _, overlay := range folder.Overlays {
+			if !contains(ignores.Ignore, overlay) {
+				ignores.Ignore = append(ignores.Ignore, overlay)
+			}
+		}
 		body, err = json.Marshal(ignores)
 		if err != nil {
 			log.Infof("error marshalling ignore files: %s", err.Error())
@@ -384,6 +481,29 @@ func (s *Syncthing) SendStignoreFile(ctx context.Context) {
 	}
 }
 
+//getOverlayIgnores returns the ignore patterns of the overlays mounted over a sync folder
+func getOverlayIgnores(dev *model.Dev, sync model.SyncFolder) []string {
+	result := []string{}
+	for _, overlay := range dev.Overlays {
+		folder := dev.GetSyncFolderForOverlay(overlay)
+		if folder == nil || *folder != sync {
+			continue
+		}
+		rel := strings.TrimPrefix(path.Clean(overlay), path.Clean(sync.RemotePath))
+		result = append(result, rel)
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 //ResetDatabase resets the syncthing database
 func (s *Syncthing) ResetDatabase(ctx context.Context, dev *model.Dev) error {
 
@@ -629,6 +749,21 @@ func (s *Syncthing) GetCompletion(ctx context.Context, local bool, device string
 	return completion, nil
 }
 
+// GetTransferredBytes returns the bytes received and sent by syncthing since it started
+func (s *Syncthing) GetTransferredBytes(ctx context.Context, local bool) (int64, error) {
+	connections := &Connections{}
+	body, err := s.APICall(ctx, "rest/system/connections", "GET", 200, nil, local, nil, true, 3)
+	if err != nil {
+		log.Infof("error calling 'rest/system/connections' local=%t syncthing API: %s", local, err)
+		return 0, err
+	}
+	if err := json.Unmarshal(body, connections); err != nil {
+		log.Infof("error unmarshalling 'rest/system/connections' local=%t syncthing API: %s", local, err)
+		return 0, err
+	}
+	return connections.Total.InBytesTotal + connections.Total.OutBytesTotal, nil
+}
+
 // GetCompletionProgress returns the syncthing completion progress
 func (s *Syncthing) GetCompletionProgress(ctx context.Context, local bool) (float64, error) {
 	device := DefaultRemoteDeviceID
@@ -14,10 +14,20 @@
 package syncthing
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
 )
 
 func TestGetFiles(t *testing.T) {
@@ -45,3 +55,126 @@ func TestGetFiles(t *testing.T) {
 		t.Errorf("got %s, expected %s", info, expected)
 	}
 }
+
+func Test_getOverlayIgnores(t *testing.T) {
+	dev := &model.Dev{
+		Sync: model.Sync{
+			Folders: []model.SyncFolder{
+				{LocalPath: "/src/api", RemotePath: "/app"},
+				{LocalPath: "/src/web", RemotePath: "/web"},
+			},
+		},
+		Overlays: []string{"/app/build", "/web/dist/", "/app/node_modules"},
+	}
+
+	result := getOverlayIgnores(dev, dev.Sync.Folders[0])
+	expected := []string{"/build", "/node_modules"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v, expected %v", result, expected)
+	}
+
+	result = getOverlayIgnores(dev, dev.Sync.Folders[1])
+	expected = []string{"/dist"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v, expected %v", result, expected)
+	}
+}
+
+func TestConfigSyncModes(t *testing.T) {
+	s := &Syncthing{
+		Type: "sendreceive",
+		Folders: []*Folder{
+			{Name: "1", LocalPath: "/src/app", Mode: model.SyncModeSendReceive},
+			{Name: "2", LocalPath: "/src/config", Mode: model.SyncModeSendOnly},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := configTemplate.Execute(buf, s); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`<folder id="okteto-1" label="1" path="/src/app" type="sendreceive"`,
+		`<folder id="okteto-2" label="2" path="/src/config" type="sendonly"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(buf.String(), e) {
+			t.Errorf("'%s' not found in config.xml", e)
+		}
+	}
+}
+
+func newFakeSyncthing(t *testing.T, version string) string {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/system/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"arch":"amd64","os":"linux","version":"%s"}`, version)
+	}))
+	t.Cleanup(s.Close)
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func TestCheckVersions(t *testing.T) {
+	var tests = []struct {
+		name      string
+		local     string
+		remote    string
+		mode      string
+		expectErr bool
+	}{
+		{name: "same-version", local: "v1.14.0", remote: "v1.14.0"},
+		{name: "compatible-minor", local: "v1.14.0", remote: "v1.2.2"},
+		{name: "incompatible-major", local: "v1.14.0", remote: "v2.0.0", expectErr: true},
+		{name: "incompatible-major-warn", local: "v1.14.0", remote: "v2.0.0", mode: "warn"},
+		{name: "incompatible-major-skip", local: "v1.14.0", remote: "v2.0.0", mode: "skip"},
+		{name: "unknown-version", local: "v1.14.0", remote: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(syncthingVersionCheckEnvVar, tt.mode)
+			defer os.Unsetenv(syncthingVersionCheckEnvVar)
+
+			s := &Syncthing{
+				Client:           NewAPIClient(),
+				GUIAddress:       newFakeSyncthing(t, tt.local),
+				RemoteGUIAddress: newFakeSyncthing(t, tt.remote),
+			}
+			err := s.CheckVersions(context.Background())
+			if tt.expectErr {
+				if _, ok := err.(errors.UserError); !ok {
+					t.Fatalf("expected a user error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGetTransferredBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/system/connections" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"connections":{},"total":{"inBytesTotal":100,"outBytesTotal":2048}}`)
+	}))
+	defer server.Close()
+
+	s := &Syncthing{
+		Client:     NewAPIClient(),
+		GUIAddress: strings.TrimPrefix(server.URL, "http://"),
+	}
+	bytes, err := s.GetTransferredBytes(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes != 2148 {
+		t.Errorf("expected 2148 transferred bytes, got %d", bytes)
+	}
+}